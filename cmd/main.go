@@ -4,25 +4,39 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
 	"os"
 	"os/signal"
+	"path/filepath"
 	"syscall"
 	"time"
 
 	"github.com/charmbracelet/log"
 	cssh "github.com/charmbracelet/ssh"
 
+	"github.com/kirkegaard/terminal-pet/pkg/achievements"
+	"github.com/kirkegaard/terminal-pet/pkg/actions"
+	"github.com/kirkegaard/terminal-pet/pkg/api"
 	"github.com/kirkegaard/terminal-pet/pkg/config"
 	"github.com/kirkegaard/terminal-pet/pkg/db"
+	"github.com/kirkegaard/terminal-pet/pkg/db/repo"
+	"github.com/kirkegaard/terminal-pet/pkg/events"
+	"github.com/kirkegaard/terminal-pet/pkg/history"
+	"github.com/kirkegaard/terminal-pet/pkg/pet"
 	"github.com/kirkegaard/terminal-pet/pkg/ssh"
+	"github.com/kirkegaard/terminal-pet/pkg/stats"
+	"github.com/kirkegaard/terminal-pet/pkg/world"
 )
 
 type Server struct {
-	SSHServer *ssh.SSHServer
-	DB        *db.DB
-	Config    *config.Config
-	logger    *log.Logger
-	ctx       context.Context
+	SSHServer  *ssh.SSHServer
+	HTTPServer *api.HTTPServer
+	World      *world.Ticker
+	Stats      *stats.Recorder
+	DB         *db.DB
+	Config     *config.Config
+	logger     *log.Logger
+	ctx        context.Context
 }
 
 func NewServer(ctx context.Context) (*Server, error) {
@@ -33,12 +47,107 @@ func NewServer(ctx context.Context) (*Server, error) {
 		log.Fatal("Config not found in context")
 	}
 
+	dbx, err := openDatabase(ctx, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	// Bring the database schema up to date
+	applied, err := dbx.Migrate(ctx)
+	if err != nil {
+		log.Error("Could not migrate database", "error", err)
+		return nil, fmt.Errorf("migrate database: %w", err)
+	}
+	log.Info("Database schema up to date", "applied_migrations", applied)
+
+	// Create a new context with the database
+	dbCtx := db.WithContext(ctx, dbx)
+
+	s := &Server{
+		Config: cfg,
+		DB:     dbx,
+		ctx:    dbCtx,
+	}
+
+	if cfg.Game.FoodCatalog != "" {
+		foods, err := pet.LoadFoodCatalog(cfg.Game.FoodCatalog)
+		if err != nil {
+			return nil, fmt.Errorf("load food catalog: %w", err)
+		}
+		pet.SetFoods(foods)
+		log.Info("Loaded food catalog", "path", cfg.Game.FoodCatalog, "foods", len(foods.All()))
+	}
+
+	userStore := repo.NewUserRepository(dbx)
+	petStore := repo.NewPetRepository(dbx)
+	tokenStore := repo.NewTokenRepository(dbx)
+	inventoryStore := repo.NewInventoryRepository(dbx)
+	scoreStore := repo.NewScoreRepository(dbx)
+	historyStore := repo.NewPetEventRepository(dbx)
+	statStore := repo.NewStatRepository(dbx)
+	lineageStore := repo.NewLineageRepository(dbx)
+	friendStore := repo.NewFriendRepository(dbx)
+	giftStore := repo.NewGiftRepository(dbx)
+
+	// Everything that happens to pets is published on the bus. The activity
+	// log subscribes first, so events are logged before what they unlock.
+	bus := events.NewBus()
+	bus.Subscribe(history.NewLogger(historyStore).Handle)
+	achievementEngine := achievements.NewEngine(repo.NewAchievementRepository(dbx), bus, pet.SystemClock)
+	achievementEngine.Subscribe()
+
+	// The live sessions, shared with the world ticker so it leaves the pets
+	// being played to them, and with the action service so actions from
+	// commands and the HTTP API go to the session playing the pet
+	sessions := ssh.NewRegistry()
+	service := actions.NewService(petStore, inventoryStore, bus, sessions, pet.SystemClock)
+
+	s.SSHServer, err = ssh.NewSSHServer(dbCtx, ssh.Deps{
+		Pets:         petStore,
+		Users:        userStore,
+		Tokens:       tokenStore,
+		Scores:       scoreStore,
+		History:      historyStore,
+		Stats:        statStore,
+		Lineage:      lineageStore,
+		Friends:      friendStore,
+		Gifts:        giftStore,
+		Achievements: achievementEngine,
+		Actions:      service,
+		Sessions:     sessions,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("create ssh server: %w", err)
+	}
+
+	// The HTTP API is optional
+	if cfg.HTTP.ListenAddr != "" {
+		s.HTTPServer, err = api.NewHTTPServer(dbCtx, service, tokenStore, userStore)
+		if err != nil {
+			return nil, fmt.Errorf("create http server: %w", err)
+		}
+	}
+
+	// Start the world ticker so pets keep aging while nobody is connected
+	s.World = world.NewTicker(petStore, bus, pet.NewSimulator(pet.SystemClock, nil), sessions, cfg.World.TickInterval)
+	s.World.Start(dbCtx)
+
+	// Snapshot the stats of all pets for the stats screen
+	s.Stats = stats.NewRecorder(petStore, statStore, cfg.World.StatsInterval)
+	s.Stats.Start(dbCtx)
+
+	return s, nil
+}
+
+func openDatabase(ctx context.Context, cfg *config.Config) (*db.DB, error) {
 	log.Info("Setting up database", "driver", cfg.DB.Driver, "data_source", cfg.DB.DataSource)
 
-	// Create directory for database
-	dbDir := "./tmp"
-	if err := os.MkdirAll(dbDir, 0755); err != nil {
-		return nil, fmt.Errorf("create database directory: %w", err)
+	// Create directory for the SQLite database file
+	if cfg.DB.Driver == db.DriverSQLite {
+		dbDir := filepath.Dir(cfg.DB.DataSource)
+		if err := os.MkdirAll(dbDir, 0755); err != nil {
+			return nil, fmt.Errorf("create database directory: %w", err)
+		}
 	}
 
 	// Open database connection
@@ -54,27 +163,43 @@ func NewServer(ctx context.Context) (*Server, error) {
 		return nil, fmt.Errorf("ping database: %w", err)
 	}
 
-	// Initialize database schema
-	if err := dbx.CreateTables(ctx); err != nil {
-		log.Error("Could not create database tables", "error", err)
-		return nil, fmt.Errorf("create database tables: %w", err)
+	return dbx, nil
+}
+
+// runMigrate handles the `migrate status` and `migrate up` admin commands
+func runMigrate(ctx context.Context, cfg *config.Config, args []string) error {
+	if len(args) != 1 || (args[0] != "status" && args[0] != "up") {
+		return fmt.Errorf("usage: %s migrate status|up", os.Args[0])
 	}
 
-	// Create a new context with the database
-	dbCtx := db.WithContext(ctx, dbx)
+	dbx, err := openDatabase(ctx, cfg)
+	if err != nil {
+		return err
+	}
+	defer dbx.Close()
 
-	s := &Server{
-		Config: cfg,
-		DB:     dbx,
-		ctx:    dbCtx,
+	if args[0] == "up" {
+		applied, err := dbx.Migrate(ctx)
+		if err != nil {
+			return fmt.Errorf("migrate database: %w", err)
+		}
+		fmt.Printf("Applied %d migration(s)\n", applied)
 	}
 
-	s.SSHServer, err = ssh.NewSSHServer(dbCtx)
+	statuses, err := dbx.MigrationStatus(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("create ssh server: %w", err)
+		return fmt.Errorf("get migration status: %w", err)
 	}
 
-	return s, nil
+	for _, status := range statuses {
+		state := "pending"
+		if status.Applied {
+			state = "applied " + status.AppliedAt.Format(time.RFC3339)
+		}
+		fmt.Printf("%04d %-40s %s\n", status.Version, status.Name, state)
+	}
+
+	return nil
 }
 
 func main() {
@@ -92,15 +217,30 @@ func main() {
 		log.Warn("Failed to parse environment variables", "error", err)
 	}
 
+	if err := cfg.Validate(); err != nil {
+		log.Fatal("Invalid configuration", "error", err)
+	}
+
 	log.Info("Configuration loaded",
 		"ssh_listen", cfg.SSH.ListenAddr,
 		"ssh_url", cfg.SSH.PublicURL,
+		"http_listen", cfg.HTTP.ListenAddr,
 		"db_driver", cfg.DB.Driver,
-		"db_source", cfg.DB.DataSource)
+		"db_source", cfg.DB.DataSource,
+		"world_tick_interval", cfg.World.TickInterval,
+		"world_stats_interval", cfg.World.StatsInterval)
 
 	// Set the config in the context
 	ctx = config.WithContext(ctx, cfg)
 
+	// Admin commands
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrate(ctx, cfg, os.Args[2:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
 	// Create server
 	s, err := NewServer(ctx)
 	if err != nil {
@@ -120,12 +260,36 @@ func main() {
 		}
 	}()
 
+	if s.HTTPServer != nil {
+		log.Info("Starting HTTP server", "address", s.Config.HTTP.ListenAddr)
+
+		go func() {
+			if err := s.HTTPServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				log.Error("Could not start http server", "error", err)
+				done <- nil
+			}
+		}()
+	}
+
 	<-done
 
+	log.Info("Stopping world ticker")
+	s.World.Stop()
+
+	log.Info("Stopping stats recorder")
+	s.Stats.Stop()
+
 	log.Info("Stopping SSH server")
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer func() { cancel() }()
 	if err := s.SSHServer.Shutdown(ctx); err != nil && !errors.Is(err, cssh.ErrServerClosed) {
 		log.Error("Could not stop server", "error", err)
 	}
+
+	if s.HTTPServer != nil {
+		log.Info("Stopping HTTP server")
+		if err := s.HTTPServer.Shutdown(ctx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Error("Could not stop http server", "error", err)
+		}
+	}
 }
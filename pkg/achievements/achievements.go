@@ -0,0 +1,157 @@
+// Package achievements unlocks badges for players as things happen to their
+// pets. Achievements are declared in the list below: each one matches the
+// pet events that count towards it and says how many such events it takes.
+// The engine subscribes to the event bus to hear about them.
+package achievements
+
+import (
+	"time"
+
+	"github.com/kirkegaard/terminal-pet/pkg/pet"
+)
+
+// Achievement describes a badge and what unlocks it
+type Achievement struct {
+	ID          string
+	Name        string
+	Emoji       string
+	Description string
+
+	// When matches the events that count towards the achievement
+	When func(e pet.Event) bool
+	// Goal is how many events it takes, 0 and 1 both unlock on the first
+	Goal int
+}
+
+// Unlocked is published when an event unlocked an achievement for the
+// owner of its pet
+type Unlocked struct {
+	pet.EventInfo
+	Achievement Achievement
+}
+
+// all lists every achievement in the order they are shown
+var all = []Achievement{
+	{
+		ID:          "first_meal",
+		Name:        "First Meal",
+		Emoji:       "🍼",
+		Description: "Feed a pet for the first time",
+		When:        on[pet.Fed](nil),
+	},
+	{
+		ID:          "grown_up",
+		Name:        "All Grown Up",
+		Emoji:       "🎓",
+		Description: "Raise a pet to adulthood",
+		When:        reachedStage(pet.StageAdult),
+	},
+	{
+		ID:          "survivor",
+		Name:        "Survivor",
+		Emoji:       "📅",
+		Description: "Keep a pet alive for 30 days",
+		When:        aliveFor(30 * 24 * time.Hour),
+	},
+	{
+		ID:          "mind_reader",
+		Name:        "Mind Reader",
+		Emoji:       "🔮",
+		Description: "Score 5/5 in Higher or Lower",
+		When:        perfectGame("higherlower"),
+	},
+	{
+		ID:          "pooper_scooper",
+		Name:        "Pooper Scooper",
+		Emoji:       "🧹",
+		Description: "Clean up 100 poops",
+		When:        on[pet.Cleaned](nil),
+		Goal:        100,
+	},
+	{
+		ID:          "too_many_treats",
+		Name:        "Too Many Treats",
+		Emoji:       "🍰",
+		Description: "Lose a pet to obesity",
+		When:        diedOf(pet.CauseObesity),
+	},
+	{
+		ID:          "social_butterfly",
+		Name:        "Social Butterfly",
+		Emoji:       "🦋",
+		Description: "Play 10 games on playdates",
+		When:        on[pet.HadPlaydate](nil),
+		Goal:        10,
+	},
+	{
+		ID:          "proud_parent",
+		Name:        "Proud Parent",
+		Emoji:       "🥚",
+		Description: "Have a baby",
+		When:        on[pet.HadBaby](nil),
+	},
+}
+
+// All returns every achievement in the order they are shown
+func All() []Achievement {
+	return all
+}
+
+// Get returns the achievement with the given ID
+func Get(id string) (Achievement, bool) {
+	for _, a := range all {
+		if a.ID == id {
+			return a, true
+		}
+	}
+
+	return Achievement{}, false
+}
+
+// goal returns how many events it takes to unlock the achievement
+func (a Achievement) goal() int {
+	return max(a.Goal, 1)
+}
+
+// counts reports whether the event counts towards the achievement
+func (a Achievement) counts(e pet.Event) bool {
+	return a.When != nil && a.When(e)
+}
+
+// on matches events of type E that meet the condition, nil for none
+func on[E pet.Event](cond func(E) bool) func(pet.Event) bool {
+	return func(e pet.Event) bool {
+		typed, ok := e.(E)
+		return ok && (cond == nil || cond(typed))
+	}
+}
+
+// reachedStage matches pets that grew into the given life stage
+func reachedStage(stage string) func(pet.Event) bool {
+	return on(func(e pet.StageChanged) bool {
+		return e.To == stage
+	})
+}
+
+// aliveFor matches any event of a living pet at least as old as the given
+// age when it happened
+func aliveFor(age time.Duration) func(pet.Event) bool {
+	return func(e pet.Event) bool {
+		p := e.EventPet()
+		return !p.IsDead() && p.Lifetime(e.EventTime()) >= age
+	}
+}
+
+// perfectGame matches the maximum score in the given game
+func perfectGame(game string) func(pet.Event) bool {
+	return on(func(e pet.Played) bool {
+		return e.Game == game && e.MaxScore > 0 && e.Score >= e.MaxScore
+	})
+}
+
+// diedOf matches pets that died of the given cause
+func diedOf(cause string) func(pet.Event) bool {
+	return on(func(e pet.Died) bool {
+		return e.Cause == cause
+	})
+}
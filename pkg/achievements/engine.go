@@ -0,0 +1,161 @@
+package achievements
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/kirkegaard/terminal-pet/pkg/db/repo"
+	"github.com/kirkegaard/terminal-pet/pkg/events"
+	"github.com/kirkegaard/terminal-pet/pkg/pet"
+)
+
+// Engine counts events towards the achievements of the pets' owners and
+// unlocks them. A nil Engine ignores every event.
+type Engine struct {
+	store repo.AchievementStore
+	bus   *events.Bus
+	clock pet.Clock
+}
+
+// NewEngine creates an engine that keeps progress in the given store and
+// publishes Unlocked on the bus for every achievement it unlocks
+func NewEngine(store repo.AchievementStore, bus *events.Bus, clock pet.Clock) *Engine {
+	if clock == nil {
+		clock = pet.SystemClock
+	}
+
+	return &Engine{
+		store: store,
+		bus:   bus,
+		clock: clock,
+	}
+}
+
+// Subscribe makes the engine count every event published on its bus until
+// the returned function is called
+func (e *Engine) Subscribe() (unsubscribe func()) {
+	if e == nil {
+		return func() {}
+	}
+
+	return e.bus.Subscribe(e.Handle)
+}
+
+// Handle counts an event towards the achievements of the owner of its pet
+// and publishes the achievements it unlocked
+func (e *Engine) Handle(ctx context.Context, ev pet.Event) {
+	if _, ok := ev.(Unlocked); ok {
+		return
+	}
+
+	unlocks, err := e.Notify(ctx, ev)
+	if err != nil {
+		log.Error("Error recording achievements", "pet_id", ev.EventPet().ID, "error", err)
+	}
+
+	for _, a := range unlocks {
+		e.bus.Publish(ctx, Unlocked{
+			EventInfo:   pet.EventInfo{Pet: ev.EventPet(), At: e.clock.Now()},
+			Achievement: a,
+		})
+	}
+}
+
+// Notify counts the events towards the achievements of the owner of their
+// pet and returns the achievements they unlocked. Every event must be about
+// a pet of the same owner.
+func (e *Engine) Notify(ctx context.Context, events ...pet.Event) ([]Achievement, error) {
+	if e == nil || len(events) == 0 || events[0].EventPet().Parent == nil {
+		return nil, nil
+	}
+
+	userID := events[0].EventPet().Parent.ID
+
+	progress, err := e.store.List(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list achievements: %w", err)
+	}
+
+	unlocked := make(map[string]bool)
+	for _, p := range progress {
+		unlocked[p.AchievementID] = p.UnlockedAt.Valid
+	}
+
+	var unlocks []Achievement
+	for _, ev := range events {
+		for _, a := range all {
+			if unlocked[a.ID] || !a.counts(ev) {
+				continue
+			}
+
+			if a.goal() > 1 {
+				count, err := e.store.AddProgress(ctx, userID, a.ID, 1)
+				if err != nil {
+					return unlocks, fmt.Errorf("add progress to %s: %w", a.ID, err)
+				}
+				if count < a.goal() {
+					continue
+				}
+			}
+
+			ok, err := e.store.Unlock(ctx, userID, a.ID, e.clock.Now())
+			if err != nil {
+				return unlocks, fmt.Errorf("unlock %s: %w", a.ID, err)
+			}
+
+			unlocked[a.ID] = true
+			if ok {
+				unlocks = append(unlocks, a)
+			}
+		}
+	}
+
+	return unlocks, nil
+}
+
+// Status is the progress of a player towards an achievement
+type Status struct {
+	Achievement
+	Progress   int
+	UnlockedAt time.Time
+}
+
+// Unlocked reports whether the player unlocked the achievement
+func (s Status) Unlocked() bool {
+	return !s.UnlockedAt.IsZero()
+}
+
+// Goal returns how many events it takes to unlock the achievement
+func (s Status) Goal() int {
+	return s.goal()
+}
+
+// Statuses returns the progress of a user towards every achievement
+func (e *Engine) Statuses(ctx context.Context, userID int) ([]Status, error) {
+	if e == nil {
+		return nil, nil
+	}
+
+	progress, err := e.store.List(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("list achievements: %w", err)
+	}
+
+	statuses := make([]Status, len(all))
+	for i, a := range all {
+		statuses[i].Achievement = a
+		for _, p := range progress {
+			if p.AchievementID != a.ID {
+				continue
+			}
+			statuses[i].Progress = p.Progress
+			if p.UnlockedAt.Valid {
+				statuses[i].UnlockedAt = p.UnlockedAt.Time
+			}
+		}
+	}
+
+	return statuses, nil
+}
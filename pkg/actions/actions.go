@@ -0,0 +1,200 @@
+// Package actions holds the rules for caring for a pet. The TUI, the SSH
+// commands and the HTTP API all go through it so a pet behaves the same no
+// matter how the player reaches it.
+package actions
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/kirkegaard/terminal-pet/pkg/pet"
+)
+
+// Actions a player can perform on a pet
+const (
+	Feed     = "feed"
+	Play     = "play"
+	Clean    = "clean"
+	Medicine = "medicine"
+	Lights   = "lights"
+	Rename   = "rename"
+	Scold    = "scold"
+	Praise   = "praise"
+)
+
+// Names lists every action
+var Names = []string{Feed, Play, Clean, Medicine, Lights, Rename, Scold, Praise}
+
+// MaxNameLength is the longest name a pet can have
+const MaxNameLength = 20
+
+var (
+	ErrUnknownAction = errors.New("unknown action")
+	ErrUnknownFood   = errors.New("unknown food")
+	ErrInvalidName   = errors.New("invalid name")
+	ErrDead          = errors.New("has passed away")
+	ErrSleeping      = errors.New("is sleeping, turn the lights on first")
+	ErrNotHungry     = errors.New("isn't hungry")
+	ErrRefused       = errors.New("is being naughty and refused, scold it")
+)
+
+// Check returns an error if the action is not allowed in the pet's current
+// state
+func Check(p *pet.Pet, action string) error {
+	if p.IsDead() {
+		return fmt.Errorf("%s %w", p.Name, ErrDead)
+	}
+
+	if !p.LightsOn && action != Lights {
+		return fmt.Errorf("%s %w", p.Name, ErrSleeping)
+	}
+
+	return nil
+}
+
+// Do checks and performs an action on the pet and returns a short
+// description of what happened. arg is the food for Feed and the new name
+// for Rename, and ignored otherwise. The simulator provides the time and the
+// rolls of the action.
+func Do(sim *pet.Simulator, p *pet.Pet, action string, arg string) (string, error) {
+	if err := Check(p, action); err != nil {
+		return "", err
+	}
+
+	switch action {
+	case Feed:
+		food, err := FeedFood(sim, p, arg)
+		if err != nil {
+			return "", err
+		}
+		out := fmt.Sprintf("You fed %s some %s. Hunger is now %d.", p.Name, strings.ToLower(food.Name), p.Hunger)
+		switch {
+		case food.LikedBy(p.SpeciesID):
+			out += " It's a favourite!"
+		case food.DislikedBy(p.SpeciesID):
+			out += " It didn't like it much."
+		}
+		return out, nil
+
+	case Play:
+		if err := PlayWith(sim, p); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("You played with %s.", p.Name), nil
+
+	case Clean:
+		if !CleanUp(sim, p) {
+			return fmt.Sprintf("%s is already clean.", p.Name), nil
+		}
+		return fmt.Sprintf("You cleaned up after %s.", p.Name), nil
+
+	case Medicine:
+		if !GiveMedicine(sim, p) {
+			return fmt.Sprintf("%s wasn't sick. The medicine made it feel worse.", p.Name), nil
+		}
+		return fmt.Sprintf("%s took the medicine and feels better.", p.Name), nil
+
+	case Lights:
+		if ToggleLights(sim, p) {
+			return fmt.Sprintf("Lights on. %s is awake.", p.Name), nil
+		}
+		return fmt.Sprintf("Lights off. %s is going to sleep.", p.Name), nil
+
+	case Rename:
+		old := p.Name
+		if err := RenamePet(sim, p, arg); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("%s is now called %s.", old, p.Name), nil
+
+	case Scold:
+		if !p.Scold(sim.Now()) {
+			return fmt.Sprintf("%s didn't do anything wrong and is sad now.", p.Name), nil
+		}
+		return fmt.Sprintf("You scolded %s. Discipline is now %d.", p.Name, p.Discipline), nil
+
+	case Praise:
+		if !p.Praise(sim.Now()) {
+			return fmt.Sprintf("You praised %s for misbehaving. Discipline is now %d.", p.Name, p.Discipline), nil
+		}
+		return fmt.Sprintf("You praised %s. It looks happy.", p.Name), nil
+	}
+
+	return "", fmt.Errorf("%w %q", ErrUnknownAction, action)
+}
+
+// FeedFood feeds the food with the given ID from the catalog to the pet and
+// returns it
+func FeedFood(sim *pet.Simulator, p *pet.Pet, id string) (*pet.Food, error) {
+	catalog := pet.Foods()
+
+	food, ok := catalog.Get(id)
+	if !ok {
+		return nil, fmt.Errorf("%w %q, choose one of %s", ErrUnknownFood, id, strings.Join(catalog.IDs(), ", "))
+	}
+
+	// Only treats can be fed to a pet that is full
+	if !food.Treat && p.Hunger <= 0 {
+		return nil, fmt.Errorf("%s %w", p.Name, ErrNotHungry)
+	}
+
+	if !sim.Feed(p, food) {
+		return nil, fmt.Errorf("%s %w", p.Name, ErrRefused)
+	}
+
+	return food, nil
+}
+
+// PlayWith plays a quick round with the pet
+func PlayWith(sim *pet.Simulator, p *pet.Pet) error {
+	if !sim.Play(p) {
+		return fmt.Errorf("%s %w", p.Name, ErrRefused)
+	}
+
+	return nil
+}
+
+// CleanUp cleans up after the pet and reports whether there was anything to
+// clean
+func CleanUp(sim *pet.Simulator, p *pet.Pet) bool {
+	hadPooped := p.HasPooped
+	sim.Clean(p)
+	return hadPooped
+}
+
+// GiveMedicine gives the pet medicine and reports whether it was sick
+func GiveMedicine(sim *pet.Simulator, p *pet.Pet) bool {
+	wasSick := p.IsSick
+	sim.GiveMedicine(p)
+	return wasSick
+}
+
+// ToggleLights turns the lights on or off and reports whether they are on
+func ToggleLights(sim *pet.Simulator, p *pet.Pet) bool {
+	sim.ToggleLights(p)
+	return p.LightsOn
+}
+
+// RenamePet gives the pet a new name
+func RenamePet(sim *pet.Simulator, p *pet.Pet, name string) error {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return fmt.Errorf("%w: name can't be empty", ErrInvalidName)
+	}
+
+	if len(name) > MaxNameLength {
+		return fmt.Errorf("%w: name can be at most %d characters", ErrInvalidName, MaxNameLength)
+	}
+
+	for _, r := range name {
+		if !unicode.IsPrint(r) {
+			return fmt.Errorf("%w: name can only contain printable characters", ErrInvalidName)
+		}
+	}
+
+	sim.Rename(p, name)
+
+	return nil
+}
@@ -0,0 +1,136 @@
+package actions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/kirkegaard/terminal-pet/pkg/db/repo"
+	"github.com/kirkegaard/terminal-pet/pkg/pet"
+	"github.com/kirkegaard/terminal-pet/pkg/shop"
+)
+
+// Coin rewards
+const (
+	// DailyCareReward is paid for the first care action of every day
+	DailyCareReward = 25
+	// CoinsPerPoint is paid for every point scored in a minigame
+	CoinsPerPoint = 5
+)
+
+var (
+	ErrUnknownItem    = errors.New("unknown item")
+	ErrAlreadyOwned   = errors.New("already have")
+	ErrOutOfStock     = repo.ErrOutOfStock
+	ErrNotEnoughCoins = repo.ErrNotEnoughCoins
+)
+
+// IsCare reports whether the action counts towards the daily care reward
+func IsCare(action string) bool {
+	switch action {
+	case Feed, Play, Clean, Medicine:
+		return true
+	}
+
+	return false
+}
+
+// RequiredItem returns the inventory item the action uses up. Feeding uses
+// up the food and giving medicine uses up medicine.
+func RequiredItem(action string, arg string) (shop.Item, bool) {
+	switch action {
+	case Feed:
+		if food, ok := pet.Foods().Get(arg); ok {
+			return shop.FoodItem(food), true
+		}
+	case Medicine:
+		return shop.Get(shop.MedicineID)
+	}
+
+	return shop.Item{}, false
+}
+
+// TakeItem takes one of the item out of the player's inventory
+func TakeItem(ctx context.Context, inventory repo.InventoryStore, userID int, item shop.Item) error {
+	err := inventory.UseItem(ctx, userID, item.ID)
+	if errors.Is(err, ErrOutOfStock) {
+		return fmt.Errorf("%s is %w, buy more in the shop", strings.ToLower(item.Name), ErrOutOfStock)
+	}
+
+	return err
+}
+
+// ReturnItem puts an item taken for an action that didn't happen back into
+// the player's inventory
+func ReturnItem(ctx context.Context, inventory repo.InventoryStore, userID int, item shop.Item) error {
+	return inventory.AddItem(ctx, userID, item.ID, 1)
+}
+
+// Buy buys one of the item with the given ID for the player and returns it
+// along with the new balance. Toys last forever so they are only sold once.
+func Buy(ctx context.Context, inventory repo.InventoryStore, userID int, id string) (shop.Item, int, error) {
+	item, ok := shop.Get(id)
+	if !ok {
+		return item, 0, fmt.Errorf("%w %q, see the shop for what's for sale", ErrUnknownItem, id)
+	}
+
+	if item.Kind == shop.KindToy {
+		owned, err := inventory.Items(ctx, userID)
+		if err != nil {
+			return item, 0, err
+		}
+		if owned[item.ID] > 0 {
+			return item, 0, fmt.Errorf("you %w a %s", ErrAlreadyOwned, strings.ToLower(item.Name))
+		}
+	}
+
+	coins, err := inventory.Buy(ctx, userID, item.ID, item.Price)
+	if errors.Is(err, ErrNotEnoughCoins) {
+		return item, 0, fmt.Errorf("%w, %s costs %d", ErrNotEnoughCoins, strings.ToLower(item.Name), item.Price)
+	}
+	if err != nil {
+		return item, 0, err
+	}
+
+	return item, coins, nil
+}
+
+// BestToy returns the most fun toy among the owned items
+func BestToy(owned map[string]int) (shop.Item, bool) {
+	var best shop.Item
+	for _, item := range shop.Items() {
+		if item.Kind == shop.KindToy && owned[item.ID] > 0 && item.Fun > best.Fun {
+			best = item
+		}
+	}
+
+	return best, best.Fun > 0
+}
+
+// RewardCare pays the daily care reward if the player hasn't had it today
+// and returns how many coins were paid
+func RewardCare(ctx context.Context, inventory repo.InventoryStore, userID int, now time.Time) (int, error) {
+	claimed, err := inventory.ClaimDailyReward(ctx, userID, DailyCareReward, now)
+	if err != nil || !claimed {
+		return 0, err
+	}
+
+	return DailyCareReward, nil
+}
+
+// RewardGame pays for the points scored in a minigame and returns how many
+// coins were paid
+func RewardGame(ctx context.Context, inventory repo.InventoryStore, userID int, score int) (int, error) {
+	coins := score * CoinsPerPoint
+	if coins <= 0 {
+		return 0, nil
+	}
+
+	if _, err := inventory.AddCoins(ctx, userID, coins); err != nil {
+		return 0, err
+	}
+
+	return coins, nil
+}
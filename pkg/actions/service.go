@@ -0,0 +1,375 @@
+package actions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/log"
+	"github.com/kirkegaard/terminal-pet/pkg/achievements"
+	"github.com/kirkegaard/terminal-pet/pkg/db/repo"
+	"github.com/kirkegaard/terminal-pet/pkg/events"
+	"github.com/kirkegaard/terminal-pet/pkg/pet"
+	"github.com/kirkegaard/terminal-pet/pkg/shop"
+)
+
+// Service loads pets from a store, brings them up to date and saves them
+// after an action. Actions take the items they use up from the owner's
+// inventory, and the events of saved pets are published on the bus. Actions
+// on pets that are being played in a live session are handed to the session.
+// It is safe for concurrent use.
+type Service struct {
+	pets      repo.PetStore
+	inventory repo.InventoryStore
+	events    *events.Bus
+	clock     pet.Clock
+	sessions  Sessions
+}
+
+// Sessions runs actions on the pets being played in live sessions, whose
+// copy of the pet is the one that gets saved
+type Sessions interface {
+	// Perform performs the action on the pet with the given ID in the
+	// session playing it and returns a copy of the pet afterwards. ok is
+	// false if no session is playing the pet.
+	Perform(ctx context.Context, petID int, action string, arg string) (out string, p *pet.Pet, ok bool, err error)
+}
+
+type sessionKey struct{}
+
+// WithinSession marks the context of actions performed by the live session
+// playing the pet, which are performed right away rather than handed to it
+func WithinSession(ctx context.Context) context.Context {
+	return context.WithValue(ctx, sessionKey{}, true)
+}
+
+// withinSession reports whether the context was marked by WithinSession
+func withinSession(ctx context.Context) bool {
+	within, _ := ctx.Value(sessionKey{}).(bool)
+	return within
+}
+
+// saveAttempts is how many times an action is performed on a pet that keeps
+// being saved by someone else in the meantime before giving up
+const saveAttempts = 3
+
+// NewService creates a service on top of the given stores. Actions on pets
+// being played in one of the live sessions are handed to it, sessions may be
+// nil if there are none.
+func NewService(pets repo.PetStore, inventory repo.InventoryStore, bus *events.Bus, sessions Sessions, clock pet.Clock) *Service {
+	if clock == nil {
+		clock = pet.SystemClock
+	}
+
+	return &Service{
+		pets:      pets,
+		inventory: inventory,
+		events:    bus,
+		clock:     clock,
+		sessions:  sessions,
+	}
+}
+
+// simulator returns a new simulator on the service's clock. A new one is
+// used every time as its random source can't be shared between goroutines.
+func (s *Service) simulator() *pet.Simulator {
+	return pet.NewSimulator(s.clock, nil)
+}
+
+// simulate advances the pet to now. The events of the simulation happened
+// while nobody was there, they stay with the pet until it is saved and pets
+// that are only looked at are simulated again the next time.
+func (s *Service) simulate(p *pet.Pet) {
+	s.simulator().Update(p)
+}
+
+// publish publishes the events recorded on a saved pet and describes the
+// achievements they unlocked. The events of the action are published as the
+// owner's, away are those that happened before.
+func (s *Service) publish(ctx context.Context, p *pet.Pet, away []pet.Event) string {
+	var out string
+
+	// Only unlocks for this very pet are collected, as other goroutines
+	// publish on the bus at the same time
+	unsubscribe := s.events.Subscribe(func(ctx context.Context, e pet.Event) {
+		if unlocked, ok := e.(achievements.Unlocked); ok && unlocked.Pet == p {
+			out += fmt.Sprintf(" Achievement unlocked: %s %s!", unlocked.Achievement.Emoji, unlocked.Achievement.Name)
+		}
+	})
+	defer unsubscribe()
+
+	s.events.Publish(ctx, away...)
+	s.events.Publish(events.WithActor(ctx, p.Parent.Name), p.TakeEvents()...)
+
+	return out
+}
+
+// Pet returns the pet with the given ID brought up to date, or nil if there
+// is none
+func (s *Service) Pet(ctx context.Context, id int) (*pet.Pet, error) {
+	p, err := s.pets.GetByID(ctx, id)
+	if err != nil || p == nil {
+		return nil, err
+	}
+
+	s.simulate(p)
+
+	return p, nil
+}
+
+// Pets returns the living pets of a user brought up to date, oldest first
+func (s *Service) Pets(ctx context.Context, userID int) ([]*pet.Pet, error) {
+	pets, err := s.pets.ListAliveByParentID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, p := range pets {
+		s.simulate(p)
+	}
+
+	return pets, nil
+}
+
+// CurrentPet returns the newest living pet of a user, or their newest pet if
+// none are alive, brought up to date. It returns nil if the user never had a
+// pet.
+func (s *Service) CurrentPet(ctx context.Context, userID int) (*pet.Pet, error) {
+	living, err := s.Pets(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(living) > 0 {
+		return living[len(living)-1], nil
+	}
+
+	p, err := s.pets.GetByParentID(ctx, userID)
+	if err != nil || p == nil {
+		return nil, err
+	}
+
+	s.simulate(p)
+
+	return p, nil
+}
+
+// Inventory returns the store of coins and items
+func (s *Service) Inventory() repo.InventoryStore {
+	return s.inventory
+}
+
+// Events returns the bus the events of pets are published on
+func (s *Service) Events() *events.Bus {
+	return s.events
+}
+
+// Perform runs an action on a pet that was loaded through the service and
+// saves it. Food and medicine are taken from the owner's inventory and put
+// back if the action doesn't happen. If the pet was saved by someone else
+// since it was loaded, it is loaded again and the action performed on the
+// new copy, which p is updated to. If a live session is playing the pet the
+// action is performed there instead and p is updated to the session's copy,
+// so the session doesn't save over it later.
+func (s *Service) Perform(ctx context.Context, p *pet.Pet, action string, arg string) (string, error) {
+	if s.sessions != nil && !withinSession(ctx) {
+		out, live, ok, err := s.sessions.Perform(ctx, p.ID, action, arg)
+		if ok {
+			if live != nil {
+				*p = *live
+			}
+			return out, err
+		}
+	}
+
+	userID := p.Parent.ID
+
+	item, usesItem := RequiredItem(action, arg)
+	if usesItem {
+		if err := Check(p, action); err != nil {
+			return "", err
+		}
+		if err := TakeItem(ctx, s.inventory, userID, item); err != nil {
+			return "", err
+		}
+	}
+
+	out, away, err := s.save(ctx, p, func(p *pet.Pet) (string, error) {
+		out, err := Do(s.simulator(), p, action, arg)
+		if err == nil && action == Play {
+			if toy, ok := s.cheer(ctx, p); ok {
+				out += fmt.Sprintf(" The %s made it extra fun.", strings.ToLower(toy.Name))
+			}
+		}
+		return out, err
+	})
+
+	// Refused actions are saved but don't use the item up
+	if err != nil && usesItem {
+		if returnErr := ReturnItem(ctx, s.inventory, userID, item); returnErr != nil {
+			return "", fmt.Errorf("return item: %w", returnErr)
+		}
+	}
+	if err != nil && !errors.Is(err, ErrRefused) {
+		return "", err
+	}
+
+	if err == nil && IsCare(action) {
+		if coins := s.rewardCare(ctx, p); coins > 0 {
+			out += fmt.Sprintf(" You earned %d coins for today's care.", coins)
+		}
+	}
+
+	unlocked := s.publish(ctx, p, away)
+	if err == nil {
+		out += unlocked
+	}
+
+	return out, err
+}
+
+// StartGame checks whether the pet plays along before a minigame starts.
+// Poorly disciplined pets sometimes refuse, which is saved so the player can
+// scold the pet for it and returned as ErrRefused.
+func (s *Service) StartGame(ctx context.Context, p *pet.Pet) error {
+	if err := Check(p, Play); err != nil {
+		return err
+	}
+
+	sim := s.simulator()
+	if !sim.Refuses(p) {
+		return nil
+	}
+
+	rolled := true
+	_, away, err := s.save(ctx, p, func(p *pet.Pet) (string, error) {
+		// A copy that was loaded again gets a roll of its own
+		if !rolled && !sim.Refuses(p) {
+			return "", nil
+		}
+		rolled = false
+		return "", fmt.Errorf("%s %w", p.Name, ErrRefused)
+	})
+	if err != nil && !errors.Is(err, ErrRefused) {
+		return err
+	}
+
+	s.publish(ctx, p, away)
+
+	return err
+}
+
+// GameReward is what a finished minigame paid out
+type GameReward struct {
+	// Coins were paid for the score
+	Coins int
+	// CareCoins is the daily care reward, if it wasn't paid yet today
+	CareCoins int
+	// Toy is the owner's best toy, which made the game extra fun, if any
+	Toy string
+}
+
+// FinishGame applies the result of a minigame to the pet, lets the owner's
+// best toy cheer it up and saves it, then pays for the score and the daily
+// care reward
+func (s *Service) FinishGame(ctx context.Context, p *pet.Pet, result pet.GameResult) (GameReward, error) {
+	var reward GameReward
+
+	_, away, err := s.save(ctx, p, func(p *pet.Pet) (string, error) {
+		s.simulator().PlayGame(p, result)
+
+		reward.Toy = ""
+		if toy, ok := s.cheer(ctx, p); ok {
+			reward.Toy = toy.Name
+		}
+		return "", nil
+	})
+	if err != nil {
+		return GameReward{}, err
+	}
+
+	coins, err := RewardGame(ctx, s.inventory, p.Parent.ID, result.Score)
+	if err != nil {
+		log.Error("Error paying game reward", "error", err)
+	} else {
+		reward.Coins = coins
+	}
+
+	reward.CareCoins = s.rewardCare(ctx, p)
+
+	s.publish(ctx, p, away)
+
+	return reward, nil
+}
+
+// save applies a change to a pet and saves it. If the pet was saved by
+// someone else since it was loaded, it is loaded again and the change
+// applied to the new copy, which p is updated to. Changes that fail with
+// ErrRefused are saved too, so the player can scold the pet for them. It
+// returns the events that happened to the pet before the change.
+func (s *Service) save(ctx context.Context, p *pet.Pet, change func(p *pet.Pet) (string, error)) (string, []pet.Event, error) {
+	away := p.TakeEvents()
+	out, err := s.saveOnce(ctx, p, change)
+	for attempt := 1; errors.Is(err, repo.ErrPetConflict) && attempt < saveAttempts; attempt++ {
+		fresh, loadErr := s.Pet(ctx, p.ID)
+		if loadErr != nil {
+			err = fmt.Errorf("reload pet: %w", loadErr)
+			break
+		}
+		if fresh == nil {
+			break
+		}
+
+		*p = *fresh
+		away = p.TakeEvents()
+		out, err = s.saveOnce(ctx, p, change)
+	}
+
+	return out, away, err
+}
+
+// saveOnce applies a change to a pet and saves it
+func (s *Service) saveOnce(ctx context.Context, p *pet.Pet, change func(p *pet.Pet) (string, error)) (string, error) {
+	out, err := change(p)
+	if err != nil && !errors.Is(err, ErrRefused) {
+		return "", err
+	}
+
+	p.MarkDead(s.clock.Now())
+
+	if saveErr := s.pets.Update(ctx, p); saveErr != nil {
+		return "", fmt.Errorf("save pet: %w", saveErr)
+	}
+
+	return out, err
+}
+
+// cheer applies the fun of the owner's best toy to a pet that was played
+// with and returns the toy
+func (s *Service) cheer(ctx context.Context, p *pet.Pet) (shop.Item, bool) {
+	owned, err := s.inventory.Items(ctx, p.Parent.ID)
+	if err != nil {
+		log.Error("Error loading inventory", "error", err)
+		return shop.Item{}, false
+	}
+
+	toy, ok := BestToy(owned)
+	if ok {
+		p.Cheer(toy.Fun)
+	}
+
+	return toy, ok
+}
+
+// rewardCare pays the daily care reward for a saved action and returns how
+// many coins were paid
+func (s *Service) rewardCare(ctx context.Context, p *pet.Pet) int {
+	coins, err := RewardCare(ctx, s.inventory, p.Parent.ID, s.clock.Now())
+	if err != nil {
+		log.Error("Error paying daily care reward", "error", err)
+		return 0
+	}
+
+	return coins
+}
@@ -0,0 +1,264 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/kirkegaard/terminal-pet/pkg/actions"
+	"github.com/kirkegaard/terminal-pet/pkg/pet"
+)
+
+type contextKey string
+
+const userIDKey contextKey = "user_id"
+
+// maxBodySize limits the size of action request bodies
+const maxBodySize = 4096
+
+// response is the JSON document returned by every endpoint. Its version
+// follows pet.StatusVersion.
+type response struct {
+	Version int          `json:"version"`
+	UserID  int          `json:"userId,omitempty"`
+	Action  string       `json:"action,omitempty"`
+	Message string       `json:"message,omitempty"`
+	Error   string       `json:"error,omitempty"`
+	Pet     *pet.Status  `json:"pet,omitempty"`
+	Pets    []pet.Status `json:"pets,omitempty"`
+}
+
+// actionRequest is the optional body of an action request
+type actionRequest struct {
+	Food string `json:"food"`
+	Name string `json:"name"`
+}
+
+// authenticated only lets requests with a valid bearer token through and
+// stores the ID of the token's owner in the request context
+func (s *HTTPServer) authenticated(next http.HandlerFunc) http.HandlerFunc {
+	return s.identified(func(w http.ResponseWriter, r *http.Request) {
+		if _, ok := r.Context().Value(userIDKey).(int); !ok {
+			writeError(w, http.StatusUnauthorized, "missing bearer token")
+			return
+		}
+
+		next(w, r)
+	})
+}
+
+// identified stores the ID of the token's owner in the request context if
+// the request has a bearer token. Requests without one are let through
+// anonymously, invalid tokens are refused.
+func (s *HTTPServer) identified(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		header := r.Header.Get("Authorization")
+		if header == "" {
+			next(w, r)
+			return
+		}
+
+		token, ok := strings.CutPrefix(header, "Bearer ")
+		if !ok || token == "" {
+			writeError(w, http.StatusUnauthorized, "missing bearer token")
+			return
+		}
+
+		userID, err := s.tokens.GetUserID(r.Context(), HashToken(token))
+		if err != nil {
+			log.Error("Error looking up api token", "error", err)
+			writeError(w, http.StatusInternalServerError, "could not verify token")
+			return
+		}
+
+		if userID == 0 {
+			writeError(w, http.StatusUnauthorized, "invalid token")
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), userIDKey, userID)
+		next(w, r.WithContext(ctx))
+	}
+}
+
+func (s *HTTPServer) handleGetPet(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid pet id")
+		return
+	}
+
+	p, err := s.service.Pet(r.Context(), id)
+	if err != nil {
+		log.Error("Error loading pet", "id", id, "error", err)
+		writeError(w, http.StatusInternalServerError, "could not load pet")
+		return
+	}
+
+	if p == nil {
+		writeError(w, http.StatusNotFound, "pet not found")
+		return
+	}
+
+	if p.Parent != nil && !s.mayView(w, r, p.Parent.ID) {
+		return
+	}
+
+	status := p.StatusAt(time.Now())
+	writeJSON(w, http.StatusOK, response{Pet: &status})
+}
+
+func (s *HTTPServer) handleListPets(w http.ResponseWriter, r *http.Request) {
+	userID, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid user id")
+		return
+	}
+
+	if !s.mayView(w, r, userID) {
+		return
+	}
+
+	s.writePets(w, r, userID)
+}
+
+// mayView reports whether the pets of the owner may be seen by whoever made
+// the request, and responds with an error if not. Owners who keep their pets
+// private only show them to themselves.
+func (s *HTTPServer) mayView(w http.ResponseWriter, r *http.Request, ownerID int) bool {
+	owner, err := s.users.FindByID(r.Context(), ownerID)
+	if err != nil {
+		log.Error("Error finding user", "user_id", ownerID, "error", err)
+		writeError(w, http.StatusInternalServerError, "could not load pets")
+		return false
+	}
+
+	if owner == nil || !owner.Private {
+		return true
+	}
+
+	if viewer, ok := r.Context().Value(userIDKey).(int); ok && viewer == owner.ID {
+		return true
+	}
+
+	writeError(w, http.StatusForbidden, "the owner keeps their pets private")
+	return false
+}
+
+func (s *HTTPServer) handleMe(w http.ResponseWriter, r *http.Request) {
+	s.writePets(w, r, r.Context().Value(userIDKey).(int))
+}
+
+// writePets responds with the living pets of a user
+func (s *HTTPServer) writePets(w http.ResponseWriter, r *http.Request, userID int) {
+	pets, err := s.service.Pets(r.Context(), userID)
+	if err != nil {
+		log.Error("Error listing pets", "user_id", userID, "error", err)
+		writeError(w, http.StatusInternalServerError, "could not load pets")
+		return
+	}
+
+	now := time.Now()
+	statuses := make([]pet.Status, 0, len(pets))
+	for _, p := range pets {
+		statuses = append(statuses, p.StatusAt(now))
+	}
+
+	writeJSON(w, http.StatusOK, response{UserID: userID, Pets: statuses})
+}
+
+func (s *HTTPServer) handleAction(w http.ResponseWriter, r *http.Request) {
+	userID := r.Context().Value(userIDKey).(int)
+
+	id, err := strconv.Atoi(r.PathValue("id"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid pet id")
+		return
+	}
+
+	action := r.PathValue("action")
+
+	var req actionRequest
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxBodySize))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "could not read request body")
+		return
+	}
+	if len(body) > 0 {
+		if err := json.Unmarshal(body, &req); err != nil {
+			writeError(w, http.StatusBadRequest, "invalid request body")
+			return
+		}
+	}
+
+	p, err := s.service.Pet(r.Context(), id)
+	if err != nil {
+		log.Error("Error loading pet", "id", id, "error", err)
+		writeError(w, http.StatusInternalServerError, "could not load pet")
+		return
+	}
+
+	// Don't reveal which pets exist to other players
+	if p == nil || p.Parent == nil || p.Parent.ID != userID {
+		writeError(w, http.StatusNotFound, "pet not found")
+		return
+	}
+
+	arg := req.Food
+	if action == actions.Rename {
+		arg = req.Name
+	}
+
+	message, err := s.service.Perform(r.Context(), p, action, arg)
+	if err != nil {
+		code := actionErrorStatus(err)
+		if code == http.StatusInternalServerError {
+			log.Error("Error performing action", "id", id, "action", action, "error", err)
+			writeError(w, code, "could not perform action")
+			return
+		}
+
+		status := p.StatusAt(time.Now())
+		writeJSON(w, code, response{Action: action, Error: err.Error(), Pet: &status})
+		return
+	}
+
+	status := p.StatusAt(time.Now())
+	writeJSON(w, http.StatusOK, response{Action: action, Message: message, Pet: &status})
+}
+
+// actionErrorStatus maps an error from the actions package to a status code
+func actionErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, actions.ErrUnknownAction):
+		return http.StatusNotFound
+	case errors.Is(err, actions.ErrUnknownFood), errors.Is(err, actions.ErrInvalidName):
+		return http.StatusBadRequest
+	case errors.Is(err, actions.ErrDead), errors.Is(err, actions.ErrSleeping), errors.Is(err, actions.ErrNotHungry),
+		errors.Is(err, actions.ErrRefused), errors.Is(err, actions.ErrOutOfStock):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func writeError(w http.ResponseWriter, code int, message string) {
+	writeJSON(w, code, response{Error: message})
+}
+
+func writeJSON(w http.ResponseWriter, code int, body response) {
+	body.Version = pet.StatusVersion
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		log.Error("Error writing response", "error", err)
+	}
+}
@@ -0,0 +1,75 @@
+// Package api serves a small JSON API for reading and caring for pets over
+// HTTP. Reads are public unless the owner keeps their pets private, actions
+// need a token minted with `ssh host token`.
+package api
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/kirkegaard/terminal-pet/pkg/actions"
+	"github.com/kirkegaard/terminal-pet/pkg/config"
+	"github.com/kirkegaard/terminal-pet/pkg/db/repo"
+)
+
+type HTTPServer struct {
+	server  *http.Server
+	service *actions.Service
+	tokens  repo.TokenStore
+	users   repo.UserStore
+}
+
+func NewHTTPServer(ctx context.Context, service *actions.Service, tokens repo.TokenStore, users repo.UserStore) (*HTTPServer, error) {
+	cfg := config.FromContext(ctx)
+	if cfg == nil {
+		return nil, fmt.Errorf("config not found in context")
+	}
+
+	s := &HTTPServer{
+		service: service,
+		tokens:  tokens,
+		users:   users,
+	}
+
+	s.server = &http.Server{
+		Addr:              cfg.HTTP.ListenAddr,
+		Handler:           s.routes(),
+		ReadHeaderTimeout: 10 * time.Second,
+		BaseContext: func(net.Listener) context.Context {
+			return ctx
+		},
+	}
+
+	return s, nil
+}
+
+// routes registers every endpoint of the API
+func (s *HTTPServer) routes() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /api/v1/pets/{id}", s.identified(s.handleGetPet))
+	mux.HandleFunc("GET /api/v1/users/{id}/pets", s.identified(s.handleListPets))
+	mux.HandleFunc("GET /api/v1/me", s.authenticated(s.handleMe))
+	mux.HandleFunc("POST /api/v1/pets/{id}/{action}", s.authenticated(s.handleAction))
+
+	return mux
+}
+
+func (s *HTTPServer) ListenAndServe() error {
+	return s.server.ListenAndServe()
+}
+
+func (s *HTTPServer) Serve(l net.Listener) error {
+	return s.server.Serve(l)
+}
+
+func (s *HTTPServer) Close() error {
+	return s.server.Close()
+}
+
+func (s *HTTPServer) Shutdown(ctx context.Context) error {
+	return s.server.Shutdown(ctx)
+}
@@ -0,0 +1,29 @@
+package api
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+)
+
+// tokenPrefix makes API tokens easy to recognise, e.g. in leaked secrets scans
+const tokenPrefix = "tp_"
+
+// NewToken generates a new API token along with the hash to store for it
+func NewToken() (token string, hash string, err error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", fmt.Errorf("generate token: %w", err)
+	}
+
+	token = tokenPrefix + hex.EncodeToString(b)
+
+	return token, HashToken(token), nil
+}
+
+// HashToken returns the hash a token is stored and looked up by
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
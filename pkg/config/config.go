@@ -1,6 +1,9 @@
 package config
 
 import (
+	"fmt"
+	"time"
+
 	env "github.com/caarlos0/env/v11"
 )
 
@@ -9,14 +12,35 @@ type SSHConfig struct {
 	PublicURL  string `env:"PUBLIC_URL"`
 }
 
+// HTTPConfig configures the optional HTTP API. It is disabled when
+// ListenAddr is empty.
+type HTTPConfig struct {
+	ListenAddr string `env:"LISTEN_ADDR"`
+}
+
 type DBConfig struct {
 	Driver     string `env:"DRIVER"`
 	DataSource string `env:"DATA_SOURCE"`
 }
 
+type WorldConfig struct {
+	TickInterval time.Duration `env:"TICK_INTERVAL"`
+	// StatsInterval is how often the stats of every pet are snapshotted
+	StatsInterval time.Duration `env:"STATS_INTERVAL"`
+}
+
+type GameConfig struct {
+	MaxPets int `env:"MAX_PETS"`
+	// FoodCatalog is the path to a JSON food catalog replacing the built-in one
+	FoodCatalog string `env:"FOOD_CATALOG"`
+}
+
 type Config struct {
-	SSH SSHConfig `envPrefix:"SSH_"`
-	DB  DBConfig  `envPrefix:"DB_"`
+	SSH   SSHConfig   `envPrefix:"SSH_"`
+	HTTP  HTTPConfig  `envPrefix:"HTTP_"`
+	DB    DBConfig    `envPrefix:"DB_"`
+	World WorldConfig `envPrefix:"WORLD_"`
+	Game  GameConfig  `envPrefix:"GAME_"`
 }
 
 func DefaultConfig() *Config {
@@ -29,9 +53,29 @@ func DefaultConfig() *Config {
 			Driver:     "sqlite3",
 			DataSource: "./tmp/terminal-pet.db",
 		},
+		World: WorldConfig{
+			TickInterval:  time.Minute,
+			StatsInterval: 10 * time.Minute,
+		},
+		Game: GameConfig{
+			MaxPets: 3,
+		},
 	}
 }
 
 func ParseEnv(cfg *Config) error {
 	return env.Parse(cfg)
 }
+
+// Validate returns an error if a setting can't be used
+func (cfg *Config) Validate() error {
+	if cfg.World.TickInterval <= 0 {
+		return fmt.Errorf("WORLD_TICK_INTERVAL must be positive, got %s", cfg.World.TickInterval)
+	}
+
+	if cfg.World.StatsInterval <= 0 {
+		return fmt.Errorf("WORLD_STATS_INTERVAL must be positive, got %s", cfg.World.StatsInterval)
+	}
+
+	return nil
+}
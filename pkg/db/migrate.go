@@ -0,0 +1,220 @@
+package db
+
+import (
+	"context"
+	"embed"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/log"
+)
+
+//go:embed migrations
+var migrationFiles embed.FS
+
+// migrationLockID is the postgres advisory lock key held while migrating
+const migrationLockID = 7387623
+
+// Migration is a single versioned schema change. Migrations are loaded from
+// the embedded migrations/<driver> directory, where each file is named
+// <version>_<name>.sql. Every driver has its own set of files so the DDL can
+// use dialect specific types.
+type Migration struct {
+	Version int
+	Name    string
+	SQL     string
+}
+
+// MigrationStatus describes whether a migration has been applied
+type MigrationStatus struct {
+	Migration
+	Applied   bool
+	AppliedAt time.Time
+}
+
+// Migrations returns all embedded migrations for a driver ordered by version
+func Migrations(driverName string) ([]Migration, error) {
+	dir := path.Join("migrations", driverName)
+
+	entries, err := fs.ReadDir(migrationFiles, dir)
+	if err != nil {
+		return nil, fmt.Errorf("no migrations for driver %q: %w", driverName, err)
+	}
+
+	migrations := make([]Migration, 0, len(entries))
+	seen := make(map[int]string)
+
+	for _, entry := range entries {
+		if entry.IsDir() || path.Ext(entry.Name()) != ".sql" {
+			continue
+		}
+
+		base := strings.TrimSuffix(entry.Name(), ".sql")
+		versionStr, name, ok := strings.Cut(base, "_")
+		if !ok {
+			return nil, fmt.Errorf("invalid migration file name %q", entry.Name())
+		}
+
+		version, err := strconv.Atoi(versionStr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid migration version in %q: %w", entry.Name(), err)
+		}
+
+		if other, exists := seen[version]; exists {
+			return nil, fmt.Errorf("duplicate migration version %d in %q and %q", version, other, entry.Name())
+		}
+		seen[version] = entry.Name()
+
+		contents, err := migrationFiles.ReadFile(path.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("read migration %q: %w", entry.Name(), err)
+		}
+
+		migrations = append(migrations, Migration{
+			Version: version,
+			Name:    name,
+			SQL:     string(contents),
+		})
+	}
+
+	sort.Slice(migrations, func(i, j int) bool {
+		return migrations[i].Version < migrations[j].Version
+	})
+
+	return migrations, nil
+}
+
+// ensureMigrationsTable creates the table used to track applied migrations
+func (d *DB) ensureMigrationsTable(ctx context.Context) error {
+	_, err := d.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version INTEGER PRIMARY KEY,
+			name TEXT NOT NULL,
+			applied_at TIMESTAMP NOT NULL
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("create schema_migrations table: %w", err)
+	}
+
+	return nil
+}
+
+// MigrationStatus returns every known migration and whether it was applied
+func (d *DB) MigrationStatus(ctx context.Context) ([]MigrationStatus, error) {
+	if err := d.ensureMigrationsTable(ctx); err != nil {
+		return nil, err
+	}
+
+	migrations, err := Migrations(d.DriverName())
+	if err != nil {
+		return nil, err
+	}
+
+	var applied []struct {
+		Version   int       `db:"version"`
+		AppliedAt time.Time `db:"applied_at"`
+	}
+
+	err = d.SelectContext(ctx, &applied, "SELECT version, applied_at FROM schema_migrations")
+	if err != nil {
+		return nil, fmt.Errorf("list applied migrations: %w", err)
+	}
+
+	appliedAt := make(map[int]time.Time, len(applied))
+	for _, a := range applied {
+		appliedAt[a.Version] = a.AppliedAt
+	}
+
+	statuses := make([]MigrationStatus, 0, len(migrations))
+	for _, m := range migrations {
+		at, ok := appliedAt[m.Version]
+		statuses = append(statuses, MigrationStatus{
+			Migration: m,
+			Applied:   ok,
+			AppliedAt: at,
+		})
+	}
+
+	return statuses, nil
+}
+
+// Migrate applies all pending migrations in order and returns how many
+// were applied. Each migration runs in its own transaction.
+func (d *DB) Migrate(ctx context.Context) (int, error) {
+	statuses, err := d.MigrationStatus(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	count := 0
+	for _, status := range statuses {
+		if status.Applied {
+			continue
+		}
+
+		applied, err := d.applyMigration(ctx, status.Migration)
+		if err != nil {
+			return count, err
+		}
+
+		if !applied {
+			continue
+		}
+
+		log.Info("Applied migration", "version", status.Version, "name", status.Name)
+		count++
+	}
+
+	return count, nil
+}
+
+// applyMigration runs a single migration and reports whether it was applied
+func (d *DB) applyMigration(ctx context.Context, m Migration) (bool, error) {
+	tx, err := d.BeginTxx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("begin migration %d: %w", m.Version, err)
+	}
+	defer tx.Rollback()
+
+	// Serialize migrations between servers sharing the same database
+	if d.DriverName() == DriverPostgres {
+		if _, err := tx.ExecContext(ctx, "SELECT pg_advisory_xact_lock($1)", migrationLockID); err != nil {
+			return false, fmt.Errorf("lock migrations: %w", err)
+		}
+	}
+
+	// Another server may have applied the migration in the meantime
+	var count int
+	err = tx.GetContext(ctx, &count, d.Rebind("SELECT COUNT(*) FROM schema_migrations WHERE version = ?"), m.Version)
+	if err != nil {
+		return false, fmt.Errorf("check migration %d: %w", m.Version, err)
+	}
+
+	if count > 0 {
+		return false, nil
+	}
+
+	if _, err := tx.ExecContext(ctx, m.SQL); err != nil {
+		return false, fmt.Errorf("apply migration %d (%s): %w", m.Version, m.Name, err)
+	}
+
+	_, err = tx.ExecContext(ctx,
+		d.Rebind("INSERT INTO schema_migrations (version, name, applied_at) VALUES (?, ?, ?)"),
+		m.Version, m.Name, time.Now().UTC(),
+	)
+	if err != nil {
+		return false, fmt.Errorf("record migration %d: %w", m.Version, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return false, fmt.Errorf("commit migration %d: %w", m.Version, err)
+	}
+
+	return true, nil
+}
@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS users (
+	id SERIAL PRIMARY KEY,
+	name TEXT NOT NULL,
+	public_key TEXT NOT NULL UNIQUE
+);
+
+CREATE TABLE IF NOT EXISTS pets (
+	id SERIAL PRIMARY KEY,
+	name TEXT NOT NULL,
+	birthday TIMESTAMPTZ NOT NULL,
+	parent_id INTEGER NOT NULL REFERENCES users(id),
+	hunger INTEGER NOT NULL DEFAULT 0,
+	happiness INTEGER NOT NULL DEFAULT 0,
+	discipline INTEGER NOT NULL DEFAULT 0,
+	health INTEGER NOT NULL DEFAULT 100,
+	weight INTEGER NOT NULL DEFAULT 0,
+	is_sick BOOLEAN NOT NULL DEFAULT FALSE,
+	has_pooped BOOLEAN NOT NULL DEFAULT FALSE,
+	lights_on BOOLEAN NOT NULL DEFAULT TRUE,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	updated_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
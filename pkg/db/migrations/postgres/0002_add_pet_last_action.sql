@@ -0,0 +1 @@
+ALTER TABLE pets ADD COLUMN IF NOT EXISTS last_action TIMESTAMPTZ;
@@ -0,0 +1,3 @@
+ALTER TABLE pets ADD COLUMN IF NOT EXISTS died_at TIMESTAMPTZ;
+ALTER TABLE pets ADD COLUMN IF NOT EXISTS cause_of_death TEXT NOT NULL DEFAULT '';
+ALTER TABLE pets ADD COLUMN IF NOT EXISTS epitaph TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,4 @@
+ALTER TABLE pets ADD COLUMN IF NOT EXISTS misbehavior TEXT NOT NULL DEFAULT '';
+ALTER TABLE pets ADD COLUMN IF NOT EXISTS misbehaved_at TIMESTAMPTZ;
+ALTER TABLE pets ADD COLUMN IF NOT EXISTS missed_calls INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE pets ADD COLUMN IF NOT EXISTS adult_form TEXT NOT NULL DEFAULT '';
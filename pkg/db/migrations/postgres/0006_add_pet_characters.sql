@@ -0,0 +1,9 @@
+ALTER TABLE pets RENAME COLUMN adult_form TO character_id;
+UPDATE pets SET character_id = CASE character_id
+    WHEN 'Gentle' THEN 'lion'
+    WHEN 'Ordinary' THEN 'housecat'
+    WHEN 'Rascal' THEN 'tomcat'
+    ELSE ''
+END;
+ALTER TABLE pets ADD COLUMN IF NOT EXISTS care_steps INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE pets ADD COLUMN IF NOT EXISTS hunger_total INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,9 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS coins INTEGER NOT NULL DEFAULT 100;
+ALTER TABLE users ADD COLUMN IF NOT EXISTS daily_reward_at TIMESTAMPTZ;
+
+CREATE TABLE IF NOT EXISTS inventory (
+	user_id INTEGER NOT NULL REFERENCES users(id),
+	item_id TEXT NOT NULL,
+	quantity INTEGER NOT NULL DEFAULT 0,
+	PRIMARY KEY (user_id, item_id)
+);
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS game_scores (
+	id SERIAL PRIMARY KEY,
+	user_id INTEGER NOT NULL REFERENCES users(id),
+	pet_id INTEGER NOT NULL REFERENCES pets(id),
+	game TEXT NOT NULL,
+	score INTEGER NOT NULL,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS game_scores_game_score ON game_scores (game, score);
+CREATE INDEX IF NOT EXISTS game_scores_user_id_game ON game_scores (user_id, game);
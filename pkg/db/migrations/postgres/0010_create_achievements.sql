@@ -0,0 +1,7 @@
+CREATE TABLE IF NOT EXISTS achievements (
+	user_id INTEGER NOT NULL REFERENCES users(id),
+	achievement_id TEXT NOT NULL,
+	progress INTEGER NOT NULL DEFAULT 0,
+	unlocked_at TIMESTAMPTZ,
+	PRIMARY KEY (user_id, achievement_id)
+);
@@ -0,0 +1,10 @@
+CREATE TABLE IF NOT EXISTS pet_events (
+	id SERIAL PRIMARY KEY,
+	pet_id INTEGER NOT NULL REFERENCES pets(id),
+	actor TEXT NOT NULL,
+	kind TEXT NOT NULL,
+	detail TEXT NOT NULL DEFAULT '',
+	created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS pet_events_pet_id_created_at ON pet_events (pet_id, created_at);
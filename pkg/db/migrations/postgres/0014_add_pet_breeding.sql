@@ -0,0 +1,14 @@
+ALTER TABLE pets ADD COLUMN IF NOT EXISTS color TEXT NOT NULL DEFAULT '';
+ALTER TABLE pets ADD COLUMN IF NOT EXISTS traits TEXT NOT NULL DEFAULT '';
+ALTER TABLE pets ADD COLUMN IF NOT EXISTS appetite INTEGER NOT NULL DEFAULT 5;
+ALTER TABLE pets ADD COLUMN IF NOT EXISTS spirit INTEGER NOT NULL DEFAULT 5;
+ALTER TABLE pets ADD COLUMN IF NOT EXISTS vigor INTEGER NOT NULL DEFAULT 5;
+ALTER TABLE pets ADD COLUMN IF NOT EXISTS bred_at TIMESTAMPTZ;
+
+CREATE TABLE IF NOT EXISTS pet_parents (
+	pet_id INTEGER NOT NULL REFERENCES pets(id),
+	parent_id INTEGER NOT NULL REFERENCES pets(id),
+	PRIMARY KEY (pet_id, parent_id)
+);
+
+CREATE INDEX IF NOT EXISTS pet_parents_parent_id ON pet_parents (parent_id);
@@ -0,0 +1,22 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS last_seen_at TIMESTAMPTZ;
+
+CREATE TABLE IF NOT EXISTS friends (
+	user_id INTEGER NOT NULL REFERENCES users(id),
+	friend_id INTEGER NOT NULL REFERENCES users(id),
+	created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	accepted_at TIMESTAMPTZ,
+	PRIMARY KEY (user_id, friend_id)
+);
+
+CREATE INDEX IF NOT EXISTS friends_friend_id ON friends (friend_id);
+
+CREATE TABLE IF NOT EXISTS gifts (
+	id SERIAL PRIMARY KEY,
+	from_user_id INTEGER NOT NULL REFERENCES users(id),
+	to_user_id INTEGER NOT NULL REFERENCES users(id),
+	item_id TEXT NOT NULL,
+	created_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	delivered_at TIMESTAMPTZ
+);
+
+CREATE INDEX IF NOT EXISTS gifts_to_user_id_delivered_at ON gifts (to_user_id, delivered_at);
@@ -0,0 +1 @@
+ALTER TABLE pets ADD COLUMN IF NOT EXISTS revision INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,3 @@
+ALTER TABLE pets ADD COLUMN IF NOT EXISTS lifespan_start TIMESTAMPTZ;
+UPDATE pets SET lifespan_start = (SELECT applied_at FROM schema_migrations WHERE version = 7)
+WHERE health > 0 AND birthday < (SELECT applied_at FROM schema_migrations WHERE version = 7);
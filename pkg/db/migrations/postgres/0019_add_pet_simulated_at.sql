@@ -0,0 +1,2 @@
+ALTER TABLE pets ADD COLUMN IF NOT EXISTS simulated_at TIMESTAMPTZ;
+UPDATE pets SET simulated_at = updated_at WHERE simulated_at IS NULL;
@@ -0,0 +1,23 @@
+CREATE TABLE IF NOT EXISTS users (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	name TEXT NOT NULL,
+	public_key TEXT NOT NULL UNIQUE
+);
+
+CREATE TABLE IF NOT EXISTS pets (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	name TEXT NOT NULL,
+	birthday TIMESTAMP NOT NULL,
+	parent_id INTEGER NOT NULL,
+	hunger INTEGER NOT NULL DEFAULT 0,
+	happiness INTEGER NOT NULL DEFAULT 0,
+	discipline INTEGER NOT NULL DEFAULT 0,
+	health INTEGER NOT NULL DEFAULT 100,
+	weight INTEGER NOT NULL DEFAULT 0,
+	is_sick BOOLEAN NOT NULL DEFAULT 0,
+	has_pooped BOOLEAN NOT NULL DEFAULT 0,
+	lights_on BOOLEAN NOT NULL DEFAULT 1,
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	updated_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	FOREIGN KEY (parent_id) REFERENCES users(id)
+);
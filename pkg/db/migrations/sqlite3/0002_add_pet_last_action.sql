@@ -0,0 +1 @@
+ALTER TABLE pets ADD COLUMN last_action TIMESTAMP;
@@ -0,0 +1,3 @@
+ALTER TABLE pets ADD COLUMN died_at TIMESTAMP;
+ALTER TABLE pets ADD COLUMN cause_of_death TEXT NOT NULL DEFAULT '';
+ALTER TABLE pets ADD COLUMN epitaph TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,4 @@
+ALTER TABLE pets ADD COLUMN misbehavior TEXT NOT NULL DEFAULT '';
+ALTER TABLE pets ADD COLUMN misbehaved_at TIMESTAMP;
+ALTER TABLE pets ADD COLUMN missed_calls INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE pets ADD COLUMN adult_form TEXT NOT NULL DEFAULT '';
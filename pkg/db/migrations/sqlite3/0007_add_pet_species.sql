@@ -0,0 +1 @@
+ALTER TABLE pets ADD COLUMN species TEXT NOT NULL DEFAULT 'cat';
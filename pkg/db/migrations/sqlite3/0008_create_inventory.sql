@@ -0,0 +1,10 @@
+ALTER TABLE users ADD COLUMN coins INTEGER NOT NULL DEFAULT 100;
+ALTER TABLE users ADD COLUMN daily_reward_at TIMESTAMP;
+
+CREATE TABLE IF NOT EXISTS inventory (
+	user_id INTEGER NOT NULL,
+	item_id TEXT NOT NULL,
+	quantity INTEGER NOT NULL DEFAULT 0,
+	PRIMARY KEY (user_id, item_id),
+	FOREIGN KEY (user_id) REFERENCES users(id)
+);
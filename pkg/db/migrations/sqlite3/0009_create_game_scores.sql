@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS game_scores (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	user_id INTEGER NOT NULL,
+	pet_id INTEGER NOT NULL,
+	game TEXT NOT NULL,
+	score INTEGER NOT NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	FOREIGN KEY (user_id) REFERENCES users(id),
+	FOREIGN KEY (pet_id) REFERENCES pets(id)
+);
+
+CREATE INDEX IF NOT EXISTS game_scores_game_score ON game_scores (game, score);
+CREATE INDEX IF NOT EXISTS game_scores_user_id_game ON game_scores (user_id, game);
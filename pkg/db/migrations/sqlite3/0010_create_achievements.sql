@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS achievements (
+	user_id INTEGER NOT NULL,
+	achievement_id TEXT NOT NULL,
+	progress INTEGER NOT NULL DEFAULT 0,
+	unlocked_at TIMESTAMP,
+	PRIMARY KEY (user_id, achievement_id),
+	FOREIGN KEY (user_id) REFERENCES users(id)
+);
@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS pet_events (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	pet_id INTEGER NOT NULL,
+	actor TEXT NOT NULL,
+	kind TEXT NOT NULL,
+	detail TEXT NOT NULL DEFAULT '',
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	FOREIGN KEY (pet_id) REFERENCES pets(id)
+);
+
+CREATE INDEX IF NOT EXISTS pet_events_pet_id_created_at ON pet_events (pet_id, created_at);
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS pet_stats (
+	pet_id INTEGER NOT NULL,
+	bucket_seconds INTEGER NOT NULL,
+	bucket_start TIMESTAMP NOT NULL,
+	hunger INTEGER NOT NULL,
+	happiness INTEGER NOT NULL,
+	health INTEGER NOT NULL,
+	weight INTEGER NOT NULL,
+	samples INTEGER NOT NULL DEFAULT 1,
+	PRIMARY KEY (pet_id, bucket_seconds, bucket_start),
+	FOREIGN KEY (pet_id) REFERENCES pets(id)
+);
+
+CREATE INDEX IF NOT EXISTS pet_stats_bucket_seconds_bucket_start ON pet_stats (bucket_seconds, bucket_start);
@@ -0,0 +1 @@
+ALTER TABLE users ADD COLUMN is_private BOOLEAN NOT NULL DEFAULT 0;
@@ -0,0 +1,16 @@
+ALTER TABLE pets ADD COLUMN color TEXT NOT NULL DEFAULT '';
+ALTER TABLE pets ADD COLUMN traits TEXT NOT NULL DEFAULT '';
+ALTER TABLE pets ADD COLUMN appetite INTEGER NOT NULL DEFAULT 5;
+ALTER TABLE pets ADD COLUMN spirit INTEGER NOT NULL DEFAULT 5;
+ALTER TABLE pets ADD COLUMN vigor INTEGER NOT NULL DEFAULT 5;
+ALTER TABLE pets ADD COLUMN bred_at TIMESTAMP;
+
+CREATE TABLE IF NOT EXISTS pet_parents (
+	pet_id INTEGER NOT NULL,
+	parent_id INTEGER NOT NULL,
+	PRIMARY KEY (pet_id, parent_id),
+	FOREIGN KEY (pet_id) REFERENCES pets(id),
+	FOREIGN KEY (parent_id) REFERENCES pets(id)
+);
+
+CREATE INDEX IF NOT EXISTS pet_parents_parent_id ON pet_parents (parent_id);
@@ -0,0 +1,26 @@
+ALTER TABLE users ADD COLUMN last_seen_at TIMESTAMP;
+
+CREATE TABLE IF NOT EXISTS friends (
+	user_id INTEGER NOT NULL,
+	friend_id INTEGER NOT NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	accepted_at TIMESTAMP,
+	PRIMARY KEY (user_id, friend_id),
+	FOREIGN KEY (user_id) REFERENCES users(id),
+	FOREIGN KEY (friend_id) REFERENCES users(id)
+);
+
+CREATE INDEX IF NOT EXISTS friends_friend_id ON friends (friend_id);
+
+CREATE TABLE IF NOT EXISTS gifts (
+	id INTEGER PRIMARY KEY AUTOINCREMENT,
+	from_user_id INTEGER NOT NULL,
+	to_user_id INTEGER NOT NULL,
+	item_id TEXT NOT NULL,
+	created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+	delivered_at TIMESTAMP,
+	FOREIGN KEY (from_user_id) REFERENCES users(id),
+	FOREIGN KEY (to_user_id) REFERENCES users(id)
+);
+
+CREATE INDEX IF NOT EXISTS gifts_to_user_id_delivered_at ON gifts (to_user_id, delivered_at);
@@ -0,0 +1 @@
+ALTER TABLE pets ADD COLUMN revision INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,3 @@
+ALTER TABLE pets ADD COLUMN lifespan_start TIMESTAMP;
+UPDATE pets SET lifespan_start = (SELECT applied_at FROM schema_migrations WHERE version = 7)
+WHERE health > 0 AND julianday(birthday) < (SELECT julianday(applied_at) FROM schema_migrations WHERE version = 7);
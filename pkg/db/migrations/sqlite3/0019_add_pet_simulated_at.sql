@@ -0,0 +1,2 @@
+ALTER TABLE pets ADD COLUMN simulated_at TIMESTAMP;
+UPDATE pets SET simulated_at = updated_at;
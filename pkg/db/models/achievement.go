@@ -0,0 +1,14 @@
+package models
+
+import (
+	"database/sql"
+)
+
+// Achievement is the progress of a user towards an achievement, and when
+// they unlocked it
+type Achievement struct {
+	UserID        int          `db:"user_id"`
+	AchievementID string       `db:"achievement_id"`
+	Progress      int          `db:"progress"`
+	UnlockedAt    sql.NullTime `db:"unlocked_at"`
+}
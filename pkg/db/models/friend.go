@@ -0,0 +1,32 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Friend is another user on a user's friends list, or a friend request
+// between the two that wasn't accepted yet. Incoming requests were sent by
+// the friend.
+type Friend struct {
+	UserID     int          `db:"user_id"`
+	Name       string       `db:"name"`
+	PublicKey  string       `db:"public_key"`
+	LastSeenAt sql.NullTime `db:"last_seen_at"`
+	Incoming   bool         `db:"incoming"`
+	CreatedAt  time.Time    `db:"created_at"`
+	AcceptedAt sql.NullTime `db:"accepted_at"`
+}
+
+// Gift is an item sent from one user to another. It is added to the
+// recipient's inventory once delivered. FromName is only filled in by
+// queries that join the sender.
+type Gift struct {
+	ID          int          `db:"id"`
+	FromUserID  int          `db:"from_user_id"`
+	ToUserID    int          `db:"to_user_id"`
+	ItemID      string       `db:"item_id"`
+	FromName    string       `db:"from_name"`
+	CreatedAt   time.Time    `db:"created_at"`
+	DeliveredAt sql.NullTime `db:"delivered_at"`
+}
@@ -1,20 +1,46 @@
 package models
 
-import "time"
+import (
+	"database/sql"
+	"time"
+)
 
 type Pet struct {
-	ID         int       `db:"id"`
-	Name       string    `db:"name"`
-	BirthDate  time.Time `db:"birthday"`
-	ParentID   int       `db:"parent_id"`
-	Hunger     int       `db:"hunger"`
-	Happiness  int       `db:"happiness"`
-	Discipline int       `db:"discipline"`
-	Health     int       `db:"health"`
-	Weight     int       `db:"weight"`
-	IsSick     bool      `db:"is_sick"`
-	HasPooped  bool      `db:"has_pooped"`
-	LightsOn   bool      `db:"lights_on"`
-	CreatedAt  time.Time `db:"created_at"`
-	UpdatedAt  time.Time `db:"updated_at"`
+	ID            int          `db:"id"`
+	Name          string       `db:"name"`
+	Species       string       `db:"species"`
+	BirthDate     time.Time    `db:"birthday"`
+	ParentID      int          `db:"parent_id"`
+	Hunger        int          `db:"hunger"`
+	Happiness     int          `db:"happiness"`
+	Discipline    int          `db:"discipline"`
+	Health        int          `db:"health"`
+	Weight        int          `db:"weight"`
+	IsSick        bool         `db:"is_sick"`
+	HasPooped     bool         `db:"has_pooped"`
+	LightsOn      bool         `db:"lights_on"`
+	LastAction    sql.NullTime `db:"last_action"`
+	DiedAt        sql.NullTime `db:"died_at"`
+	CauseOfDeath  string       `db:"cause_of_death"`
+	Epitaph       string       `db:"epitaph"`
+	Misbehavior   string       `db:"misbehavior"`
+	MisbehavedAt  sql.NullTime `db:"misbehaved_at"`
+	MissedCalls   int          `db:"missed_calls"`
+	CharacterID   string       `db:"character_id"`
+	CareSteps     int          `db:"care_steps"`
+	HungerTotal   int          `db:"hunger_total"`
+	Color         string       `db:"color"`
+	Traits        string       `db:"traits"`
+	Appetite      int          `db:"appetite"`
+	Spirit        int          `db:"spirit"`
+	Vigor         int          `db:"vigor"`
+	BredAt        sql.NullTime `db:"bred_at"`
+	Revision      int          `db:"revision"`
+	LifespanStart sql.NullTime `db:"lifespan_start"`
+	SimulatedAt   sql.NullTime `db:"simulated_at"`
+	CreatedAt     time.Time    `db:"created_at"`
+	UpdatedAt     time.Time    `db:"updated_at"`
+
+	// ParentName is only filled in by queries that join the owner
+	ParentName string `db:"parent_name"`
 }
@@ -0,0 +1,16 @@
+package models
+
+import (
+	"time"
+)
+
+// PetEvent is an entry in the activity log of a pet. Actor is the player
+// who was there when it happened, or "world" if nobody was.
+type PetEvent struct {
+	ID        int       `db:"id"`
+	PetID     int       `db:"pet_id"`
+	Actor     string    `db:"actor"`
+	Kind      string    `db:"kind"`
+	Detail    string    `db:"detail"`
+	CreatedAt time.Time `db:"created_at"`
+}
@@ -0,0 +1,18 @@
+package models
+
+import (
+	"time"
+)
+
+// GameScore is a score reached in a minigame. UserName and PetName are only
+// filled in by queries that join them.
+type GameScore struct {
+	ID        int       `db:"id"`
+	UserID    int       `db:"user_id"`
+	PetID     int       `db:"pet_id"`
+	Game      string    `db:"game"`
+	Score     int       `db:"score"`
+	UserName  string    `db:"user_name"`
+	PetName   string    `db:"pet_name"`
+	CreatedAt time.Time `db:"created_at"`
+}
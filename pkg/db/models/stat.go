@@ -0,0 +1,19 @@
+package models
+
+import (
+	"time"
+)
+
+// StatSnapshot is the average of the stats of a pet over a bucket of time
+// starting at At. Recent snapshots are single samples, older ones are
+// compacted into averages over larger buckets.
+type StatSnapshot struct {
+	PetID         int       `db:"pet_id"`
+	BucketSeconds int       `db:"bucket_seconds"`
+	At            time.Time `db:"bucket_start"`
+	Hunger        int       `db:"hunger"`
+	Happiness     int       `db:"happiness"`
+	Health        int       `db:"health"`
+	Weight        int       `db:"weight"`
+	Samples       int       `db:"samples"`
+}
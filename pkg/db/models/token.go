@@ -0,0 +1,12 @@
+package models
+
+import (
+	"time"
+)
+
+type APIToken struct {
+	ID        int       `db:"id"`
+	UserID    int       `db:"user_id"`
+	TokenHash string    `db:"token_hash"`
+	CreatedAt time.Time `db:"created_at"`
+}
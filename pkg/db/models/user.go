@@ -1,7 +1,15 @@
 package models
 
+import (
+	"database/sql"
+)
+
 type User struct {
 	ID        int    `db:"id"`
 	Name      string `db:"name"`
 	PublicKey string `db:"public_key"`
+	// Private users can't be visited by other players
+	Private bool `db:"is_private"`
+	// LastSeenAt is when the user last connected or disconnected
+	LastSeenAt sql.NullTime `db:"last_seen_at"`
 }
@@ -0,0 +1,84 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kirkegaard/terminal-pet/pkg/db"
+	"github.com/kirkegaard/terminal-pet/pkg/db/models"
+)
+
+type AchievementRepository struct {
+	db *db.DB
+}
+
+func NewAchievementRepository(database *db.DB) *AchievementRepository {
+	return &AchievementRepository{
+		db: database,
+	}
+}
+
+// List retrieves the progress of a user towards their achievements
+func (r *AchievementRepository) List(ctx context.Context, userID int) ([]models.Achievement, error) {
+	if r.db == nil {
+		return nil, fmt.Errorf("no database connection available")
+	}
+
+	var achievements []models.Achievement
+	err := r.db.SelectContext(ctx, &achievements,
+		r.db.Rebind("SELECT user_id, achievement_id, progress, unlocked_at FROM achievements WHERE user_id = ?"),
+		userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list achievements: %w", err)
+	}
+
+	return achievements, nil
+}
+
+// AddProgress adds to the progress of a user towards an achievement,
+// creating its row if needed
+func (r *AchievementRepository) AddProgress(ctx context.Context, userID int, achievementID string, amount int) (int, error) {
+	if r.db == nil {
+		return 0, fmt.Errorf("no database connection available")
+	}
+
+	var progress int
+	err := r.db.QueryRowContext(ctx,
+		r.db.Rebind(`INSERT INTO achievements (user_id, achievement_id, progress) VALUES (?, ?, ?)
+			ON CONFLICT (user_id, achievement_id) DO UPDATE SET progress = achievements.progress + excluded.progress
+			RETURNING progress`),
+		userID, achievementID, amount,
+	).Scan(&progress)
+	if err != nil {
+		return 0, fmt.Errorf("add achievement progress: %w", err)
+	}
+
+	return progress, nil
+}
+
+// Unlock records when a user unlocked an achievement, unless they already
+// had
+func (r *AchievementRepository) Unlock(ctx context.Context, userID int, achievementID string, at time.Time) (bool, error) {
+	if r.db == nil {
+		return false, fmt.Errorf("no database connection available")
+	}
+
+	result, err := r.db.ExecContext(ctx,
+		r.db.Rebind(`INSERT INTO achievements (user_id, achievement_id, progress, unlocked_at) VALUES (?, ?, 0, ?)
+			ON CONFLICT (user_id, achievement_id) DO UPDATE SET unlocked_at = excluded.unlocked_at
+			WHERE achievements.unlocked_at IS NULL`),
+		userID, achievementID, at.UTC(),
+	)
+	if err != nil {
+		return false, fmt.Errorf("unlock achievement: %w", err)
+	}
+
+	unlocked, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("get rows affected: %w", err)
+	}
+
+	return unlocked > 0, nil
+}
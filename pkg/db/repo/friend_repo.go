@@ -0,0 +1,133 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/kirkegaard/terminal-pet/pkg/db"
+	"github.com/kirkegaard/terminal-pet/pkg/db/models"
+)
+
+type FriendRepository struct {
+	db *db.DB
+}
+
+func NewFriendRepository(database *db.DB) *FriendRepository {
+	return &FriendRepository{
+		db: database,
+	}
+}
+
+// friendColumns selects the other user of a friendship along with the
+// friendship itself, the first parameter is the user looking at it
+const friendColumns = `
+	u.id AS user_id, u.name, u.public_key, u.last_seen_at,
+	f.user_id <> ? AS incoming, f.created_at, f.accepted_at
+	FROM friends f
+	JOIN users u ON u.id = CASE WHEN f.user_id = ? THEN f.friend_id ELSE f.user_id END`
+
+// Get retrieves the friendship or request between two users
+func (r *FriendRepository) Get(ctx context.Context, userID, otherID int) (*models.Friend, error) {
+	if r.db == nil {
+		return nil, fmt.Errorf("no database connection available")
+	}
+
+	query := `SELECT ` + friendColumns + `
+		WHERE (f.user_id = ? AND f.friend_id = ?) OR (f.user_id = ? AND f.friend_id = ?)`
+
+	var friend models.Friend
+	err := r.db.GetContext(ctx, &friend, r.db.Rebind(query), userID, userID, userID, otherID, otherID, userID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("find friend: %w", err)
+	}
+
+	return &friend, nil
+}
+
+// Request stores a friend request
+func (r *FriendRepository) Request(ctx context.Context, fromID, toID int, at time.Time) error {
+	if r.db == nil {
+		return fmt.Errorf("no database connection available")
+	}
+
+	_, err := r.db.ExecContext(ctx,
+		r.db.Rebind("INSERT INTO friends (user_id, friend_id, created_at) VALUES (?, ?, ?)"),
+		fromID, toID, at.UTC(),
+	)
+	if err != nil {
+		return fmt.Errorf("create friend request: %w", err)
+	}
+
+	return nil
+}
+
+// Accept accepts a friend request
+func (r *FriendRepository) Accept(ctx context.Context, fromID, toID int, at time.Time) error {
+	if r.db == nil {
+		return fmt.Errorf("no database connection available")
+	}
+
+	result, err := r.db.ExecContext(ctx,
+		r.db.Rebind("UPDATE friends SET accepted_at = ? WHERE user_id = ? AND friend_id = ? AND accepted_at IS NULL"),
+		at.UTC(), fromID, toID,
+	)
+	if err != nil {
+		return fmt.Errorf("accept friend request: %w", err)
+	}
+
+	accepted, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("get rows affected: %w", err)
+	}
+
+	if accepted == 0 {
+		return ErrNoFriendRequest
+	}
+
+	return nil
+}
+
+// Remove deletes the friendship or request between two users
+func (r *FriendRepository) Remove(ctx context.Context, userID, otherID int) (bool, error) {
+	if r.db == nil {
+		return false, fmt.Errorf("no database connection available")
+	}
+
+	result, err := r.db.ExecContext(ctx,
+		r.db.Rebind("DELETE FROM friends WHERE (user_id = ? AND friend_id = ?) OR (user_id = ? AND friend_id = ?)"),
+		userID, otherID, otherID, userID,
+	)
+	if err != nil {
+		return false, fmt.Errorf("delete friend: %w", err)
+	}
+
+	removed, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("get rows affected: %w", err)
+	}
+
+	return removed > 0, nil
+}
+
+// List retrieves the friends and friend requests of a user
+func (r *FriendRepository) List(ctx context.Context, userID int) ([]models.Friend, error) {
+	if r.db == nil {
+		return nil, fmt.Errorf("no database connection available")
+	}
+
+	query := `SELECT ` + friendColumns + `
+		WHERE f.user_id = ? OR f.friend_id = ?
+		ORDER BY f.accepted_at IS NULL, LOWER(u.name), u.id`
+
+	var friends []models.Friend
+	if err := r.db.SelectContext(ctx, &friends, r.db.Rebind(query), userID, userID, userID, userID); err != nil {
+		return nil, fmt.Errorf("list friends: %w", err)
+	}
+
+	return friends, nil
+}
@@ -0,0 +1,130 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kirkegaard/terminal-pet/pkg/db"
+	"github.com/kirkegaard/terminal-pet/pkg/db/models"
+)
+
+type GiftRepository struct {
+	db *db.DB
+}
+
+func NewGiftRepository(database *db.DB) *GiftRepository {
+	return &GiftRepository{
+		db: database,
+	}
+}
+
+// Send takes the item out of the sender's inventory and stores the gift in
+// a single transaction
+func (r *GiftRepository) Send(ctx context.Context, gift *models.Gift) error {
+	if r.db == nil {
+		return fmt.Errorf("no database connection available")
+	}
+
+	if gift.CreatedAt.IsZero() {
+		gift.CreatedAt = time.Now()
+	}
+	gift.CreatedAt = gift.CreatedAt.UTC()
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx,
+		tx.Rebind("UPDATE inventory SET quantity = quantity - 1 WHERE user_id = ? AND item_id = ? AND quantity > 0"),
+		gift.FromUserID, gift.ItemID,
+	)
+	if err != nil {
+		return fmt.Errorf("take gift from inventory: %w", err)
+	}
+
+	taken, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("get rows affected: %w", err)
+	}
+
+	if taken == 0 {
+		return ErrOutOfStock
+	}
+
+	err = tx.QueryRowContext(ctx,
+		tx.Rebind("INSERT INTO gifts (from_user_id, to_user_id, item_id, created_at) VALUES (?, ?, ?, ?) RETURNING id"),
+		gift.FromUserID, gift.ToUserID, gift.ItemID, gift.CreatedAt,
+	).Scan(&gift.ID)
+	if err != nil {
+		return fmt.Errorf("create gift: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit gift: %w", err)
+	}
+
+	return nil
+}
+
+// Deliver adds the undelivered gifts of a user to their inventory in a
+// single transaction. Gifts delivered by another session in the meantime
+// are left out.
+func (r *GiftRepository) Deliver(ctx context.Context, userID int, at time.Time) ([]models.Gift, error) {
+	if r.db == nil {
+		return nil, fmt.Errorf("no database connection available")
+	}
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query := `
+		SELECT g.id, g.from_user_id, g.to_user_id, g.item_id, g.created_at, u.name AS from_name
+		FROM gifts g
+		JOIN users u ON u.id = g.from_user_id
+		WHERE g.to_user_id = ? AND g.delivered_at IS NULL
+		ORDER BY g.created_at, g.id`
+
+	var pending []models.Gift
+	if err := tx.SelectContext(ctx, &pending, tx.Rebind(query), userID); err != nil {
+		return nil, fmt.Errorf("list gifts: %w", err)
+	}
+
+	var delivered []models.Gift
+	for _, gift := range pending {
+		result, err := tx.ExecContext(ctx,
+			tx.Rebind("UPDATE gifts SET delivered_at = ? WHERE id = ? AND delivered_at IS NULL"),
+			at.UTC(), gift.ID,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("deliver gift: %w", err)
+		}
+
+		updated, err := result.RowsAffected()
+		if err != nil {
+			return nil, fmt.Errorf("get rows affected: %w", err)
+		}
+		if updated == 0 {
+			continue
+		}
+
+		if err := addItem(ctx, tx, tx.Rebind, userID, gift.ItemID, 1); err != nil {
+			return nil, fmt.Errorf("add item: %w", err)
+		}
+
+		gift.DeliveredAt.Time = at.UTC()
+		gift.DeliveredAt.Valid = true
+		delivered = append(delivered, gift)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit gifts: %w", err)
+	}
+
+	return delivered, nil
+}
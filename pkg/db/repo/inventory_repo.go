@@ -0,0 +1,206 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/kirkegaard/terminal-pet/pkg/db"
+)
+
+type InventoryRepository struct {
+	db *db.DB
+}
+
+func NewInventoryRepository(database *db.DB) *InventoryRepository {
+	return &InventoryRepository{
+		db: database,
+	}
+}
+
+// Coins retrieves the coin balance of a user
+func (r *InventoryRepository) Coins(ctx context.Context, userID int) (int, error) {
+	if r.db == nil {
+		return 0, fmt.Errorf("no database connection available")
+	}
+
+	var coins int
+	err := r.db.QueryRowContext(ctx, r.db.Rebind("SELECT coins FROM users WHERE id = ?"), userID).Scan(&coins)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("find coins: %w", err)
+	}
+
+	return coins, nil
+}
+
+// AddCoins adds coins to the balance of a user
+func (r *InventoryRepository) AddCoins(ctx context.Context, userID int, amount int) (int, error) {
+	if r.db == nil {
+		return 0, fmt.Errorf("no database connection available")
+	}
+
+	var coins int
+	err := r.db.QueryRowContext(ctx,
+		r.db.Rebind("UPDATE users SET coins = coins + ? WHERE id = ? RETURNING coins"),
+		amount, userID,
+	).Scan(&coins)
+	if err != nil {
+		return 0, fmt.Errorf("add coins: %w", err)
+	}
+
+	return coins, nil
+}
+
+// ClaimDailyReward adds the daily reward to the balance of a user unless it
+// was already claimed on the same day
+func (r *InventoryRepository) ClaimDailyReward(ctx context.Context, userID int, amount int, now time.Time) (bool, error) {
+	if r.db == nil {
+		return false, fmt.Errorf("no database connection available")
+	}
+
+	now = now.UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	result, err := r.db.ExecContext(ctx,
+		r.db.Rebind(`UPDATE users SET coins = coins + ?, daily_reward_at = ?
+			WHERE id = ? AND (daily_reward_at IS NULL OR daily_reward_at < ?)`),
+		amount, now, userID, today,
+	)
+	if err != nil {
+		return false, fmt.Errorf("claim daily reward: %w", err)
+	}
+
+	claimed, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("get rows affected: %w", err)
+	}
+
+	return claimed > 0, nil
+}
+
+// Items retrieves how many of each item a user owns
+func (r *InventoryRepository) Items(ctx context.Context, userID int) (map[string]int, error) {
+	if r.db == nil {
+		return nil, fmt.Errorf("no database connection available")
+	}
+
+	rows, err := r.db.QueryContext(ctx,
+		r.db.Rebind("SELECT item_id, quantity FROM inventory WHERE user_id = ? AND quantity > 0"),
+		userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list inventory: %w", err)
+	}
+	defer rows.Close()
+
+	items := make(map[string]int)
+	for rows.Next() {
+		var itemID string
+		var quantity int
+		if err := rows.Scan(&itemID, &quantity); err != nil {
+			return nil, fmt.Errorf("scan inventory: %w", err)
+		}
+		items[itemID] = quantity
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list inventory: %w", err)
+	}
+
+	return items, nil
+}
+
+// AddItem adds items to the inventory of a user
+func (r *InventoryRepository) AddItem(ctx context.Context, userID int, itemID string, quantity int) error {
+	if r.db == nil {
+		return fmt.Errorf("no database connection available")
+	}
+
+	if err := addItem(ctx, r.db, r.db.Rebind, userID, itemID, quantity); err != nil {
+		return fmt.Errorf("add item: %w", err)
+	}
+
+	return nil
+}
+
+// UseItem takes one item out of the inventory of a user
+func (r *InventoryRepository) UseItem(ctx context.Context, userID int, itemID string) error {
+	if r.db == nil {
+		return fmt.Errorf("no database connection available")
+	}
+
+	result, err := r.db.ExecContext(ctx,
+		r.db.Rebind("UPDATE inventory SET quantity = quantity - 1 WHERE user_id = ? AND item_id = ? AND quantity > 0"),
+		userID, itemID,
+	)
+	if err != nil {
+		return fmt.Errorf("use item: %w", err)
+	}
+
+	used, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("get rows affected: %w", err)
+	}
+
+	if used == 0 {
+		return ErrOutOfStock
+	}
+
+	return nil
+}
+
+// Buy takes the price from the balance of a user and adds the item to their
+// inventory in a single transaction
+func (r *InventoryRepository) Buy(ctx context.Context, userID int, itemID string, price int) (int, error) {
+	if r.db == nil {
+		return 0, fmt.Errorf("no database connection available")
+	}
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var coins int
+	err = tx.QueryRowContext(ctx,
+		tx.Rebind("UPDATE users SET coins = coins - ? WHERE id = ? AND coins >= ? RETURNING coins"),
+		price, userID, price,
+	).Scan(&coins)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return 0, ErrNotEnoughCoins
+		}
+		return 0, fmt.Errorf("pay for item: %w", err)
+	}
+
+	if err := addItem(ctx, tx, tx.Rebind, userID, itemID, 1); err != nil {
+		return 0, fmt.Errorf("add item: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("commit purchase: %w", err)
+	}
+
+	return coins, nil
+}
+
+// execer is implemented by both the database and its transactions
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// addItem adds to the quantity of an item, creating its row if needed
+func addItem(ctx context.Context, e execer, rebind func(string) string, userID int, itemID string, quantity int) error {
+	_, err := e.ExecContext(ctx,
+		rebind(`INSERT INTO inventory (user_id, item_id, quantity) VALUES (?, ?, ?)
+			ON CONFLICT (user_id, item_id) DO UPDATE SET quantity = inventory.quantity + excluded.quantity`),
+		userID, itemID, quantity,
+	)
+
+	return err
+}
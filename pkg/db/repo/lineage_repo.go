@@ -0,0 +1,74 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kirkegaard/terminal-pet/pkg/db"
+)
+
+type LineageRepository struct {
+	db *db.DB
+}
+
+func NewLineageRepository(database *db.DB) *LineageRepository {
+	return &LineageRepository{
+		db: database,
+	}
+}
+
+// AddParents records the parents of a bred pet
+func (r *LineageRepository) AddParents(ctx context.Context, petID int, parentIDs ...int) error {
+	if r.db == nil {
+		return fmt.Errorf("no database connection available")
+	}
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, parentID := range parentIDs {
+		_, err := tx.ExecContext(ctx, tx.Rebind("INSERT INTO pet_parents (pet_id, parent_id) VALUES (?, ?)"), petID, parentID)
+		if err != nil {
+			return fmt.Errorf("add pet parent: %w", err)
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit pet parents: %w", err)
+	}
+
+	return nil
+}
+
+// Parents retrieves the IDs of the parents of a pet
+func (r *LineageRepository) Parents(ctx context.Context, petID int) ([]int, error) {
+	if r.db == nil {
+		return nil, fmt.Errorf("no database connection available")
+	}
+
+	var ids []int
+	err := r.db.SelectContext(ctx, &ids, r.db.Rebind("SELECT parent_id FROM pet_parents WHERE pet_id = ? ORDER BY parent_id"), petID)
+	if err != nil {
+		return nil, fmt.Errorf("list pet parents: %w", err)
+	}
+
+	return ids, nil
+}
+
+// Children retrieves the IDs of the babies of a pet, oldest first
+func (r *LineageRepository) Children(ctx context.Context, petID int) ([]int, error) {
+	if r.db == nil {
+		return nil, fmt.Errorf("no database connection available")
+	}
+
+	var ids []int
+	err := r.db.SelectContext(ctx, &ids, r.db.Rebind("SELECT pet_id FROM pet_parents WHERE parent_id = ? ORDER BY pet_id"), petID)
+	if err != nil {
+		return nil, fmt.Errorf("list pet children: %w", err)
+	}
+
+	return ids, nil
+}
@@ -0,0 +1,1145 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kirkegaard/terminal-pet/pkg/db/models"
+	"github.com/kirkegaard/terminal-pet/pkg/pet"
+)
+
+// MemoryUserRepository is an in-memory UserStore, mainly used in tests
+type MemoryUserRepository struct {
+	mu     sync.Mutex
+	users  []models.User
+	nextID int
+}
+
+func NewMemoryUserRepository() *MemoryUserRepository {
+	return &MemoryUserRepository{nextID: 1}
+}
+
+// GetByPublicKey retrieves a user ID by public key
+func (r *MemoryUserRepository) GetByPublicKey(ctx context.Context, publicKey string) (int, error) {
+	user, err := r.FindByPublicKey(ctx, publicKey)
+	if err != nil || user == nil {
+		return 0, err
+	}
+
+	return user.ID, nil
+}
+
+// Create creates a new user
+func (r *MemoryUserRepository) Create(ctx context.Context, name, publicKey string) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, u := range r.users {
+		if u.PublicKey == publicKey {
+			return 0, fmt.Errorf("create user: public key already exists")
+		}
+	}
+
+	user := models.User{
+		ID:        r.nextID,
+		Name:      name,
+		PublicKey: publicKey,
+	}
+	r.nextID++
+	r.users = append(r.users, user)
+
+	return user.ID, nil
+}
+
+func (r *MemoryUserRepository) FindByPublicKey(ctx context.Context, publicKey string) (*models.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, u := range r.users {
+		if u.PublicKey == publicKey {
+			user := u
+			return &user, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// FindByID retrieves a user by ID
+func (r *MemoryUserRepository) FindByID(ctx context.Context, id int) (*models.User, error) {
+	user, ok := r.find(id)
+	if !ok {
+		return nil, nil
+	}
+
+	return &user, nil
+}
+
+// FindByName retrieves the user created with a name, ignoring case
+func (r *MemoryUserRepository) FindByName(ctx context.Context, name string) (*models.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var found *models.User
+	for _, u := range r.users {
+		if !strings.EqualFold(u.Name, name) {
+			continue
+		}
+		if found != nil {
+			return nil, ErrAmbiguousName
+		}
+		user := u
+		found = &user
+	}
+
+	return found, nil
+}
+
+// SetPrivate changes whether other players may visit the user's pets
+func (r *MemoryUserRepository) SetPrivate(ctx context.Context, userID int, private bool) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.users {
+		if r.users[i].ID == userID {
+			r.users[i].Private = private
+			return nil
+		}
+	}
+
+	return fmt.Errorf("update user privacy: user %d not found", userID)
+}
+
+// SetLastSeen records when the user was last around
+func (r *MemoryUserRepository) SetLastSeen(ctx context.Context, userID int, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.users {
+		if r.users[i].ID == userID {
+			r.users[i].LastSeenAt = sql.NullTime{Time: at, Valid: true}
+			return nil
+		}
+	}
+
+	return fmt.Errorf("update user last seen: user %d not found", userID)
+}
+
+// find returns a copy of the user with the given ID
+func (r *MemoryUserRepository) find(userID int) (models.User, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, u := range r.users {
+		if u.ID == userID {
+			return u, true
+		}
+	}
+
+	return models.User{}, false
+}
+
+// memoryPet is a stored pet along with its bookkeeping columns
+type memoryPet struct {
+	pet       pet.Pet
+	createdAt time.Time
+	updatedAt time.Time
+}
+
+// MemoryPetRepository is an in-memory PetStore, mainly used in tests. Pets
+// are copied on the way in and out so callers never share state with the
+// store, just like with a database.
+type MemoryPetRepository struct {
+	mu       sync.Mutex
+	pets     map[int]*memoryPet
+	nextID   int
+	userRepo UserStore
+	clock    pet.Clock
+}
+
+func NewMemoryPetRepository(userRepo UserStore) *MemoryPetRepository {
+	if userRepo == nil {
+		userRepo = NewMemoryUserRepository()
+	}
+
+	return &MemoryPetRepository{
+		pets:     make(map[int]*memoryPet),
+		nextID:   1,
+		userRepo: userRepo,
+		clock:    pet.SystemClock,
+	}
+}
+
+// WithClock sets the clock used for the created and updated timestamps
+func (r *MemoryPetRepository) WithClock(clock pet.Clock) *MemoryPetRepository {
+	r.clock = clock
+	return r
+}
+
+// copyPet returns a copy of the pet that does not share its parent. Events
+// recorded on the pet stay with the original.
+func copyPet(p *pet.Pet) *pet.Pet {
+	c := *p
+	c.TakeEvents()
+	c.Genes.Traits = slices.Clone(p.Genes.Traits)
+	if p.Parent != nil {
+		parent := *p.Parent
+		c.Parent = &parent
+	}
+	return &c
+}
+
+// load returns a copy of a stored pet as it would be read from a database
+func (r *MemoryPetRepository) load(stored *memoryPet) *pet.Pet {
+	p := copyPet(&stored.pet)
+	p.LastVisit = stored.updatedAt
+	if p.SimulatedAt.IsZero() {
+		p.SimulatedAt = stored.updatedAt
+	}
+	settleCharacter(p)
+	return p
+}
+
+// Create creates a new pet
+func (r *MemoryPetRepository) Create(ctx context.Context, p *pet.Pet) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if p.Parent == nil {
+		return fmt.Errorf("create pet: pet has no parent")
+	}
+
+	p.ID = r.nextID
+	r.nextID++
+
+	now := r.clock.Now().UTC()
+	r.pets[p.ID] = &memoryPet{
+		pet:       *copyPet(p),
+		createdAt: now,
+		updatedAt: now,
+	}
+
+	return nil
+}
+
+// GetByID retrieves a pet by its ID
+func (r *MemoryPetRepository) GetByID(ctx context.Context, id int) (*pet.Pet, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.pets[id]
+	if !ok {
+		return nil, nil
+	}
+
+	return r.load(stored), nil
+}
+
+// GetByParentID retrieves the newest pet of a parent
+func (r *MemoryPetRepository) GetByParentID(ctx context.Context, parentID int) (*pet.Pet, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var newest *memoryPet
+	for _, stored := range r.pets {
+		if stored.pet.Parent.ID != parentID {
+			continue
+		}
+
+		if newest == nil || stored.createdAt.After(newest.createdAt) ||
+			(stored.createdAt.Equal(newest.createdAt) && stored.pet.ID > newest.pet.ID) {
+			newest = stored
+		}
+	}
+
+	if newest == nil {
+		return nil, nil
+	}
+
+	return r.load(newest), nil
+}
+
+// ListAlive retrieves every pet that is still alive
+func (r *MemoryPetRepository) ListAlive(ctx context.Context) ([]*pet.Pet, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	pets := make([]*pet.Pet, 0, len(r.pets))
+	for _, stored := range r.pets {
+		if stored.pet.Health > 0 {
+			pets = append(pets, r.load(stored))
+		}
+	}
+
+	sort.Slice(pets, func(i, j int) bool {
+		return pets[i].ID < pets[j].ID
+	})
+
+	return pets, nil
+}
+
+// ListAliveByParentID retrieves the living pets of a parent, oldest first
+func (r *MemoryPetRepository) ListAliveByParentID(ctx context.Context, parentID int) ([]*pet.Pet, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := make([]*memoryPet, 0)
+	for _, s := range r.pets {
+		if s.pet.Parent.ID == parentID && s.pet.Health > 0 {
+			stored = append(stored, s)
+		}
+	}
+
+	sort.Slice(stored, func(i, j int) bool {
+		if stored[i].createdAt.Equal(stored[j].createdAt) {
+			return stored[i].pet.ID < stored[j].pet.ID
+		}
+		return stored[i].createdAt.Before(stored[j].createdAt)
+	})
+
+	pets := make([]*pet.Pet, 0, len(stored))
+	for _, s := range stored {
+		pets = append(pets, r.load(s))
+	}
+
+	return pets, nil
+}
+
+// ListDeadByParentID retrieves the deceased pets of a parent, most recent first
+func (r *MemoryPetRepository) ListDeadByParentID(ctx context.Context, parentID int) ([]*pet.Pet, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	pets := make([]*pet.Pet, 0)
+	for _, s := range r.pets {
+		if s.pet.Parent.ID == parentID && s.pet.Health <= 0 {
+			pets = append(pets, r.load(s))
+		}
+	}
+
+	diedAt := func(p *pet.Pet) time.Time {
+		if p.DiedAt.IsZero() {
+			return p.LastVisit
+		}
+		return p.DiedAt
+	}
+
+	sort.Slice(pets, func(i, j int) bool {
+		if diedAt(pets[i]).Equal(diedAt(pets[j])) {
+			return pets[i].ID > pets[j].ID
+		}
+		return diedAt(pets[i]).After(diedAt(pets[j]))
+	})
+
+	return pets, nil
+}
+
+// SetEpitaph stores the epitaph of a deceased pet
+func (r *MemoryPetRepository) SetEpitaph(ctx context.Context, id int, epitaph string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if stored, ok := r.pets[id]; ok && stored.pet.Health <= 0 {
+		stored.pet.Epitaph = epitaph
+	}
+
+	return nil
+}
+
+// Update updates an existing pet, unless it was saved by someone else since
+// it was loaded
+func (r *MemoryPetRepository) Update(ctx context.Context, p *pet.Pet) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.pets[p.ID]
+	if !ok || p.Parent == nil || stored.pet.Parent.ID != p.Parent.ID || stored.pet.Revision != p.Revision {
+		return ErrPetConflict
+	}
+
+	// The epitaph is only changed through SetEpitaph
+	epitaph := stored.pet.Epitaph
+	p.Revision = stored.pet.Revision + 1
+	stored.pet = *copyPet(p)
+	stored.pet.Epitaph = epitaph
+	stored.updatedAt = r.clock.Now().UTC()
+
+	return nil
+}
+
+// UpdateSimulated stores a pet advanced by the world simulation
+func (r *MemoryPetRepository) UpdateSimulated(ctx context.Context, p *pet.Pet) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.pets[p.ID]
+	if !ok || stored.pet.Revision != p.Revision {
+		return false, nil
+	}
+
+	stored.pet.Hunger = p.Hunger
+	stored.pet.Happiness = p.Happiness
+	stored.pet.Health = p.Health
+	stored.pet.Weight = p.Weight
+	stored.pet.IsSick = p.IsSick
+	stored.pet.HasPooped = p.HasPooped
+	stored.pet.Discipline = p.Discipline
+	stored.pet.DiedAt = p.DiedAt
+	stored.pet.CauseOfDeath = p.CauseOfDeath
+	stored.pet.Misbehavior = p.Misbehavior
+	stored.pet.MisbehavedAt = p.MisbehavedAt
+	stored.pet.MissedCalls = p.MissedCalls
+	stored.pet.CharacterID = p.CharacterID
+	stored.pet.CareSteps = p.CareSteps
+	stored.pet.HungerTotal = p.HungerTotal
+	stored.pet.SimulatedAt = p.SimulatedAt
+	stored.pet.Revision++
+	stored.updatedAt = r.clock.Now().UTC()
+	p.Revision = stored.pet.Revision
+
+	return true, nil
+}
+
+// Delete removes a pet
+func (r *MemoryPetRepository) Delete(ctx context.Context, id int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.pets, id)
+
+	return nil
+}
+
+// FindByParentPublicKey retrieves a pet by the parent's public key
+func (r *MemoryPetRepository) FindByParentPublicKey(ctx context.Context, publicKey string) (*pet.Pet, error) {
+	userID, err := r.userRepo.GetByPublicKey(ctx, publicKey)
+	if err != nil || userID == 0 {
+		return nil, err
+	}
+
+	return r.GetByParentID(ctx, userID)
+}
+
+// Save either creates or updates a pet
+func (r *MemoryPetRepository) Save(ctx context.Context, p *pet.Pet, publicKey string) error {
+	return savePet(ctx, r, r.userRepo, p, publicKey)
+}
+
+// MemoryTokenRepository is an in-memory TokenStore, mainly used in tests
+type MemoryTokenRepository struct {
+	mu     sync.Mutex
+	tokens map[string]int
+}
+
+func NewMemoryTokenRepository() *MemoryTokenRepository {
+	return &MemoryTokenRepository{tokens: make(map[string]int)}
+}
+
+// Create stores the hash of a new API token for a user
+func (r *MemoryTokenRepository) Create(ctx context.Context, userID int, tokenHash string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.tokens[tokenHash]; ok {
+		return fmt.Errorf("create api token: token already exists")
+	}
+
+	r.tokens[tokenHash] = userID
+
+	return nil
+}
+
+// GetUserID retrieves the ID of the user owning a token hash
+func (r *MemoryTokenRepository) GetUserID(ctx context.Context, tokenHash string) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.tokens[tokenHash], nil
+}
+
+// DeleteByUserID revokes every token of a user
+func (r *MemoryTokenRepository) DeleteByUserID(ctx context.Context, userID int) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	deleted := 0
+	for hash, id := range r.tokens {
+		if id == userID {
+			delete(r.tokens, hash)
+			deleted++
+		}
+	}
+
+	return deleted, nil
+}
+
+// MemoryInventoryRepository is an in-memory InventoryStore, mainly used in
+// tests. Every user starts with StartingCoins.
+type MemoryInventoryRepository struct {
+	mu       sync.Mutex
+	coins    map[int]int
+	rewarded map[int]time.Time
+	items    map[int]map[string]int
+}
+
+func NewMemoryInventoryRepository() *MemoryInventoryRepository {
+	return &MemoryInventoryRepository{
+		coins:    make(map[int]int),
+		rewarded: make(map[int]time.Time),
+		items:    make(map[int]map[string]int),
+	}
+}
+
+// balance returns the coins of a user, the caller must hold the lock
+func (r *MemoryInventoryRepository) balance(userID int) int {
+	coins, ok := r.coins[userID]
+	if !ok {
+		return StartingCoins
+	}
+
+	return coins
+}
+
+// Coins retrieves the coin balance of a user
+func (r *MemoryInventoryRepository) Coins(ctx context.Context, userID int) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return r.balance(userID), nil
+}
+
+// AddCoins adds coins to the balance of a user
+func (r *MemoryInventoryRepository) AddCoins(ctx context.Context, userID int, amount int) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.coins[userID] = r.balance(userID) + amount
+
+	return r.coins[userID], nil
+}
+
+// ClaimDailyReward adds the daily reward to the balance of a user unless it
+// was already claimed on the same day
+func (r *MemoryInventoryRepository) ClaimDailyReward(ctx context.Context, userID int, amount int, now time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	now = now.UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+
+	if last, ok := r.rewarded[userID]; ok && !last.Before(today) {
+		return false, nil
+	}
+
+	r.rewarded[userID] = now
+	r.coins[userID] = r.balance(userID) + amount
+
+	return true, nil
+}
+
+// Items retrieves how many of each item a user owns
+func (r *MemoryInventoryRepository) Items(ctx context.Context, userID int) (map[string]int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	items := make(map[string]int)
+	for id, quantity := range r.items[userID] {
+		if quantity > 0 {
+			items[id] = quantity
+		}
+	}
+
+	return items, nil
+}
+
+// AddItem adds items to the inventory of a user
+func (r *MemoryInventoryRepository) AddItem(ctx context.Context, userID int, itemID string, quantity int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.addItem(userID, itemID, quantity)
+
+	return nil
+}
+
+// addItem adds to the quantity of an item, the caller must hold the lock
+func (r *MemoryInventoryRepository) addItem(userID int, itemID string, quantity int) {
+	if r.items[userID] == nil {
+		r.items[userID] = make(map[string]int)
+	}
+
+	r.items[userID][itemID] += quantity
+}
+
+// UseItem takes one item out of the inventory of a user
+func (r *MemoryInventoryRepository) UseItem(ctx context.Context, userID int, itemID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.items[userID][itemID] <= 0 {
+		return ErrOutOfStock
+	}
+
+	r.items[userID][itemID]--
+
+	return nil
+}
+
+// Buy takes the price from the balance of a user and adds the item to their
+// inventory
+func (r *MemoryInventoryRepository) Buy(ctx context.Context, userID int, itemID string, price int) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	coins := r.balance(userID)
+	if coins < price {
+		return 0, ErrNotEnoughCoins
+	}
+
+	r.coins[userID] = coins - price
+	r.addItem(userID, itemID, 1)
+
+	return r.coins[userID], nil
+}
+
+// MemoryScoreRepository is an in-memory ScoreStore, mainly used in tests.
+// The names of the user and pet are kept as they were added.
+type MemoryScoreRepository struct {
+	mu     sync.Mutex
+	nextID int
+	scores []models.GameScore
+}
+
+func NewMemoryScoreRepository() *MemoryScoreRepository {
+	return &MemoryScoreRepository{nextID: 1}
+}
+
+// Add stores a new game score and sets its ID
+func (r *MemoryScoreRepository) Add(ctx context.Context, score *models.GameScore) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if score.CreatedAt.IsZero() {
+		score.CreatedAt = time.Now().UTC()
+	}
+
+	score.ID = r.nextID
+	r.nextID++
+	r.scores = append(r.scores, *score)
+
+	return nil
+}
+
+// Best retrieves the highest score of a user in a game
+func (r *MemoryScoreRepository) Best(ctx context.Context, userID int, game string) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	best := 0
+	for _, s := range r.scores {
+		if s.UserID == userID && s.Game == game {
+			best = max(best, s.Score)
+		}
+	}
+
+	return best, nil
+}
+
+// Top retrieves the leaderboard of a game. Only the earliest of the best
+// scores of each user is listed.
+func (r *MemoryScoreRepository) Top(ctx context.Context, game string, limit int) ([]models.GameScore, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Scores are stored in the order they were added, so the first best
+	// score of a user is also the earliest
+	best := make(map[int]models.GameScore)
+	for _, s := range r.scores {
+		if s.Game != game {
+			continue
+		}
+		if b, ok := best[s.UserID]; !ok || s.Score > b.Score {
+			best[s.UserID] = s
+		}
+	}
+
+	scores := make([]models.GameScore, 0, len(best))
+	for _, s := range best {
+		scores = append(scores, s)
+	}
+
+	sort.Slice(scores, func(i, j int) bool {
+		if scores[i].Score != scores[j].Score {
+			return scores[i].Score > scores[j].Score
+		}
+		return scores[i].ID < scores[j].ID
+	})
+
+	if len(scores) > limit {
+		scores = scores[:limit]
+	}
+
+	return scores, nil
+}
+
+// MemoryAchievementRepository is an in-memory AchievementStore, mainly used
+// in tests
+type MemoryAchievementRepository struct {
+	mu           sync.Mutex
+	achievements map[int]map[string]*models.Achievement
+}
+
+func NewMemoryAchievementRepository() *MemoryAchievementRepository {
+	return &MemoryAchievementRepository{achievements: make(map[int]map[string]*models.Achievement)}
+}
+
+// achievement returns the progress of a user towards an achievement,
+// creating it if needed. The caller must hold the lock.
+func (r *MemoryAchievementRepository) achievement(userID int, achievementID string) *models.Achievement {
+	if r.achievements[userID] == nil {
+		r.achievements[userID] = make(map[string]*models.Achievement)
+	}
+
+	a, ok := r.achievements[userID][achievementID]
+	if !ok {
+		a = &models.Achievement{UserID: userID, AchievementID: achievementID}
+		r.achievements[userID][achievementID] = a
+	}
+
+	return a
+}
+
+// List retrieves the progress of a user towards their achievements
+func (r *MemoryAchievementRepository) List(ctx context.Context, userID int) ([]models.Achievement, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	achievements := make([]models.Achievement, 0, len(r.achievements[userID]))
+	for _, a := range r.achievements[userID] {
+		achievements = append(achievements, *a)
+	}
+
+	return achievements, nil
+}
+
+// AddProgress adds to the progress of a user towards an achievement
+func (r *MemoryAchievementRepository) AddProgress(ctx context.Context, userID int, achievementID string, amount int) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	a := r.achievement(userID, achievementID)
+	a.Progress += amount
+
+	return a.Progress, nil
+}
+
+// Unlock records when a user unlocked an achievement, unless they already
+// had
+func (r *MemoryAchievementRepository) Unlock(ctx context.Context, userID int, achievementID string, at time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	a := r.achievement(userID, achievementID)
+	if a.UnlockedAt.Valid {
+		return false, nil
+	}
+
+	a.UnlockedAt = sql.NullTime{Time: at.UTC(), Valid: true}
+
+	return true, nil
+}
+
+// MemoryPetEventRepository is an in-memory PetEventStore, mainly used in
+// tests
+type MemoryPetEventRepository struct {
+	mu     sync.Mutex
+	nextID int
+	events []models.PetEvent
+}
+
+func NewMemoryPetEventRepository() *MemoryPetEventRepository {
+	return &MemoryPetEventRepository{nextID: 1}
+}
+
+// Add appends an entry to the activity log of a pet and sets its ID
+func (r *MemoryPetEventRepository) Add(ctx context.Context, event *models.PetEvent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now()
+	}
+	event.CreatedAt = event.CreatedAt.UTC()
+
+	event.ID = r.nextID
+	r.nextID++
+	r.events = append(r.events, *event)
+
+	return nil
+}
+
+// ListByPetID retrieves the latest entries in the activity log of a pet
+func (r *MemoryPetEventRepository) ListByPetID(ctx context.Context, petID int, limit int) ([]models.PetEvent, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var events []models.PetEvent
+	for _, e := range r.events {
+		if e.PetID == petID {
+			events = append(events, e)
+		}
+	}
+
+	sort.Slice(events, func(i, j int) bool {
+		if !events[i].CreatedAt.Equal(events[j].CreatedAt) {
+			return events[i].CreatedAt.After(events[j].CreatedAt)
+		}
+		return events[i].ID > events[j].ID
+	})
+
+	if len(events) > limit {
+		events = events[:limit]
+	}
+
+	return events, nil
+}
+
+// MemoryStatRepository is an in-memory StatStore, mainly used in tests
+type MemoryStatRepository struct {
+	mu        sync.Mutex
+	snapshots []models.StatSnapshot
+}
+
+func NewMemoryStatRepository() *MemoryStatRepository {
+	return &MemoryStatRepository{}
+}
+
+// index returns the index of the snapshot stored for the bucket of a
+// snapshot, or -1 if there is none. The caller must hold the lock.
+func (r *MemoryStatRepository) index(s models.StatSnapshot) int {
+	for i, stored := range r.snapshots {
+		if stored.PetID == s.PetID && stored.BucketSeconds == s.BucketSeconds && stored.At.Equal(s.At) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// Add stores a stat snapshot unless its bucket already has one
+func (r *MemoryStatRepository) Add(ctx context.Context, s *models.StatSnapshot) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored := *s
+	stored.At = stored.At.UTC()
+	stored.Samples = max(stored.Samples, 1)
+	if r.index(stored) < 0 {
+		r.snapshots = append(r.snapshots, stored)
+	}
+
+	return nil
+}
+
+// ListByPetID retrieves the stat snapshots of a pet since the given time
+func (r *MemoryStatRepository) ListByPetID(ctx context.Context, petID int, since time.Time) ([]models.StatSnapshot, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var snapshots []models.StatSnapshot
+	for _, s := range r.snapshots {
+		if s.PetID == petID && !s.At.Before(since) {
+			snapshots = append(snapshots, s)
+		}
+	}
+
+	sort.Slice(snapshots, func(i, j int) bool {
+		if !snapshots[i].At.Equal(snapshots[j].At) {
+			return snapshots[i].At.Before(snapshots[j].At)
+		}
+		return snapshots[i].BucketSeconds > snapshots[j].BucketSeconds
+	})
+
+	return snapshots, nil
+}
+
+// Compact replaces the snapshots in buckets smaller than the given size
+// that start before the given time by their averages, merged into the
+// averages of earlier compactions
+func (r *MemoryStatRepository) Compact(ctx context.Context, bucket time.Duration, before time.Time) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	seconds := int(bucket / time.Second)
+
+	var old, kept []models.StatSnapshot
+	for _, s := range r.snapshots {
+		if s.BucketSeconds < seconds && s.At.Before(before) {
+			old = append(old, s)
+		} else {
+			kept = append(kept, s)
+		}
+	}
+
+	r.snapshots = kept
+	for _, s := range averageSnapshots(old, bucket) {
+		if i := r.index(s); i >= 0 {
+			r.snapshots[i] = averageSnapshots([]models.StatSnapshot{r.snapshots[i], s}, bucket)[0]
+			continue
+		}
+		r.snapshots = append(r.snapshots, s)
+	}
+
+	return len(old), nil
+}
+
+// MemoryLineageRepository is an in-memory LineageStore, mainly used in tests
+type MemoryLineageRepository struct {
+	mu      sync.Mutex
+	parents map[int][]int
+}
+
+func NewMemoryLineageRepository() *MemoryLineageRepository {
+	return &MemoryLineageRepository{
+		parents: make(map[int][]int),
+	}
+}
+
+// AddParents records the parents of a bred pet
+func (r *MemoryLineageRepository) AddParents(ctx context.Context, petID int, parentIDs ...int) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, parentID := range parentIDs {
+		if slices.Contains(r.parents[petID], parentID) {
+			return fmt.Errorf("add pet parent: %d is already a parent of %d", parentID, petID)
+		}
+		r.parents[petID] = append(r.parents[petID], parentID)
+	}
+	sort.Ints(r.parents[petID])
+
+	return nil
+}
+
+// Parents retrieves the IDs of the parents of a pet
+func (r *MemoryLineageRepository) Parents(ctx context.Context, petID int) ([]int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return slices.Clone(r.parents[petID]), nil
+}
+
+// Children retrieves the IDs of the babies of a pet, oldest first
+func (r *MemoryLineageRepository) Children(ctx context.Context, petID int) ([]int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var ids []int
+	for id, parents := range r.parents {
+		if slices.Contains(parents, petID) {
+			ids = append(ids, id)
+		}
+	}
+	sort.Ints(ids)
+
+	return ids, nil
+}
+
+// memoryFriendship is a stored friend request, accepted or not
+type memoryFriendship struct {
+	fromID     int
+	toID       int
+	createdAt  time.Time
+	acceptedAt sql.NullTime
+}
+
+// MemoryFriendRepository is an in-memory FriendStore, mainly used in tests.
+// The names of friends are looked up in the user repository.
+type MemoryFriendRepository struct {
+	mu          sync.Mutex
+	friendships []memoryFriendship
+	users       *MemoryUserRepository
+}
+
+func NewMemoryFriendRepository(users *MemoryUserRepository) *MemoryFriendRepository {
+	return &MemoryFriendRepository{users: users}
+}
+
+// friend describes a friendship as seen by one of the two users
+func (r *MemoryFriendRepository) friend(userID int, f memoryFriendship) models.Friend {
+	otherID := f.toID
+	if f.toID == userID {
+		otherID = f.fromID
+	}
+
+	other, _ := r.users.find(otherID)
+
+	return models.Friend{
+		UserID:     otherID,
+		Name:       other.Name,
+		PublicKey:  other.PublicKey,
+		LastSeenAt: other.LastSeenAt,
+		Incoming:   f.fromID != userID,
+		CreatedAt:  f.createdAt,
+		AcceptedAt: f.acceptedAt,
+	}
+}
+
+// index returns the position of the friendship between two users, or -1.
+// The caller must hold the lock.
+func (r *MemoryFriendRepository) index(userID, otherID int) int {
+	return slices.IndexFunc(r.friendships, func(f memoryFriendship) bool {
+		return (f.fromID == userID && f.toID == otherID) || (f.fromID == otherID && f.toID == userID)
+	})
+}
+
+// Get retrieves the friendship or request between two users
+func (r *MemoryFriendRepository) Get(ctx context.Context, userID, otherID int) (*models.Friend, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := r.index(userID, otherID)
+	if i < 0 {
+		return nil, nil
+	}
+
+	friend := r.friend(userID, r.friendships[i])
+	return &friend, nil
+}
+
+// Request stores a friend request
+func (r *MemoryFriendRepository) Request(ctx context.Context, fromID, toID int, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if slices.ContainsFunc(r.friendships, func(f memoryFriendship) bool { return f.fromID == fromID && f.toID == toID }) {
+		return fmt.Errorf("create friend request: %d already asked %d", fromID, toID)
+	}
+
+	r.friendships = append(r.friendships, memoryFriendship{fromID: fromID, toID: toID, createdAt: at})
+
+	return nil
+}
+
+// Accept accepts a friend request
+func (r *MemoryFriendRepository) Accept(ctx context.Context, fromID, toID int, at time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, f := range r.friendships {
+		if f.fromID == fromID && f.toID == toID && !f.acceptedAt.Valid {
+			r.friendships[i].acceptedAt = sql.NullTime{Time: at, Valid: true}
+			return nil
+		}
+	}
+
+	return ErrNoFriendRequest
+}
+
+// Remove deletes the friendship or request between two users
+func (r *MemoryFriendRepository) Remove(ctx context.Context, userID, otherID int) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	before := len(r.friendships)
+	r.friendships = slices.DeleteFunc(r.friendships, func(f memoryFriendship) bool {
+		return (f.fromID == userID && f.toID == otherID) || (f.fromID == otherID && f.toID == userID)
+	})
+
+	return len(r.friendships) < before, nil
+}
+
+// List retrieves the friends and friend requests of a user
+func (r *MemoryFriendRepository) List(ctx context.Context, userID int) ([]models.Friend, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var friends []models.Friend
+	for _, f := range r.friendships {
+		if f.fromID == userID || f.toID == userID {
+			friends = append(friends, r.friend(userID, f))
+		}
+	}
+
+	sort.SliceStable(friends, func(i, j int) bool {
+		if friends[i].AcceptedAt.Valid != friends[j].AcceptedAt.Valid {
+			return friends[i].AcceptedAt.Valid
+		}
+		if a, b := strings.ToLower(friends[i].Name), strings.ToLower(friends[j].Name); a != b {
+			return a < b
+		}
+		return friends[i].UserID < friends[j].UserID
+	})
+
+	return friends, nil
+}
+
+// MemoryGiftRepository is an in-memory GiftStore, mainly used in tests. Gifts
+// are taken from and added to the inventory repository.
+type MemoryGiftRepository struct {
+	mu        sync.Mutex
+	gifts     []models.Gift
+	nextID    int
+	users     *MemoryUserRepository
+	inventory *MemoryInventoryRepository
+}
+
+func NewMemoryGiftRepository(users *MemoryUserRepository, inventory *MemoryInventoryRepository) *MemoryGiftRepository {
+	return &MemoryGiftRepository{
+		nextID:    1,
+		users:     users,
+		inventory: inventory,
+	}
+}
+
+// Send takes the item out of the sender's inventory and stores the gift
+func (r *MemoryGiftRepository) Send(ctx context.Context, gift *models.Gift) error {
+	if err := r.inventory.UseItem(ctx, gift.FromUserID, gift.ItemID); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if gift.CreatedAt.IsZero() {
+		gift.CreatedAt = time.Now()
+	}
+	gift.ID = r.nextID
+	r.nextID++
+	r.gifts = append(r.gifts, *gift)
+
+	return nil
+}
+
+// Deliver adds the undelivered gifts of a user to their inventory
+func (r *MemoryGiftRepository) Deliver(ctx context.Context, userID int, at time.Time) ([]models.Gift, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var delivered []models.Gift
+	for i, gift := range r.gifts {
+		if gift.ToUserID != userID || gift.DeliveredAt.Valid {
+			continue
+		}
+
+		if err := r.inventory.AddItem(ctx, userID, gift.ItemID, 1); err != nil {
+			return delivered, fmt.Errorf("add item: %w", err)
+		}
+
+		r.gifts[i].DeliveredAt = sql.NullTime{Time: at, Valid: true}
+
+		gift = r.gifts[i]
+		if sender, ok := r.users.find(gift.FromUserID); ok {
+			gift.FromName = sender.Name
+		}
+		delivered = append(delivered, gift)
+	}
+
+	return delivered, nil
+}
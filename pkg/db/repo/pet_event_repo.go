@@ -0,0 +1,63 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/kirkegaard/terminal-pet/pkg/db"
+	"github.com/kirkegaard/terminal-pet/pkg/db/models"
+)
+
+type PetEventRepository struct {
+	db *db.DB
+}
+
+func NewPetEventRepository(database *db.DB) *PetEventRepository {
+	return &PetEventRepository{
+		db: database,
+	}
+}
+
+// Add appends an entry to the activity log of a pet and sets its ID
+func (r *PetEventRepository) Add(ctx context.Context, event *models.PetEvent) error {
+	if r.db == nil {
+		return fmt.Errorf("no database connection available")
+	}
+
+	if event.CreatedAt.IsZero() {
+		event.CreatedAt = time.Now()
+	}
+	event.CreatedAt = event.CreatedAt.UTC()
+
+	err := r.db.QueryRowContext(ctx,
+		r.db.Rebind("INSERT INTO pet_events (pet_id, actor, kind, detail, created_at) VALUES (?, ?, ?, ?, ?) RETURNING id"),
+		event.PetID, event.Actor, event.Kind, event.Detail, event.CreatedAt,
+	).Scan(&event.ID)
+	if err != nil {
+		return fmt.Errorf("create pet event: %w", err)
+	}
+
+	return nil
+}
+
+// ListByPetID retrieves the latest entries in the activity log of a pet
+func (r *PetEventRepository) ListByPetID(ctx context.Context, petID int, limit int) ([]models.PetEvent, error) {
+	if r.db == nil {
+		return nil, fmt.Errorf("no database connection available")
+	}
+
+	query := `
+		SELECT id, pet_id, actor, kind, detail, created_at
+		FROM pet_events
+		WHERE pet_id = ?
+		ORDER BY created_at DESC, id DESC
+		LIMIT ?`
+
+	var events []models.PetEvent
+	if err := r.db.SelectContext(ctx, &events, r.db.Rebind(query), petID, limit); err != nil {
+		return nil, fmt.Errorf("list pet events: %w", err)
+	}
+
+	return events, nil
+}
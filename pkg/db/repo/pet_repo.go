@@ -3,7 +3,9 @@ package repo
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/charmbracelet/log"
@@ -12,16 +14,19 @@ import (
 	"github.com/kirkegaard/terminal-pet/pkg/pet"
 )
 
+// petColumns are the columns selected when loading a pet, along with the
+// name of its owner
+const petColumns = `id, name, species, birthday, parent_id, hunger, happiness, discipline, health, weight, is_sick, has_pooped, lights_on,
+		last_action, died_at, cause_of_death, epitaph, misbehavior, misbehaved_at, missed_calls, character_id,
+		care_steps, hunger_total, color, traits, appetite, spirit, vigor, bred_at, lifespan_start, revision, simulated_at, updated_at,
+		COALESCE((SELECT users.name FROM users WHERE users.id = pets.parent_id), '') AS parent_name`
+
 type PetRepository struct {
 	db       *db.DB
-	userRepo *UserRepository
+	userRepo UserStore
 }
 
 func NewPetRepository(database *db.DB) *PetRepository {
-	if database == nil {
-		database = db.GetInstance()
-	}
-
 	return &PetRepository{
 		db:       database,
 		userRepo: NewUserRepository(database),
@@ -34,12 +39,17 @@ func (r *PetRepository) Create(ctx context.Context, p *pet.Pet) error {
 		return fmt.Errorf("no database connection available")
 	}
 
-	result, err := r.db.Exec(`
+	var id int
+	err := r.db.QueryRowContext(ctx, r.db.Rebind(`
 		INSERT INTO pets (
-			name, birthday, parent_id, hunger, happiness, discipline, health, weight, is_sick, has_pooped, lights_on
-		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
-	`,
+			name, species, birthday, parent_id, hunger, happiness, discipline, health, weight, is_sick, has_pooped, lights_on, last_action,
+			died_at, cause_of_death, misbehavior, misbehaved_at, missed_calls, character_id, care_steps, hunger_total,
+			color, traits, appetite, spirit, vigor, bred_at, simulated_at, updated_at
+		) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		RETURNING id
+	`),
 		p.Name,
+		p.SpeciesID,
 		p.BirthDate,
 		p.Parent.ID,
 		p.Hunger,
@@ -50,19 +60,52 @@ func (r *PetRepository) Create(ctx context.Context, p *pet.Pet) error {
 		p.IsSick,
 		p.HasPooped,
 		p.LightsOn,
-	)
+		p.LastAction,
+		nullTime(p.DiedAt),
+		p.CauseOfDeath,
+		p.Misbehavior,
+		nullTime(p.MisbehavedAt),
+		p.MissedCalls,
+		p.CharacterID,
+		p.CareSteps,
+		p.HungerTotal,
+		p.Genes.Color,
+		strings.Join(p.Genes.Traits, ","),
+		p.Genes.Appetite,
+		p.Genes.Spirit,
+		p.Genes.Vigor,
+		nullTime(p.BredAt),
+		nullTime(p.SimulatedAt),
+		time.Now().UTC(),
+	).Scan(&id)
 	if err != nil {
 		return fmt.Errorf("create pet: %w", err)
 	}
 
-	// Get the last inserted ID and set it on the pet
-	id, err := result.LastInsertId()
+	p.ID = id
+	return nil
+}
+
+// GetByID retrieves a pet by its ID
+func (r *PetRepository) GetByID(ctx context.Context, id int) (*pet.Pet, error) {
+	if r.db == nil {
+		return nil, fmt.Errorf("no database connection available")
+	}
+
+	var model models.Pet
+
+	err := r.db.GetContext(ctx, &model, r.db.Rebind(`
+		SELECT `+petColumns+`
+		FROM pets WHERE id = ?
+	`), id)
 	if err != nil {
-		return fmt.Errorf("get last insert id: %w", err)
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("get pet by id: %w", err)
 	}
 
-	p.ID = int(id)
-	return nil
+	return modelToPet(&model), nil
 }
 
 // GetByParentID retrieves a pet by its parent ID
@@ -73,25 +116,10 @@ func (r *PetRepository) GetByParentID(ctx context.Context, parentID int) (*pet.P
 
 	var model models.Pet
 
-	err := r.db.QueryRow(`
-		SELECT id, name, birthday, parent_id, hunger, happiness, discipline, health, weight, is_sick, has_pooped, lights_on, updated_at
+	err := r.db.GetContext(ctx, &model, r.db.Rebind(`
+		SELECT `+petColumns+`
 		FROM pets WHERE parent_id = ? ORDER BY created_at DESC LIMIT 1
-	`, parentID).Scan(
-		&model.ID,
-		&model.Name,
-		&model.BirthDate,
-		&model.ParentID,
-		&model.Hunger,
-		&model.Happiness,
-		&model.Discipline,
-		&model.Health,
-		&model.Weight,
-		&model.IsSick,
-		&model.HasPooped,
-		&model.LightsOn,
-		&model.UpdatedAt,
-	)
-
+	`), parentID)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
@@ -99,9 +127,104 @@ func (r *PetRepository) GetByParentID(ctx context.Context, parentID int) (*pet.P
 		return nil, fmt.Errorf("get pet by parent id: %w", err)
 	}
 
-	parent := pet.NewParent(model.ParentID, "Player")
+	return modelToPet(&model), nil
+}
+
+// ListAlive retrieves every pet that is still alive
+func (r *PetRepository) ListAlive(ctx context.Context) ([]*pet.Pet, error) {
+	if r.db == nil {
+		return nil, fmt.Errorf("no database connection available")
+	}
+
+	pets, err := r.selectPets(ctx, `
+		SELECT `+petColumns+`
+		FROM pets WHERE health > 0
+	`)
+	if err != nil {
+		return nil, fmt.Errorf("list alive pets: %w", err)
+	}
+
+	return pets, nil
+}
+
+// ListAliveByParentID retrieves the living pets of a parent, oldest first
+func (r *PetRepository) ListAliveByParentID(ctx context.Context, parentID int) ([]*pet.Pet, error) {
+	if r.db == nil {
+		return nil, fmt.Errorf("no database connection available")
+	}
+
+	pets, err := r.selectPets(ctx, `
+		SELECT `+petColumns+`
+		FROM pets WHERE parent_id = ? AND health > 0 ORDER BY created_at, id
+	`, parentID)
+	if err != nil {
+		return nil, fmt.Errorf("list pets by parent id: %w", err)
+	}
+
+	return pets, nil
+}
+
+// ListDeadByParentID retrieves the deceased pets of a parent, most recent first
+func (r *PetRepository) ListDeadByParentID(ctx context.Context, parentID int) ([]*pet.Pet, error) {
+	if r.db == nil {
+		return nil, fmt.Errorf("no database connection available")
+	}
+
+	pets, err := r.selectPets(ctx, `
+		SELECT `+petColumns+`
+		FROM pets WHERE parent_id = ? AND health <= 0 ORDER BY COALESCE(died_at, updated_at) DESC, id DESC
+	`, parentID)
+	if err != nil {
+		return nil, fmt.Errorf("list dead pets by parent id: %w", err)
+	}
+
+	return pets, nil
+}
+
+// SetEpitaph stores the epitaph of a deceased pet
+func (r *PetRepository) SetEpitaph(ctx context.Context, id int, epitaph string) error {
+	if r.db == nil {
+		return fmt.Errorf("no database connection available")
+	}
+
+	_, err := r.db.ExecContext(ctx, r.db.Rebind("UPDATE pets SET epitaph = ? WHERE id = ? AND health <= 0"), epitaph, id)
+	if err != nil {
+		return fmt.Errorf("set epitaph: %w", err)
+	}
+
+	return nil
+}
+
+// selectPets runs a query returning pet rows and converts them into pets
+func (r *PetRepository) selectPets(ctx context.Context, query string, args ...interface{}) ([]*pet.Pet, error) {
+	var rows []models.Pet
+
+	if err := r.db.SelectContext(ctx, &rows, r.db.Rebind(query), args...); err != nil {
+		return nil, err
+	}
+
+	pets := make([]*pet.Pet, 0, len(rows))
+	for i := range rows {
+		pets = append(pets, modelToPet(&rows[i]))
+	}
+
+	return pets, nil
+}
 
-	petModel := pet.NewPet(model.Name, model.BirthDate, parent)
+// nullTime converts a zero time into a NULL column value
+func nullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
+}
+
+// modelToPet converts a database row into a pet
+func modelToPet(model *models.Pet) *pet.Pet {
+	parentName := model.ParentName
+	if parentName == "" {
+		parentName = "Player"
+	}
+	parent := pet.NewParent(model.ParentID, parentName)
+
+	petModel := pet.NewPet(model.Name, model.Species, model.BirthDate, parent)
 	petModel.ID = model.ID
 	petModel.Hunger = model.Hunger
 	petModel.Happiness = model.Happiness
@@ -111,32 +234,94 @@ func (r *PetRepository) GetByParentID(ctx context.Context, parentID int) (*pet.P
 	petModel.IsSick = model.IsSick
 	petModel.HasPooped = model.HasPooped
 	petModel.LightsOn = model.LightsOn
+	if model.LastAction.Valid {
+		petModel.LastAction = model.LastAction.Time
+	}
+	if model.DiedAt.Valid {
+		petModel.DiedAt = model.DiedAt.Time
+	}
+	petModel.CauseOfDeath = model.CauseOfDeath
+	petModel.Epitaph = model.Epitaph
+	petModel.Misbehavior = model.Misbehavior
+	if model.MisbehavedAt.Valid {
+		petModel.MisbehavedAt = model.MisbehavedAt.Time
+	}
+	petModel.MissedCalls = model.MissedCalls
+	petModel.CharacterID = model.CharacterID
+	petModel.CareSteps = model.CareSteps
+	petModel.HungerTotal = model.HungerTotal
+	petModel.Genes = pet.Genes{
+		Color:    model.Color,
+		Appetite: model.Appetite,
+		Spirit:   model.Spirit,
+		Vigor:    model.Vigor,
+	}
+	if model.Traits != "" {
+		petModel.Genes.Traits = strings.Split(model.Traits, ",")
+	}
+	if model.BredAt.Valid {
+		petModel.BredAt = model.BredAt.Time
+	}
+	if model.LifespanStart.Valid {
+		petModel.LifespanStart = model.LifespanStart.Time
+	}
+	petModel.Revision = model.Revision
 	petModel.LastVisit = model.UpdatedAt
+	petModel.SimulatedAt = model.UpdatedAt
+	if model.SimulatedAt.Valid {
+		petModel.SimulatedAt = model.SimulatedAt.Time
+	}
+	settleCharacter(petModel)
 
-	return petModel, nil
+	return petModel
+}
+
+// settleCharacter gives pets from before characters, or with a character
+// that no longer exists, their species' character for the life stage they
+// were last simulated in, so they don't evolve out of a fallback
+func settleCharacter(p *pet.Pet) {
+	if _, ok := pet.CharacterByID(p.CharacterID); !ok {
+		p.CharacterID = p.Character().ID
+	}
 }
 
-// Update updates an existing pet in the database
+// Update updates an existing pet in the database and moves it to the next
+// revision. The row is only written if it is still at the revision the pet
+// was loaded at, otherwise ErrPetConflict is returned.
 func (r *PetRepository) Update(ctx context.Context, p *pet.Pet) error {
 	if r.db == nil {
 		return fmt.Errorf("no database connection available")
 	}
 
-	_, err := r.db.Exec(`
-		UPDATE pets SET 
+	var revision int
+	err := r.db.QueryRowContext(ctx, r.db.Rebind(`
+		UPDATE pets SET
 			name = ?,
 			birthday = ?,
-			hunger = ?, 
-			happiness = ?, 
-			discipline = ?, 
-			health = ?, 
-			weight = ?, 
+			hunger = ?,
+			happiness = ?,
+			discipline = ?,
+			health = ?,
+			weight = ?,
 			is_sick = ?,
 			has_pooped = ?,
 			lights_on = ?,
+			last_action = ?,
+			died_at = ?,
+			cause_of_death = ?,
+			misbehavior = ?,
+			misbehaved_at = ?,
+			missed_calls = ?,
+			character_id = ?,
+			care_steps = ?,
+			hunger_total = ?,
+			bred_at = ?,
+			revision = revision + 1,
+			simulated_at = ?,
 			updated_at = ?
-		WHERE id = ? AND parent_id = ?
-	`,
+		WHERE id = ? AND parent_id = ? AND revision = ?
+		RETURNING revision
+	`),
 		p.Name,
 		p.BirthDate,
 		p.Hunger,
@@ -147,24 +332,110 @@ func (r *PetRepository) Update(ctx context.Context, p *pet.Pet) error {
 		p.IsSick,
 		p.HasPooped,
 		p.LightsOn,
-		time.Now(),
+		p.LastAction,
+		nullTime(p.DiedAt),
+		p.CauseOfDeath,
+		p.Misbehavior,
+		nullTime(p.MisbehavedAt),
+		p.MissedCalls,
+		p.CharacterID,
+		p.CareSteps,
+		p.HungerTotal,
+		nullTime(p.BredAt),
+		nullTime(p.SimulatedAt),
+		time.Now().UTC(),
 		p.ID,
 		p.Parent.ID,
-	)
+		p.Revision,
+	).Scan(&revision)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrPetConflict
+	}
 	if err != nil {
 		return fmt.Errorf("update pet: %w", err)
 	}
 
+	p.Revision = revision
+
 	return nil
 }
 
+// UpdateSimulated stores the stats of a pet that was advanced by the world
+// simulation. The row is only written if nobody else saved the pet since it
+// was loaded, which is told by its revision. It reports whether the row was
+// written.
+func (r *PetRepository) UpdateSimulated(ctx context.Context, p *pet.Pet) (bool, error) {
+	if r.db == nil {
+		return false, fmt.Errorf("no database connection available")
+	}
+
+	result, err := r.db.ExecContext(ctx, r.db.Rebind(`
+		UPDATE pets SET
+			hunger = ?,
+			happiness = ?,
+			health = ?,
+			weight = ?,
+			is_sick = ?,
+			has_pooped = ?,
+			discipline = ?,
+			died_at = ?,
+			cause_of_death = ?,
+			misbehavior = ?,
+			misbehaved_at = ?,
+			missed_calls = ?,
+			character_id = ?,
+			care_steps = ?,
+			hunger_total = ?,
+			revision = revision + 1,
+			simulated_at = ?,
+			updated_at = ?
+		WHERE id = ? AND revision = ?
+	`),
+		p.Hunger,
+		p.Happiness,
+		p.Health,
+		p.Weight,
+		p.IsSick,
+		p.HasPooped,
+		p.Discipline,
+		nullTime(p.DiedAt),
+		p.CauseOfDeath,
+		p.Misbehavior,
+		nullTime(p.MisbehavedAt),
+		p.MissedCalls,
+		p.CharacterID,
+		p.CareSteps,
+		p.HungerTotal,
+		nullTime(p.SimulatedAt.UTC()),
+		time.Now().UTC(),
+		p.ID,
+		p.Revision,
+	)
+	if err != nil {
+		return false, fmt.Errorf("update simulated pet: %w", err)
+	}
+
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("get rows affected: %w", err)
+	}
+
+	if affected == 0 {
+		return false, nil
+	}
+
+	p.Revision++
+
+	return true, nil
+}
+
 // Delete removes a pet from the database
 func (r *PetRepository) Delete(ctx context.Context, id int) error {
 	if r.db == nil {
 		return fmt.Errorf("no database connection available")
 	}
 
-	_, err := r.db.Exec("DELETE FROM pets WHERE id = ?", id)
+	_, err := r.db.ExecContext(ctx, r.db.Rebind("DELETE FROM pets WHERE id = ?"), id)
 	if err != nil {
 		return fmt.Errorf("delete pet: %w", err)
 	}
@@ -207,30 +478,30 @@ func (r *PetRepository) Save(ctx context.Context, p *pet.Pet, publicKey string)
 		return fmt.Errorf("no database connection available")
 	}
 
+	return savePet(ctx, r, r.userRepo, p, publicKey)
+}
+
+// savePet creates or updates a pet in the given store, creating the user
+// that owns the public key if it does not exist yet
+func savePet(ctx context.Context, pets PetStore, users UserStore, p *pet.Pet, publicKey string) error {
 	log.Debug("Saving pet", "id", p.ID, "name", p.Name)
 
-	userID, err := r.userRepo.GetByPublicKey(ctx, publicKey)
+	userID, err := users.GetByPublicKey(ctx, publicKey)
 	if err != nil {
 		return err
 	}
 
 	if userID == 0 {
-		userID, err = r.userRepo.Create(ctx, p.Parent.Name, publicKey)
+		userID, err = users.Create(ctx, p.Parent.Name, publicKey)
 		if err != nil {
 			return err
 		}
-		p.Parent.ID = userID
 	}
+	p.Parent.ID = userID
 
-	// Check if the pet already exists
-	existingPet, err := r.GetByParentID(ctx, userID)
-	if err != nil {
-		return err
+	if p.ID == 0 {
+		return pets.Create(ctx, p)
 	}
 
-	if existingPet == nil {
-		return r.Create(ctx, p)
-	} else {
-		return r.Update(ctx, p)
-	}
+	return pets.Update(ctx, p)
 }
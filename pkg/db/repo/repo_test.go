@@ -0,0 +1,851 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kirkegaard/terminal-pet/pkg/db"
+	"github.com/kirkegaard/terminal-pet/pkg/db/models"
+	"github.com/kirkegaard/terminal-pet/pkg/pet"
+)
+
+// postgresDSNEnv names the environment variable holding the data source of
+// a PostgreSQL database to run the tests against. The tests create and drop
+// a schema of their own in it.
+const postgresDSNEnv = "TEST_POSTGRES_DSN"
+
+// stores are the stores a test case runs against, all backed by the same
+// database
+type stores struct {
+	pets         PetStore
+	users        UserStore
+	inventory    InventoryStore
+	scores       ScoreStore
+	achievements AchievementStore
+	history      PetEventStore
+	stats        StatStore
+	lineage      LineageStore
+	friends      FriendStore
+	gifts        GiftStore
+}
+
+// backends open a fresh, empty set of stores for each test case
+var backends = []struct {
+	name string
+	open func(t *testing.T) stores
+}{
+	{"sqlite3", openSQLite},
+	{"postgres", openPostgres},
+	{"memory", openMemory},
+}
+
+// forEachBackend runs the test against every backend
+func forEachBackend(t *testing.T, test func(t *testing.T, s stores)) {
+	for _, backend := range backends {
+		t.Run(backend.name, func(t *testing.T) {
+			test(t, backend.open(t))
+		})
+	}
+}
+
+func openSQLite(t *testing.T) stores {
+	return openSQL(t, db.DriverSQLite, filepath.Join(t.TempDir(), "pet.db"))
+}
+
+func openPostgres(t *testing.T) stores {
+	dsn := os.Getenv(postgresDSNEnv)
+	if dsn == "" {
+		t.Skipf("%s is not set", postgresDSNEnv)
+	}
+
+	ctx := context.Background()
+
+	admin, err := db.Open(ctx, db.DriverPostgres, dsn)
+	if err != nil {
+		t.Fatalf("open postgres: %v", err)
+	}
+	t.Cleanup(admin.Close)
+
+	schema := fmt.Sprintf("test_%d", time.Now().UnixNano())
+	if _, err := admin.ExecContext(ctx, "CREATE SCHEMA "+schema); err != nil {
+		t.Fatalf("create schema: %v", err)
+	}
+	t.Cleanup(func() {
+		if _, err := admin.ExecContext(context.Background(), "DROP SCHEMA "+schema+" CASCADE"); err != nil {
+			t.Errorf("drop schema: %v", err)
+		}
+	})
+
+	return openSQL(t, db.DriverPostgres, withSearchPath(dsn, schema))
+}
+
+// withSearchPath adds the schema to look tables up in to a PostgreSQL data
+// source, in either URL or key=value form
+func withSearchPath(dsn string, schema string) string {
+	if !strings.Contains(dsn, "://") {
+		return dsn + " search_path=" + schema
+	}
+
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return dsn
+	}
+
+	q := u.Query()
+	q.Set("search_path", schema)
+	u.RawQuery = q.Encode()
+
+	return u.String()
+}
+
+func openSQL(t *testing.T, driver string, dsn string) stores {
+	ctx := context.Background()
+
+	database, err := db.Open(ctx, driver, dsn)
+	if err != nil {
+		t.Fatalf("open %s: %v", driver, err)
+	}
+	t.Cleanup(database.Close)
+
+	if _, err := database.Migrate(ctx); err != nil {
+		t.Fatalf("migrate %s: %v", driver, err)
+	}
+
+	return stores{
+		pets:         NewPetRepository(database),
+		users:        NewUserRepository(database),
+		inventory:    NewInventoryRepository(database),
+		scores:       NewScoreRepository(database),
+		achievements: NewAchievementRepository(database),
+		history:      NewPetEventRepository(database),
+		stats:        NewStatRepository(database),
+		lineage:      NewLineageRepository(database),
+		friends:      NewFriendRepository(database),
+		gifts:        NewGiftRepository(database),
+	}
+}
+
+func openMemory(t *testing.T) stores {
+	users := NewMemoryUserRepository()
+	inventory := NewMemoryInventoryRepository()
+
+	return stores{
+		pets:         NewMemoryPetRepository(users),
+		users:        users,
+		inventory:    inventory,
+		scores:       NewMemoryScoreRepository(),
+		achievements: NewMemoryAchievementRepository(),
+		history:      NewMemoryPetEventRepository(),
+		stats:        NewMemoryStatRepository(),
+		lineage:      NewMemoryLineageRepository(),
+		friends:      NewMemoryFriendRepository(users),
+		gifts:        NewMemoryGiftRepository(users, inventory),
+	}
+}
+
+// now is the time the test pets are born, cut to what every backend
+// stores
+var now = time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+
+// createUser stores a user or fails the test
+func createUser(t *testing.T, s stores, name string) int {
+	id, err := s.users.Create(context.Background(), name, "key-"+name)
+	if err != nil {
+		t.Fatalf("create user %s: %v", name, err)
+	}
+
+	return id
+}
+
+// createPet stores a new pet of the user or fails the test
+func createPet(t *testing.T, s stores, userID int, name string) *pet.Pet {
+	p := pet.NewPet(name, pet.DefaultSpecies, now, pet.NewParent(userID, "owner"))
+	if err := s.pets.Create(context.Background(), p); err != nil {
+		t.Fatalf("create pet %s: %v", name, err)
+	}
+
+	return p
+}
+
+func TestCreate(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, s stores) {
+		ctx := context.Background()
+
+		alice := createUser(t, s, "alice")
+		bob := createUser(t, s, "bob")
+		if alice == 0 || bob == 0 || alice == bob {
+			t.Fatalf("user IDs %d and %d, want two different IDs", alice, bob)
+		}
+
+		p := createPet(t, s, alice, "Rex")
+		other := createPet(t, s, bob, "Tom")
+		if p.ID == 0 || p.ID == other.ID {
+			t.Fatalf("pet IDs %d and %d, want two different IDs", p.ID, other.ID)
+		}
+
+		got, err := s.pets.GetByParentID(ctx, alice)
+		if err != nil || got == nil {
+			t.Fatalf("get pet: %v, %v", got, err)
+		}
+		if got.ID != p.ID || got.Name != "Rex" || got.Parent.ID != alice {
+			t.Errorf("got pet %d %s of user %d, want pet %d Rex of user %d", got.ID, got.Name, got.Parent.ID, p.ID, alice)
+		}
+		if !got.BirthDate.Equal(now) {
+			t.Errorf("born %v, want %v", got.BirthDate, now)
+		}
+		if got.Genes.Color != p.Genes.Color || strings.Join(got.Genes.Traits, ",") != strings.Join(p.Genes.Traits, ",") {
+			t.Errorf("genes %+v, want %+v", got.Genes, p.Genes)
+		}
+		if got.Revision != 0 {
+			t.Errorf("revision %d, want 0", got.Revision)
+		}
+
+		// Saving a pet for a new public key creates its owner
+		adopted := pet.NewPet("Kit", pet.SpeciesDragon, now, pet.NewParent(0, "carol"))
+		if err := s.pets.Save(ctx, adopted, "key-carol"); err != nil {
+			t.Fatalf("save pet: %v", err)
+		}
+		carol, err := s.users.GetByPublicKey(ctx, "key-carol")
+		if err != nil || carol == 0 || adopted.Parent.ID != carol || adopted.ID == 0 {
+			t.Errorf("saved pet %d of user %d, found user %d: %v", adopted.ID, adopted.Parent.ID, carol, err)
+		}
+		if kit, err := s.pets.GetByParentID(ctx, carol); err != nil || kit == nil || kit.SpeciesID != pet.SpeciesDragon {
+			t.Errorf("got pet %+v: %v, want a %s", kit, err, pet.SpeciesDragon)
+		}
+
+		// A player's living pets are listed oldest first
+		second := createPet(t, s, alice, "Max")
+		living, err := s.pets.ListAliveByParentID(ctx, alice)
+		if err != nil || len(living) != 2 || living[0].ID != p.ID || living[1].ID != second.ID {
+			t.Errorf("living pets %+v: %v, want pets %d and %d", living, err, p.ID, second.ID)
+		}
+
+		user, err := s.users.FindByPublicKey(ctx, "key-carol")
+		if err != nil || user == nil || user.Name != "carol" {
+			t.Errorf("found user %+v: %v, want carol", user, err)
+		}
+	})
+}
+
+func TestUpdate(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, s stores) {
+		ctx := context.Background()
+
+		alice := createUser(t, s, "alice")
+		p := createPet(t, s, alice, "Rex")
+
+		p.Hunger, p.IsSick, p.LightsOn = 42, true, false
+		kitten := p.Species().Characters[pet.FormChild].ID
+		p.CharacterID = kitten
+		if err := s.pets.Save(ctx, p, "key-alice"); err != nil {
+			t.Fatalf("save pet: %v", err)
+		}
+
+		got, err := s.pets.GetByParentID(ctx, alice)
+		if err != nil || got == nil {
+			t.Fatalf("get pet: %v, %v", got, err)
+		}
+		if got.Hunger != 42 || !got.IsSick || got.LightsOn {
+			t.Errorf("hunger %d, sick %v, lights %v, want 42, true, false", got.Hunger, got.IsSick, got.LightsOn)
+		}
+		if got.CharacterID != kitten {
+			t.Errorf("character %s, want %s", got.CharacterID, kitten)
+		}
+
+		alive, err := s.pets.ListAlive(ctx)
+		if err != nil || len(alive) != 1 {
+			t.Errorf("%d living pets: %v, want 1", len(alive), err)
+		}
+
+		// Pets with a character that doesn't exist get their species'
+		// character for their stage
+		p.CharacterID = "gone"
+		if err := s.pets.Update(ctx, p); err != nil {
+			t.Fatalf("update pet: %v", err)
+		}
+		got, err = s.pets.GetByID(ctx, p.ID)
+		if err != nil || got == nil {
+			t.Fatalf("get pet: %v, %v", got, err)
+		}
+		if want := p.Species().Characters[pet.FormBaby].ID; got.CharacterID != want {
+			t.Errorf("character %s, want %s", got.CharacterID, want)
+		}
+
+		// Saving doesn't move the simulation time
+		simulatedAt := time.Now().UTC().Add(-time.Hour).Truncate(time.Second)
+		p.SimulatedAt = simulatedAt
+		if err := s.pets.Update(ctx, p); err != nil {
+			t.Fatalf("update pet: %v", err)
+		}
+
+		got, err = s.pets.GetByID(ctx, p.ID)
+		if err != nil || got == nil {
+			t.Fatalf("get pet: %v, %v", got, err)
+		}
+		if !got.SimulatedAt.Equal(simulatedAt) {
+			t.Errorf("simulated at %v, want %v", got.SimulatedAt, simulatedAt)
+		}
+
+		// A copy loaded before the last save can't be saved over it
+		stale := *got
+		got.Hunger = 10
+		if err := s.pets.Update(ctx, got); err != nil {
+			t.Fatalf("update pet: %v", err)
+		}
+		stale.Hunger = 90
+		if err := s.pets.Update(ctx, &stale); !errors.Is(err, ErrPetConflict) {
+			t.Errorf("updating a stale copy: %v, want %v", err, ErrPetConflict)
+		}
+
+		p = got
+		p.Health = 0
+		if err := s.pets.Update(ctx, p); err != nil {
+			t.Fatalf("update pet: %v", err)
+		}
+
+		alive, err = s.pets.ListAlive(ctx)
+		if err != nil || len(alive) != 0 {
+			t.Errorf("%d living pets: %v, want none", len(alive), err)
+		}
+	})
+}
+
+func TestUpdateSimulated(t *testing.T) {
+	tests := []struct {
+		name string
+		// savedMeanwhile saves the pet through another copy after it was
+		// loaded for the simulation
+		savedMeanwhile bool
+		want           bool
+	}{
+		{"nobody else saved the pet", false, true},
+		{"the pet was saved meanwhile", true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			forEachBackend(t, func(t *testing.T, s stores) {
+				ctx := context.Background()
+
+				p := createPet(t, s, createUser(t, s, "alice"), "Rex")
+
+				simulated, err := s.pets.GetByID(ctx, p.ID)
+				if err != nil {
+					t.Fatalf("get pet: %v", err)
+				}
+
+				if tt.savedMeanwhile {
+					p.Happiness = 77
+					if err := s.pets.Update(ctx, p); err != nil {
+						t.Fatalf("update pet: %v", err)
+					}
+					if p.Revision != 1 {
+						t.Errorf("revision after update %d, want 1", p.Revision)
+					}
+				}
+
+				simulated.Hunger = 42
+				simulated.SimulatedAt = now.Add(time.Hour)
+				ok, err := s.pets.UpdateSimulated(ctx, simulated)
+				if err != nil {
+					t.Fatalf("update simulated pet: %v", err)
+				}
+				if ok != tt.want {
+					t.Errorf("updated %v, want %v", ok, tt.want)
+				}
+
+				got, err := s.pets.GetByID(ctx, p.ID)
+				if err != nil {
+					t.Fatalf("get pet: %v", err)
+				}
+
+				if tt.want {
+					if got.Hunger != 42 || got.Revision != 1 {
+						t.Errorf("hunger %d at revision %d, want 42 at revision 1", got.Hunger, got.Revision)
+					}
+					return
+				}
+
+				if got.Hunger == 42 || got.Happiness != 77 {
+					t.Errorf("hunger %d and happiness %d, the simulation saved over the update", got.Hunger, got.Happiness)
+				}
+			})
+		})
+	}
+}
+
+func TestDeath(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, s stores) {
+		ctx := context.Background()
+
+		alice := createUser(t, s, "alice")
+		p := createPet(t, s, alice, "Rex")
+		createPet(t, s, alice, "Max")
+
+		p.Health, p.Hunger = 0, 100
+		died := now.Add(time.Hour)
+		if !p.MarkDead(died) {
+			t.Fatalf("pet didn't die")
+		}
+		if err := s.pets.Update(ctx, p); err != nil {
+			t.Fatalf("update pet: %v", err)
+		}
+		if err := s.pets.SetEpitaph(ctx, p.ID, "Always hungry"); err != nil {
+			t.Fatalf("set epitaph: %v", err)
+		}
+
+		dead, err := s.pets.ListDeadByParentID(ctx, alice)
+		if err != nil || len(dead) != 1 {
+			t.Fatalf("dead pets %+v: %v, want Rex", dead, err)
+		}
+
+		got := dead[0]
+		if got.ID != p.ID || got.CauseOfDeath != pet.CauseStarvation || got.Epitaph != "Always hungry" || !got.DiedAt.Equal(died) {
+			t.Errorf("dead pet %d died of %s at %v with epitaph %q, want %d died of %s at %v", got.ID, got.CauseOfDeath, got.DiedAt, got.Epitaph, p.ID, pet.CauseStarvation, died)
+		}
+	})
+}
+
+func TestFindByName(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, s stores) {
+		ctx := context.Background()
+
+		alice := createUser(t, s, "alice")
+		if _, err := s.users.Create(ctx, "Bob", "key-bob-1"); err != nil {
+			t.Fatalf("create user: %v", err)
+		}
+		if _, err := s.users.Create(ctx, "bob", "key-bob-2"); err != nil {
+			t.Fatalf("create user: %v", err)
+		}
+		if err := s.users.SetPrivate(ctx, alice, true); err != nil {
+			t.Fatalf("set private: %v", err)
+		}
+
+		tests := []struct {
+			name        string
+			wantID      int
+			wantPrivate bool
+			wantErr     error
+		}{
+			{"ALICE", alice, true, nil},
+			{"nobody", 0, false, nil},
+			{"bob", 0, false, ErrAmbiguousName},
+		}
+
+		for _, tt := range tests {
+			user, err := s.users.FindByName(ctx, tt.name)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("find %s: error %v, want %v", tt.name, err, tt.wantErr)
+				continue
+			}
+
+			id, private := 0, false
+			if user != nil {
+				id, private = user.ID, user.Private
+			}
+			if id != tt.wantID || private != tt.wantPrivate {
+				t.Errorf("find %s: user %d private %v, want %d private %v", tt.name, id, private, tt.wantID, tt.wantPrivate)
+			}
+		}
+	})
+}
+
+func TestInventory(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, s stores) {
+		ctx := context.Background()
+
+		alice := createUser(t, s, "alice")
+
+		coins, err := s.inventory.Coins(ctx, alice)
+		if err != nil || coins != StartingCoins {
+			t.Fatalf("coins %d: %v, want %d", coins, err, StartingCoins)
+		}
+
+		steps := []struct {
+			name      string
+			do        func() error
+			wantErr   error
+			wantCoins int
+			wantItems map[string]int
+		}{
+			{"using an item that isn't owned", func() error { return s.inventory.UseItem(ctx, alice, "burger") }, ErrOutOfStock, StartingCoins, map[string]int{}},
+			{"buying an item", func() error { _, err := s.inventory.Buy(ctx, alice, "burger", 30); return err }, nil, StartingCoins - 30, map[string]int{"burger": 1}},
+			{"buying another", func() error { _, err := s.inventory.Buy(ctx, alice, "burger", 30); return err }, nil, StartingCoins - 60, map[string]int{"burger": 2}},
+			{"buying what can't be afforded", func() error { _, err := s.inventory.Buy(ctx, alice, "cake", 50); return err }, ErrNotEnoughCoins, StartingCoins - 60, map[string]int{"burger": 2}},
+			{"using an item", func() error { return s.inventory.UseItem(ctx, alice, "burger") }, nil, StartingCoins - 60, map[string]int{"burger": 1}},
+			{"using the last one", func() error { return s.inventory.UseItem(ctx, alice, "burger") }, nil, StartingCoins - 60, map[string]int{}},
+			{"using one too many", func() error { return s.inventory.UseItem(ctx, alice, "burger") }, ErrOutOfStock, StartingCoins - 60, map[string]int{}},
+			{"adding items", func() error { return s.inventory.AddItem(ctx, alice, "medicine", 3) }, nil, StartingCoins - 60, map[string]int{"medicine": 3}},
+			{"earning coins", func() error { _, err := s.inventory.AddCoins(ctx, alice, 25); return err }, nil, StartingCoins - 35, map[string]int{"medicine": 3}},
+		}
+
+		for _, step := range steps {
+			if err := step.do(); !errors.Is(err, step.wantErr) {
+				t.Fatalf("%s: error %v, want %v", step.name, err, step.wantErr)
+			}
+
+			coins, err := s.inventory.Coins(ctx, alice)
+			if err != nil || coins != step.wantCoins {
+				t.Errorf("%s: coins %d: %v, want %d", step.name, coins, err, step.wantCoins)
+			}
+
+			items, err := s.inventory.Items(ctx, alice)
+			if err != nil || fmt.Sprint(items) != fmt.Sprint(step.wantItems) {
+				t.Errorf("%s: items %v: %v, want %v", step.name, items, err, step.wantItems)
+			}
+		}
+	})
+}
+
+func TestClaimDailyReward(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, s stores) {
+		ctx := context.Background()
+
+		alice := createUser(t, s, "alice")
+		morning := time.Date(2025, 3, 1, 0, 30, 0, 0, time.UTC)
+
+		claims := []struct {
+			at   time.Time
+			want bool
+		}{
+			{morning, true},
+			{morning.Add(23 * time.Hour), false},
+			{morning.Add(24 * time.Hour), true},
+			// The day is the UTC day, wherever the player is
+			{morning.Add(25 * time.Hour).In(time.FixedZone("UTC-3", -3*60*60)), false},
+		}
+
+		for i, claim := range claims {
+			claimed, err := s.inventory.ClaimDailyReward(ctx, alice, 10, claim.at)
+			if err != nil {
+				t.Fatalf("claim %d: %v", i, err)
+			}
+			if claimed != claim.want {
+				t.Errorf("claim %d at %v: claimed %v, want %v", i, claim.at, claimed, claim.want)
+			}
+		}
+
+		coins, err := s.inventory.Coins(ctx, alice)
+		if err != nil || coins != StartingCoins+20 {
+			t.Errorf("coins %d: %v, want %d", coins, err, StartingCoins+20)
+		}
+	})
+}
+
+func TestScores(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, s stores) {
+		ctx := context.Background()
+
+		alice := createUser(t, s, "alice")
+		bob := createUser(t, s, "bob")
+		carol := createUser(t, s, "carol")
+		rex := createPet(t, s, alice, "Rex")
+
+		added := []models.GameScore{
+			{UserID: alice, PetID: rex.ID, Game: "snake", Score: 12},
+			{UserID: bob, Game: "snake", Score: 30},
+			{UserID: alice, PetID: rex.ID, Game: "snake", Score: 30},
+			{UserID: alice, PetID: rex.ID, Game: "snake", Score: 7},
+			{UserID: carol, Game: "snake", Score: 5},
+			{UserID: carol, Game: "memory", Score: 99},
+		}
+		for i := range added {
+			added[i].CreatedAt = now.Add(time.Duration(i) * time.Minute)
+			if err := s.scores.Add(ctx, &added[i]); err != nil || added[i].ID == 0 {
+				t.Fatalf("add score %d: %v", i, err)
+			}
+		}
+
+		best, err := s.scores.Best(ctx, alice, "snake")
+		if err != nil || best != 30 {
+			t.Errorf("best score %d: %v, want 30", best, err)
+		}
+		best, err = s.scores.Best(ctx, bob, "memory")
+		if err != nil || best != 0 {
+			t.Errorf("best score of a game never played %d: %v, want 0", best, err)
+		}
+
+		// Each player is listed once with their best score, ties go to
+		// whoever reached it first
+		top, err := s.scores.Top(ctx, "snake", 2)
+		if err != nil || len(top) != 2 {
+			t.Fatalf("top scores %+v: %v, want 2", top, err)
+		}
+		if top[0].ID != added[1].ID || top[1].ID != added[2].ID {
+			t.Errorf("top scores %d and %d, want %d and %d", top[0].ID, top[1].ID, added[1].ID, added[2].ID)
+		}
+	})
+}
+
+func TestAchievements(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, s stores) {
+		ctx := context.Background()
+
+		alice := createUser(t, s, "alice")
+		bob := createUser(t, s, "bob")
+
+		for want := 1; want <= 3; want++ {
+			got, err := s.achievements.AddProgress(ctx, alice, "feeder", 1)
+			if err != nil || got != want {
+				t.Fatalf("progress %d: %v, want %d", got, err, want)
+			}
+		}
+
+		unlocked, err := s.achievements.Unlock(ctx, alice, "feeder", now)
+		if err != nil || !unlocked {
+			t.Fatalf("unlocked %v: %v, want true", unlocked, err)
+		}
+		unlocked, err = s.achievements.Unlock(ctx, alice, "feeder", now.Add(time.Hour))
+		if err != nil || unlocked {
+			t.Errorf("unlocked again %v: %v, want false", unlocked, err)
+		}
+		if _, err := s.achievements.Unlock(ctx, alice, "first_steps", now); err != nil {
+			t.Fatalf("unlock without progress: %v", err)
+		}
+
+		list, err := s.achievements.List(ctx, alice)
+		if err != nil || len(list) != 2 {
+			t.Fatalf("achievements %+v: %v, want 2", list, err)
+		}
+		for _, a := range list {
+			if a.UserID != alice || !a.UnlockedAt.Valid || !a.UnlockedAt.Time.Equal(now) {
+				t.Errorf("achievement %+v, want unlocked by %d at %v", a, alice, now)
+			}
+			if a.AchievementID == "feeder" && a.Progress != 3 {
+				t.Errorf("progress %d, want 3", a.Progress)
+			}
+		}
+
+		list, err = s.achievements.List(ctx, bob)
+		if err != nil || len(list) != 0 {
+			t.Errorf("achievements of bob %+v: %v, want none", list, err)
+		}
+	})
+}
+
+func TestHistory(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, s stores) {
+		ctx := context.Background()
+
+		alice := createUser(t, s, "alice")
+		rex := createPet(t, s, alice, "Rex")
+		other := createPet(t, s, alice, "Max")
+
+		for i, kind := range []string{"fed", "played", "cleaned"} {
+			e := &models.PetEvent{PetID: rex.ID, Actor: "alice", Kind: kind, CreatedAt: now.Add(time.Duration(i) * time.Minute)}
+			if err := s.history.Add(ctx, e); err != nil || e.ID == 0 {
+				t.Fatalf("add %s: %v", kind, err)
+			}
+		}
+		if err := s.history.Add(ctx, &models.PetEvent{PetID: other.ID, Actor: "alice", Kind: "fed", CreatedAt: now}); err != nil {
+			t.Fatalf("add fed: %v", err)
+		}
+
+		entries, err := s.history.ListByPetID(ctx, rex.ID, 2)
+		if err != nil || len(entries) != 2 {
+			t.Fatalf("entries %+v: %v, want 2", entries, err)
+		}
+		if entries[0].Kind != "cleaned" || entries[1].Kind != "played" {
+			t.Errorf("entries %s and %s, want cleaned and played", entries[0].Kind, entries[1].Kind)
+		}
+		if !entries[0].CreatedAt.Equal(now.Add(2 * time.Minute)) {
+			t.Errorf("logged at %v, want %v", entries[0].CreatedAt, now.Add(2*time.Minute))
+		}
+	})
+}
+
+func TestFriends(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, s stores) {
+		ctx := context.Background()
+
+		alice := createUser(t, s, "alice")
+		bob := createUser(t, s, "bob")
+		carol := createUser(t, s, "carol")
+
+		if err := s.friends.Request(ctx, alice, bob, now); err != nil {
+			t.Fatalf("request: %v", err)
+		}
+		if err := s.friends.Request(ctx, carol, alice, now); err != nil {
+			t.Fatalf("request: %v", err)
+		}
+
+		// Only the user who was asked can accept
+		if err := s.friends.Accept(ctx, bob, alice, now); !errors.Is(err, ErrNoFriendRequest) {
+			t.Errorf("accepting own request: %v, want %v", err, ErrNoFriendRequest)
+		}
+		if err := s.friends.Accept(ctx, alice, bob, now); err != nil {
+			t.Fatalf("accept: %v", err)
+		}
+
+		friend, err := s.friends.Get(ctx, bob, alice)
+		if err != nil || friend == nil || !friend.AcceptedAt.Valid {
+			t.Errorf("friendship %+v: %v, want an accepted one", friend, err)
+		}
+
+		list, err := s.friends.List(ctx, alice)
+		if err != nil {
+			t.Fatalf("list: %v", err)
+		}
+
+		var got []string
+		for _, f := range list {
+			got = append(got, fmt.Sprintf("%s accepted=%v incoming=%v", f.Name, f.AcceptedAt.Valid, f.Incoming))
+		}
+		want := []string{"bob accepted=true incoming=false", "carol accepted=false incoming=true"}
+		if strings.Join(got, ", ") != strings.Join(want, ", ") {
+			t.Errorf("friends %v, want %v", got, want)
+		}
+
+		removed, err := s.friends.Remove(ctx, carol, alice)
+		if err != nil || !removed {
+			t.Errorf("remove: %v, %v", removed, err)
+		}
+		if removed, _ := s.friends.Remove(ctx, carol, alice); removed {
+			t.Errorf("removed a request twice")
+		}
+	})
+}
+
+func TestGifts(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, s stores) {
+		ctx := context.Background()
+
+		alice := createUser(t, s, "alice")
+		bob := createUser(t, s, "bob")
+
+		gift := &models.Gift{FromUserID: alice, ToUserID: bob, ItemID: "burger", CreatedAt: now}
+		if err := s.gifts.Send(ctx, gift); !errors.Is(err, ErrOutOfStock) {
+			t.Errorf("sending an item that isn't owned: %v, want %v", err, ErrOutOfStock)
+		}
+
+		if err := s.inventory.AddItem(ctx, alice, "burger", 1); err != nil {
+			t.Fatalf("add item: %v", err)
+		}
+		if err := s.gifts.Send(ctx, gift); err != nil {
+			t.Fatalf("send: %v", err)
+		}
+		if gift.ID == 0 {
+			t.Errorf("gift has no ID")
+		}
+
+		items, err := s.inventory.Items(ctx, alice)
+		if err != nil || items["burger"] != 0 {
+			t.Errorf("sender still has %d burgers: %v", items["burger"], err)
+		}
+
+		delivered, err := s.gifts.Deliver(ctx, bob, now.Add(time.Minute))
+		if err != nil {
+			t.Fatalf("deliver: %v", err)
+		}
+		if len(delivered) != 1 || delivered[0].FromName != "alice" || delivered[0].ItemID != "burger" {
+			t.Errorf("delivered %+v, want a burger from alice", delivered)
+		}
+
+		items, err = s.inventory.Items(ctx, bob)
+		if err != nil || items["burger"] != 1 {
+			t.Errorf("recipient has %d burgers: %v, want 1", items["burger"], err)
+		}
+
+		again, err := s.gifts.Deliver(ctx, bob, now.Add(2*time.Minute))
+		if err != nil || len(again) != 0 {
+			t.Errorf("delivered %+v again: %v", again, err)
+		}
+	})
+}
+
+func TestCompact(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, s stores) {
+		ctx := context.Background()
+
+		p := createPet(t, s, createUser(t, s, "alice"), "Rex")
+
+		snapshot := func(at time.Time, hunger int) *models.StatSnapshot {
+			return &models.StatSnapshot{PetID: p.ID, BucketSeconds: 60, At: at, Hunger: hunger, Happiness: 50, Health: 100, Weight: 10, Samples: 1}
+		}
+
+		hour := now.Truncate(time.Hour)
+		for i, hunger := range []int{10, 20} {
+			if err := s.stats.Add(ctx, snapshot(hour.Add(time.Duration(i)*time.Minute), hunger)); err != nil {
+				t.Fatalf("add: %v", err)
+			}
+		}
+
+		// A second snapshot for the same bucket is ignored
+		if err := s.stats.Add(ctx, snapshot(hour, 90)); err != nil {
+			t.Fatalf("add: %v", err)
+		}
+
+		compacted, err := s.stats.Compact(ctx, time.Hour, hour.Add(time.Hour))
+		if err != nil || compacted != 2 {
+			t.Fatalf("compacted %d: %v, want 2", compacted, err)
+		}
+
+		// A snapshot that arrives late is merged into the hour's average by
+		// its weight
+		if err := s.stats.Add(ctx, snapshot(hour.Add(30*time.Minute), 60)); err != nil {
+			t.Fatalf("add: %v", err)
+		}
+		if _, err := s.stats.Compact(ctx, time.Hour, hour.Add(time.Hour)); err != nil {
+			t.Fatalf("compact: %v", err)
+		}
+
+		// Newer snapshots are left alone
+		if err := s.stats.Add(ctx, snapshot(hour.Add(time.Hour), 70)); err != nil {
+			t.Fatalf("add: %v", err)
+		}
+
+		got, err := s.stats.ListByPetID(ctx, p.ID, hour)
+		if err != nil {
+			t.Fatalf("list: %v", err)
+		}
+
+		want := []string{"3600s hunger=30 samples=3", "60s hunger=70 samples=1"}
+		var gotDesc []string
+		for _, snap := range got {
+			gotDesc = append(gotDesc, fmt.Sprintf("%ds hunger=%d samples=%d", snap.BucketSeconds, snap.Hunger, snap.Samples))
+		}
+		if strings.Join(gotDesc, ", ") != strings.Join(want, ", ") {
+			t.Errorf("snapshots %v, want %v", gotDesc, want)
+		}
+	})
+}
+
+func TestLineage(t *testing.T) {
+	forEachBackend(t, func(t *testing.T, s stores) {
+		ctx := context.Background()
+
+		alice := createUser(t, s, "alice")
+		mother := createPet(t, s, alice, "Rex")
+		father := createPet(t, s, alice, "Max")
+		first := createPet(t, s, alice, "Kit")
+		second := createPet(t, s, alice, "Pip")
+
+		for _, baby := range []*pet.Pet{first, second} {
+			if err := s.lineage.AddParents(ctx, baby.ID, mother.ID, father.ID); err != nil {
+				t.Fatalf("add parents: %v", err)
+			}
+		}
+
+		parents, err := s.lineage.Parents(ctx, first.ID)
+		if err != nil || fmt.Sprint(parents) != fmt.Sprint([]int{mother.ID, father.ID}) {
+			t.Errorf("parents %v: %v, want %d and %d", parents, err, mother.ID, father.ID)
+		}
+
+		children, err := s.lineage.Children(ctx, father.ID)
+		if err != nil || fmt.Sprint(children) != fmt.Sprint([]int{first.ID, second.ID}) {
+			t.Errorf("children %v: %v, want %d and %d", children, err, first.ID, second.ID)
+		}
+
+		parents, err = s.lineage.Parents(ctx, mother.ID)
+		if err != nil || len(parents) != 0 {
+			t.Errorf("parents of an adopted pet %v: %v, want none", parents, err)
+		}
+	})
+}
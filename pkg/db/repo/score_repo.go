@@ -0,0 +1,90 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/kirkegaard/terminal-pet/pkg/db"
+	"github.com/kirkegaard/terminal-pet/pkg/db/models"
+)
+
+type ScoreRepository struct {
+	db *db.DB
+}
+
+func NewScoreRepository(database *db.DB) *ScoreRepository {
+	return &ScoreRepository{
+		db: database,
+	}
+}
+
+// Add stores a new game score and sets its ID
+func (r *ScoreRepository) Add(ctx context.Context, score *models.GameScore) error {
+	if r.db == nil {
+		return fmt.Errorf("no database connection available")
+	}
+
+	if score.CreatedAt.IsZero() {
+		score.CreatedAt = time.Now().UTC()
+	}
+
+	err := r.db.QueryRowContext(ctx,
+		r.db.Rebind("INSERT INTO game_scores (user_id, pet_id, game, score, created_at) VALUES (?, ?, ?, ?, ?) RETURNING id"),
+		score.UserID, score.PetID, score.Game, score.Score, score.CreatedAt,
+	).Scan(&score.ID)
+	if err != nil {
+		return fmt.Errorf("create game score: %w", err)
+	}
+
+	return nil
+}
+
+// Best retrieves the highest score of a user in a game
+func (r *ScoreRepository) Best(ctx context.Context, userID int, game string) (int, error) {
+	if r.db == nil {
+		return 0, fmt.Errorf("no database connection available")
+	}
+
+	var best sql.NullInt64
+	err := r.db.QueryRowContext(ctx,
+		r.db.Rebind("SELECT MAX(score) FROM game_scores WHERE user_id = ? AND game = ?"),
+		userID, game,
+	).Scan(&best)
+	if err != nil {
+		return 0, fmt.Errorf("find best score: %w", err)
+	}
+
+	return int(best.Int64), nil
+}
+
+// Top retrieves the leaderboard of a game. Only the earliest of the best
+// scores of each user is listed.
+func (r *ScoreRepository) Top(ctx context.Context, game string, limit int) ([]models.GameScore, error) {
+	if r.db == nil {
+		return nil, fmt.Errorf("no database connection available")
+	}
+
+	query := `
+		SELECT s.id, s.user_id, s.pet_id, s.game, s.score, s.created_at,
+			u.name AS user_name, COALESCE(p.name, '') AS pet_name
+		FROM game_scores s
+		JOIN users u ON u.id = s.user_id
+		LEFT JOIN pets p ON p.id = s.pet_id
+		WHERE s.game = ? AND s.id = (
+			SELECT b.id FROM game_scores b
+			WHERE b.user_id = s.user_id AND b.game = s.game
+			ORDER BY b.score DESC, b.created_at ASC, b.id ASC
+			LIMIT 1
+		)
+		ORDER BY s.score DESC, s.created_at ASC, s.id ASC
+		LIMIT ?`
+
+	var scores []models.GameScore
+	if err := r.db.SelectContext(ctx, &scores, r.db.Rebind(query), game, limit); err != nil {
+		return nil, fmt.Errorf("list top scores: %w", err)
+	}
+
+	return scores, nil
+}
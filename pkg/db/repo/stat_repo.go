@@ -0,0 +1,186 @@
+package repo
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/kirkegaard/terminal-pet/pkg/db"
+	"github.com/kirkegaard/terminal-pet/pkg/db/models"
+)
+
+// statColumns are the columns of a stat snapshot
+const statColumns = "pet_id, bucket_seconds, bucket_start, hunger, happiness, health, weight, samples"
+
+type StatRepository struct {
+	db *db.DB
+}
+
+func NewStatRepository(database *db.DB) *StatRepository {
+	return &StatRepository{
+		db: database,
+	}
+}
+
+// Add stores a stat snapshot unless its bucket already has one
+func (r *StatRepository) Add(ctx context.Context, s *models.StatSnapshot) error {
+	if r.db == nil {
+		return fmt.Errorf("no database connection available")
+	}
+
+	if err := addSnapshot(ctx, r.db, r.db.Rebind, s); err != nil {
+		return fmt.Errorf("create stat snapshot: %w", err)
+	}
+
+	return nil
+}
+
+// ListByPetID retrieves the stat snapshots of a pet since the given time
+func (r *StatRepository) ListByPetID(ctx context.Context, petID int, since time.Time) ([]models.StatSnapshot, error) {
+	if r.db == nil {
+		return nil, fmt.Errorf("no database connection available")
+	}
+
+	var snapshots []models.StatSnapshot
+	err := r.db.SelectContext(ctx, &snapshots,
+		r.db.Rebind("SELECT "+statColumns+" FROM pet_stats WHERE pet_id = ? AND bucket_start >= ? ORDER BY bucket_start, bucket_seconds DESC"),
+		petID, since.UTC(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list stat snapshots: %w", err)
+	}
+
+	return snapshots, nil
+}
+
+// Compact replaces the snapshots in buckets smaller than the given size
+// that start before the given time by their averages, in a single
+// transaction. Averages for buckets that were compacted before are merged
+// into them.
+func (r *StatRepository) Compact(ctx context.Context, bucket time.Duration, before time.Time) (int, error) {
+	if r.db == nil {
+		return 0, fmt.Errorf("no database connection available")
+	}
+
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	seconds := int(bucket / time.Second)
+
+	var snapshots []models.StatSnapshot
+	err = tx.SelectContext(ctx, &snapshots,
+		tx.Rebind("SELECT "+statColumns+" FROM pet_stats WHERE bucket_seconds < ? AND bucket_start < ?"),
+		seconds, before.UTC(),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("list stat snapshots: %w", err)
+	}
+
+	if len(snapshots) == 0 {
+		return 0, nil
+	}
+
+	for _, s := range averageSnapshots(snapshots, bucket) {
+		if err := mergeSnapshot(ctx, tx, tx.Rebind, &s); err != nil {
+			return 0, fmt.Errorf("create compacted stat snapshot: %w", err)
+		}
+	}
+
+	_, err = tx.ExecContext(ctx,
+		tx.Rebind("DELETE FROM pet_stats WHERE bucket_seconds < ? AND bucket_start < ?"),
+		seconds, before.UTC(),
+	)
+	if err != nil {
+		return 0, fmt.Errorf("delete stat snapshots: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("commit compaction: %w", err)
+	}
+
+	return len(snapshots), nil
+}
+
+// addSnapshot stores a stat snapshot unless its bucket already has one
+func addSnapshot(ctx context.Context, e execer, rebind func(string) string, s *models.StatSnapshot) error {
+	_, err := e.ExecContext(ctx,
+		rebind(`INSERT INTO pet_stats (`+statColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+			ON CONFLICT (pet_id, bucket_seconds, bucket_start) DO NOTHING`),
+		s.PetID, s.BucketSeconds, s.At.UTC(), s.Hunger, s.Happiness, s.Health, s.Weight, max(s.Samples, 1),
+	)
+
+	return err
+}
+
+// mergeSnapshot stores a stat snapshot, or merges it into the one its
+// bucket already has weighted by their number of samples
+func mergeSnapshot(ctx context.Context, e execer, rebind func(string) string, s *models.StatSnapshot) error {
+	_, err := e.ExecContext(ctx,
+		rebind(`INSERT INTO pet_stats (`+statColumns+`) VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+			ON CONFLICT (pet_id, bucket_seconds, bucket_start) DO UPDATE SET
+				hunger = (pet_stats.hunger * pet_stats.samples + excluded.hunger * excluded.samples) / (pet_stats.samples + excluded.samples),
+				happiness = (pet_stats.happiness * pet_stats.samples + excluded.happiness * excluded.samples) / (pet_stats.samples + excluded.samples),
+				health = (pet_stats.health * pet_stats.samples + excluded.health * excluded.samples) / (pet_stats.samples + excluded.samples),
+				weight = (pet_stats.weight * pet_stats.samples + excluded.weight * excluded.samples) / (pet_stats.samples + excluded.samples),
+				samples = pet_stats.samples + excluded.samples`),
+		s.PetID, s.BucketSeconds, s.At.UTC(), s.Hunger, s.Happiness, s.Health, s.Weight, max(s.Samples, 1),
+	)
+
+	return err
+}
+
+// averageSnapshots averages snapshots over buckets of the given size,
+// weighting each by its number of samples. The averages are ordered by pet
+// and time.
+func averageSnapshots(snapshots []models.StatSnapshot, bucket time.Duration) []models.StatSnapshot {
+	type key struct {
+		petID int
+		at    time.Time
+	}
+
+	type sum struct {
+		hunger, happiness, health, weight, samples int
+	}
+
+	sums := make(map[key]*sum)
+	for _, s := range snapshots {
+		k := key{petID: s.PetID, at: s.At.UTC().Truncate(bucket)}
+		if sums[k] == nil {
+			sums[k] = &sum{}
+		}
+
+		samples := max(s.Samples, 1)
+		sums[k].hunger += s.Hunger * samples
+		sums[k].happiness += s.Happiness * samples
+		sums[k].health += s.Health * samples
+		sums[k].weight += s.Weight * samples
+		sums[k].samples += samples
+	}
+
+	averages := make([]models.StatSnapshot, 0, len(sums))
+	for k, s := range sums {
+		averages = append(averages, models.StatSnapshot{
+			PetID:         k.petID,
+			BucketSeconds: int(bucket / time.Second),
+			At:            k.at,
+			Hunger:        s.hunger / s.samples,
+			Happiness:     s.happiness / s.samples,
+			Health:        s.health / s.samples,
+			Weight:        s.weight / s.samples,
+			Samples:       s.samples,
+		})
+	}
+
+	sort.Slice(averages, func(i, j int) bool {
+		if averages[i].PetID != averages[j].PetID {
+			return averages[i].PetID < averages[j].PetID
+		}
+		return averages[i].At.Before(averages[j].At)
+	})
+
+	return averages
+}
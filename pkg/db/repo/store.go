@@ -0,0 +1,227 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/kirkegaard/terminal-pet/pkg/db/models"
+	"github.com/kirkegaard/terminal-pet/pkg/pet"
+)
+
+// PetStore persists pets
+type PetStore interface {
+	// Create stores a new pet and sets its ID
+	Create(ctx context.Context, p *pet.Pet) error
+	// GetByID returns the pet with the given ID, or nil if there is none
+	GetByID(ctx context.Context, id int) (*pet.Pet, error)
+	// GetByParentID returns the newest pet of a user, or nil if there is none
+	GetByParentID(ctx context.Context, parentID int) (*pet.Pet, error)
+	// ListAlive returns every pet that is still alive
+	ListAlive(ctx context.Context) ([]*pet.Pet, error)
+	// ListAliveByParentID returns the living pets of a user, oldest first
+	ListAliveByParentID(ctx context.Context, parentID int) ([]*pet.Pet, error)
+	// ListDeadByParentID returns the deceased pets of a user, most recent first
+	ListDeadByParentID(ctx context.Context, parentID int) ([]*pet.Pet, error)
+	// SetEpitaph stores the epitaph of a deceased pet
+	SetEpitaph(ctx context.Context, id int, epitaph string) error
+	// Update stores the current state of an existing pet and moves it to
+	// the next revision, or returns ErrPetConflict if it was saved by someone
+	// else since it was loaded
+	Update(ctx context.Context, p *pet.Pet) error
+	// UpdateSimulated stores a pet advanced by the world simulation, unless
+	// it was saved by someone else since it was loaded
+	UpdateSimulated(ctx context.Context, p *pet.Pet) (bool, error)
+	// Delete removes a pet
+	Delete(ctx context.Context, id int) error
+	// FindByParentPublicKey returns the newest pet of the user with the
+	// given public key, or nil if there is none
+	FindByParentPublicKey(ctx context.Context, publicKey string) (*pet.Pet, error)
+	// Save creates or updates a pet, creating its owner if needed
+	Save(ctx context.Context, p *pet.Pet, publicKey string) error
+}
+
+// UserStore persists users
+type UserStore interface {
+	// GetByPublicKey returns the ID of the user with the given public key,
+	// or 0 if there is none
+	GetByPublicKey(ctx context.Context, publicKey string) (int, error)
+	// Create stores a new user and returns its ID
+	Create(ctx context.Context, name, publicKey string) (int, error)
+	// FindByPublicKey returns the user with the given public key, or nil if
+	// there is none
+	FindByPublicKey(ctx context.Context, publicKey string) (*models.User, error)
+	// FindByID returns the user with the given ID, or nil if there is none
+	FindByID(ctx context.Context, id int) (*models.User, error)
+	// FindByName returns the user registered with the given name, ignoring
+	// case, or nil if there is none. Names aren't unique, ErrAmbiguousName is
+	// returned if more than one user has it.
+	FindByName(ctx context.Context, name string) (*models.User, error)
+	// SetPrivate changes whether other players may visit the user's pets
+	SetPrivate(ctx context.Context, userID int, private bool) error
+	// SetLastSeen records when the user was last around
+	SetLastSeen(ctx context.Context, userID int, at time.Time) error
+}
+
+// TokenStore persists API tokens. Only a hash of each token is stored.
+type TokenStore interface {
+	// Create stores the hash of a new token for a user
+	Create(ctx context.Context, userID int, tokenHash string) error
+	// GetUserID returns the ID of the user owning the token hash, or 0 if
+	// there is none
+	GetUserID(ctx context.Context, tokenHash string) (int, error)
+	// DeleteByUserID revokes every token of a user and returns how many
+	// were revoked
+	DeleteByUserID(ctx context.Context, userID int) (int, error)
+}
+
+// InventoryStore persists the coins of each player and the items they own
+type InventoryStore interface {
+	// Coins returns the coin balance of a user
+	Coins(ctx context.Context, userID int) (int, error)
+	// AddCoins adds coins to the balance of a user and returns the new
+	// balance
+	AddCoins(ctx context.Context, userID int, amount int) (int, error)
+	// ClaimDailyReward adds coins to the balance of a user once per UTC day
+	// and reports whether they were added
+	ClaimDailyReward(ctx context.Context, userID int, amount int, now time.Time) (bool, error)
+	// Items returns how many of each item a user owns, by item ID
+	Items(ctx context.Context, userID int) (map[string]int, error)
+	// AddItem adds items to the inventory of a user
+	AddItem(ctx context.Context, userID int, itemID string, quantity int) error
+	// UseItem takes one item out of the inventory of a user, or returns
+	// ErrOutOfStock if they have none
+	UseItem(ctx context.Context, userID int, itemID string) error
+	// Buy pays the price from the balance of a user and adds one item to
+	// their inventory, or returns ErrNotEnoughCoins if they can't afford it.
+	// It returns the new balance.
+	Buy(ctx context.Context, userID int, itemID string, price int) (int, error)
+}
+
+// ScoreStore persists the scores reached in minigames
+type ScoreStore interface {
+	// Add stores a new score
+	Add(ctx context.Context, score *models.GameScore) error
+	// Best returns the highest score of a user in a game, or 0 if they
+	// never played it
+	Best(ctx context.Context, userID int, game string) (int, error)
+	// Top returns the best score of each user in a game, highest first and
+	// earliest first among equal scores, with the names of the user and pet
+	Top(ctx context.Context, game string, limit int) ([]models.GameScore, error)
+}
+
+// AchievementStore persists the progress of each player towards their
+// achievements
+type AchievementStore interface {
+	// List returns the progress of a user towards every achievement they
+	// made progress on or unlocked
+	List(ctx context.Context, userID int) ([]models.Achievement, error)
+	// AddProgress adds to the progress of a user towards an achievement and
+	// returns the new progress
+	AddProgress(ctx context.Context, userID int, achievementID string, amount int) (int, error)
+	// Unlock records that a user unlocked an achievement and reports
+	// whether it was unlocked just now
+	Unlock(ctx context.Context, userID int, achievementID string, at time.Time) (bool, error)
+}
+
+// PetEventStore persists the activity log of each pet. Entries are only
+// ever added.
+type PetEventStore interface {
+	// Add appends an entry to the activity log of a pet
+	Add(ctx context.Context, event *models.PetEvent) error
+	// ListByPetID returns the latest entries in the activity log of a pet,
+	// newest first
+	ListByPetID(ctx context.Context, petID int, limit int) ([]models.PetEvent, error)
+}
+
+// StatStore persists snapshots of the stats of each pet. Old snapshots are
+// compacted into averages over larger buckets of time.
+type StatStore interface {
+	// Add stores a snapshot, unless the pet already has one for the same
+	// bucket
+	Add(ctx context.Context, s *models.StatSnapshot) error
+	// ListByPetID returns the snapshots of a pet starting at or after the
+	// given time, oldest first
+	ListByPetID(ctx context.Context, petID int, since time.Time) ([]models.StatSnapshot, error)
+	// Compact replaces the snapshots in buckets smaller than the given size
+	// that start before the given time by their averages over buckets of
+	// that size, and returns how many snapshots were replaced
+	Compact(ctx context.Context, bucket time.Duration, before time.Time) (int, error)
+}
+
+// LineageStore persists who the parents of bred pets are
+type LineageStore interface {
+	// AddParents records the parents of a bred pet
+	AddParents(ctx context.Context, petID int, parentIDs ...int) error
+	// Parents returns the IDs of the parents of a pet, none if it was
+	// adopted
+	Parents(ctx context.Context, petID int) ([]int, error)
+	// Children returns the IDs of the babies of a pet, oldest first
+	Children(ctx context.Context, petID int) ([]int, error)
+}
+
+// FriendStore persists friendships between users. A friendship starts out
+// as a request from one user to the other until it is accepted.
+type FriendStore interface {
+	// Get returns the friendship or request between two users, in either
+	// direction, or nil if there is none
+	Get(ctx context.Context, userID, otherID int) (*models.Friend, error)
+	// Request stores a friend request from one user to another
+	Request(ctx context.Context, fromID, toID int, at time.Time) error
+	// Accept accepts the friend request from one user to another, or
+	// returns ErrNoFriendRequest if there is none
+	Accept(ctx context.Context, fromID, toID int, at time.Time) error
+	// Remove ends a friendship, or withdraws or declines a request, and
+	// reports whether there was one
+	Remove(ctx context.Context, userID, otherID int) (bool, error)
+	// List returns the friends and friend requests of a user, friends first
+	// and by name
+	List(ctx context.Context, userID int) ([]models.Friend, error)
+}
+
+// GiftStore persists the items users send each other
+type GiftStore interface {
+	// Send takes one of the item out of the sender's inventory and stores
+	// the gift, or returns ErrOutOfStock if they have none. It sets the ID
+	// of the gift.
+	Send(ctx context.Context, gift *models.Gift) error
+	// Deliver adds the undelivered gifts of a user to their inventory and
+	// returns them, oldest first with the names of the senders
+	Deliver(ctx context.Context, userID int, at time.Time) ([]models.Gift, error)
+}
+
+// StartingCoins is the balance every player starts with
+const StartingCoins = 100
+
+var (
+	ErrOutOfStock      = errors.New("out of stock")
+	ErrNotEnoughCoins  = errors.New("not enough coins")
+	ErrNoFriendRequest = errors.New("no friend request")
+	ErrAmbiguousName   = errors.New("more than one user has that name")
+	ErrPetConflict     = errors.New("pet was saved by someone else since it was loaded")
+)
+
+var (
+	_ PetStore         = (*PetRepository)(nil)
+	_ UserStore        = (*UserRepository)(nil)
+	_ TokenStore       = (*TokenRepository)(nil)
+	_ InventoryStore   = (*InventoryRepository)(nil)
+	_ ScoreStore       = (*ScoreRepository)(nil)
+	_ AchievementStore = (*AchievementRepository)(nil)
+	_ PetEventStore    = (*PetEventRepository)(nil)
+	_ StatStore        = (*StatRepository)(nil)
+	_ LineageStore     = (*LineageRepository)(nil)
+	_ FriendStore      = (*FriendRepository)(nil)
+	_ GiftStore        = (*GiftRepository)(nil)
+	_ PetStore         = (*MemoryPetRepository)(nil)
+	_ UserStore        = (*MemoryUserRepository)(nil)
+	_ TokenStore       = (*MemoryTokenRepository)(nil)
+	_ InventoryStore   = (*MemoryInventoryRepository)(nil)
+	_ ScoreStore       = (*MemoryScoreRepository)(nil)
+	_ AchievementStore = (*MemoryAchievementRepository)(nil)
+	_ PetEventStore    = (*MemoryPetEventRepository)(nil)
+	_ StatStore        = (*MemoryStatRepository)(nil)
+	_ LineageStore     = (*MemoryLineageRepository)(nil)
+	_ FriendStore      = (*MemoryFriendRepository)(nil)
+	_ GiftStore        = (*MemoryGiftRepository)(nil)
+)
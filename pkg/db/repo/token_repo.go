@@ -0,0 +1,74 @@
+package repo
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/kirkegaard/terminal-pet/pkg/db"
+)
+
+type TokenRepository struct {
+	db *db.DB
+}
+
+func NewTokenRepository(database *db.DB) *TokenRepository {
+	return &TokenRepository{
+		db: database,
+	}
+}
+
+// Create stores the hash of a new API token for a user
+func (r *TokenRepository) Create(ctx context.Context, userID int, tokenHash string) error {
+	if r.db == nil {
+		return fmt.Errorf("no database connection available")
+	}
+
+	_, err := r.db.ExecContext(ctx,
+		r.db.Rebind("INSERT INTO api_tokens (user_id, token_hash, created_at) VALUES (?, ?, ?)"),
+		userID, tokenHash, time.Now().UTC(),
+	)
+	if err != nil {
+		return fmt.Errorf("create api token: %w", err)
+	}
+
+	return nil
+}
+
+// GetUserID retrieves the ID of the user owning a token hash
+func (r *TokenRepository) GetUserID(ctx context.Context, tokenHash string) (int, error) {
+	if r.db == nil {
+		return 0, fmt.Errorf("no database connection available")
+	}
+
+	var userID int
+	err := r.db.QueryRowContext(ctx, r.db.Rebind("SELECT user_id FROM api_tokens WHERE token_hash = ?"), tokenHash).Scan(&userID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("find api token: %w", err)
+	}
+
+	return userID, nil
+}
+
+// DeleteByUserID revokes every token of a user
+func (r *TokenRepository) DeleteByUserID(ctx context.Context, userID int) (int, error) {
+	if r.db == nil {
+		return 0, fmt.Errorf("no database connection available")
+	}
+
+	result, err := r.db.ExecContext(ctx, r.db.Rebind("DELETE FROM api_tokens WHERE user_id = ?"), userID)
+	if err != nil {
+		return 0, fmt.Errorf("delete api tokens: %w", err)
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("get rows affected: %w", err)
+	}
+
+	return int(deleted), nil
+}
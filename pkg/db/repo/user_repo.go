@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"time"
 
 	"github.com/kirkegaard/terminal-pet/pkg/db"
 	"github.com/kirkegaard/terminal-pet/pkg/db/models"
@@ -14,10 +15,6 @@ type UserRepository struct {
 }
 
 func NewUserRepository(database *db.DB) *UserRepository {
-	if database == nil {
-		database = db.GetInstance()
-	}
-
 	return &UserRepository{
 		db: database,
 	}
@@ -30,7 +27,7 @@ func (r *UserRepository) GetByPublicKey(ctx context.Context, publicKey string) (
 	}
 
 	var userID int
-	err := r.db.QueryRow("SELECT id FROM users WHERE public_key = ?", publicKey).Scan(&userID)
+	err := r.db.QueryRowContext(ctx, r.db.Rebind("SELECT id FROM users WHERE public_key = ?"), publicKey).Scan(&userID)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return 0, nil
@@ -47,38 +44,118 @@ func (r *UserRepository) Create(ctx context.Context, name, publicKey string) (in
 		return 0, fmt.Errorf("no database connection available")
 	}
 
-	res, err := r.db.Exec("INSERT INTO users (name, public_key) VALUES (?, ?)", name, publicKey)
+	var id int
+	err := r.db.QueryRowContext(ctx,
+		r.db.Rebind("INSERT INTO users (name, public_key) VALUES (?, ?) RETURNING id"),
+		name, publicKey,
+	).Scan(&id)
 	if err != nil {
 		return 0, fmt.Errorf("create user: %w", err)
 	}
 
-	id, err := res.LastInsertId()
+	return id, nil
+}
+
+func (r *UserRepository) FindByPublicKey(ctx context.Context, publicKey string) (*models.User, error) {
+	if r.db == nil {
+		return nil, fmt.Errorf("no database connection available")
+	}
+
+	var user models.User
+
+	err := r.db.QueryRowContext(ctx, r.db.Rebind("SELECT id, name, public_key, is_private, last_seen_at FROM users WHERE public_key = ?"), publicKey).Scan(
+		&user.ID,
+		&user.Name,
+		&user.PublicKey,
+		&user.Private,
+		&user.LastSeenAt,
+	)
+
 	if err != nil {
-		return 0, fmt.Errorf("get last insert id: %w", err)
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("find user by public key: %w", err)
 	}
 
-	return int(id), nil
+	return &user, nil
 }
 
-func (r *UserRepository) FindByPublicKey(ctx context.Context, publicKey string) (*models.User, error) {
+// FindByID retrieves a user by ID
+func (r *UserRepository) FindByID(ctx context.Context, id int) (*models.User, error) {
 	if r.db == nil {
 		return nil, fmt.Errorf("no database connection available")
 	}
 
 	var user models.User
 
-	err := r.db.QueryRow("SELECT id, name, public_key FROM users WHERE public_key = ?", publicKey).Scan(
+	err := r.db.QueryRowContext(ctx, r.db.Rebind("SELECT id, name, public_key, is_private, last_seen_at FROM users WHERE id = ?"), id).Scan(
 		&user.ID,
 		&user.Name,
 		&user.PublicKey,
+		&user.Private,
+		&user.LastSeenAt,
 	)
 
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return nil, nil
 		}
-		return nil, fmt.Errorf("find user by public key: %w", err)
+		return nil, fmt.Errorf("find user by id: %w", err)
 	}
 
 	return &user, nil
 }
+
+// FindByName retrieves the user registered with a name, ignoring case
+func (r *UserRepository) FindByName(ctx context.Context, name string) (*models.User, error) {
+	if r.db == nil {
+		return nil, fmt.Errorf("no database connection available")
+	}
+
+	var users []models.User
+	err := r.db.SelectContext(ctx, &users,
+		r.db.Rebind("SELECT id, name, public_key, is_private, last_seen_at FROM users WHERE LOWER(name) = LOWER(?) ORDER BY id LIMIT 2"),
+		name,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("find user by name: %w", err)
+	}
+
+	switch len(users) {
+	case 0:
+		return nil, nil
+	case 1:
+		return &users[0], nil
+	default:
+		return nil, ErrAmbiguousName
+	}
+}
+
+// SetPrivate changes whether other players may visit the user's pets
+func (r *UserRepository) SetPrivate(ctx context.Context, userID int, private bool) error {
+	if r.db == nil {
+		return fmt.Errorf("no database connection available")
+	}
+
+	_, err := r.db.ExecContext(ctx, r.db.Rebind("UPDATE users SET is_private = ? WHERE id = ?"), private, userID)
+	if err != nil {
+		return fmt.Errorf("update user privacy: %w", err)
+	}
+
+	return nil
+}
+
+// SetLastSeen records when the user was last around
+func (r *UserRepository) SetLastSeen(ctx context.Context, userID int, at time.Time) error {
+	if r.db == nil {
+		return fmt.Errorf("no database connection available")
+	}
+
+	_, err := r.db.ExecContext(ctx, r.db.Rebind("UPDATE users SET last_seen_at = ? WHERE id = ?"), at.UTC(), userID)
+	if err != nil {
+		return fmt.Errorf("update user last seen: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,27 @@
+package events
+
+import (
+	"context"
+)
+
+// ActorWorld is the actor of events that happened while no player was there,
+// such as those of the world ticker
+const ActorWorld = "world"
+
+type actorKey struct{}
+
+// WithActor returns a context for publishing events that happened while the
+// named player was there
+func WithActor(ctx context.Context, name string) context.Context {
+	return context.WithValue(ctx, actorKey{}, name)
+}
+
+// Actor returns the player who was there when the events published with the
+// context happened, or ActorWorld if nobody was
+func Actor(ctx context.Context) string {
+	if name, ok := ctx.Value(actorKey{}).(string); ok && name != "" {
+		return name
+	}
+
+	return ActorWorld
+}
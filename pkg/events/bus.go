@@ -0,0 +1,91 @@
+// Package events passes the events of pets between the parts of the server.
+// Whoever changes a pet publishes the events it recorded, and subscribers
+// such as achievements react to them without knowing where they came from.
+package events
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"github.com/kirkegaard/terminal-pet/pkg/pet"
+)
+
+// Handler reacts to an event. Handlers are called on the goroutine that
+// published the event, so they must not block for long.
+type Handler func(ctx context.Context, e pet.Event)
+
+// Bus delivers published events to every subscriber. It is safe for
+// concurrent use, and a nil Bus drops every event.
+type Bus struct {
+	mu          sync.RWMutex
+	nextID      int
+	subscribers []subscriber
+}
+
+type subscriber struct {
+	id      int
+	handler Handler
+}
+
+// NewBus creates a bus without subscribers
+func NewBus() *Bus {
+	return &Bus{}
+}
+
+// Subscribe calls the handler for every event published from now on until
+// the returned function is called. Handlers are called in the order they
+// subscribed.
+func (b *Bus) Subscribe(h Handler) (unsubscribe func()) {
+	if b == nil {
+		return func() {}
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	id := b.nextID
+	b.nextID++
+	b.subscribers = append(b.subscribers, subscriber{id: id, handler: h})
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+
+			b.subscribers = slices.DeleteFunc(b.subscribers, func(s subscriber) bool {
+				return s.id == id
+			})
+		})
+	}
+}
+
+// SubscribeContext calls the handler for every event published until the
+// context is done
+func (b *Bus) SubscribeContext(ctx context.Context, h Handler) {
+	unsubscribe := b.Subscribe(h)
+
+	go func() {
+		<-ctx.Done()
+		unsubscribe()
+	}()
+}
+
+// Publish delivers the events in order to every subscriber. Events
+// published by a handler are delivered before Publish returns.
+func (b *Bus) Publish(ctx context.Context, events ...pet.Event) {
+	if b == nil || len(events) == 0 {
+		return
+	}
+
+	b.mu.RLock()
+	subscribers := slices.Clone(b.subscribers)
+	b.mu.RUnlock()
+
+	for _, e := range events {
+		for _, s := range subscribers {
+			s.handler(ctx, e)
+		}
+	}
+}
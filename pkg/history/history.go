@@ -0,0 +1,119 @@
+// Package history keeps the activity log of every pet. The logger subscribes
+// to the event bus and appends each event of a pet to its log, along with
+// the player who was there when it happened.
+package history
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/charmbracelet/log"
+	"github.com/kirkegaard/terminal-pet/pkg/achievements"
+	"github.com/kirkegaard/terminal-pet/pkg/db/models"
+	"github.com/kirkegaard/terminal-pet/pkg/db/repo"
+	"github.com/kirkegaard/terminal-pet/pkg/events"
+	"github.com/kirkegaard/terminal-pet/pkg/pet"
+)
+
+// Kinds of log entries
+const (
+	KindFed         = "fed"
+	KindPlayed      = "played"
+	KindCleaned     = "cleaned"
+	KindMedicated   = "medicated"
+	KindSick        = "sick"
+	KindPooped      = "pooped"
+	KindGrew        = "grew"
+	KindDied        = "died"
+	KindRenamed     = "renamed"
+	KindAchievement = "achievement"
+	KindPlaydate    = "playdate"
+	KindBaby        = "baby"
+)
+
+// Logger appends the events published on the bus to the activity log of
+// their pet
+type Logger struct {
+	store repo.PetEventStore
+}
+
+// NewLogger creates a logger that keeps the log in the given store
+func NewLogger(store repo.PetEventStore) *Logger {
+	return &Logger{
+		store: store,
+	}
+}
+
+// Handle appends an event to the activity log of its pet. It is meant to be
+// subscribed to the event bus.
+func (l *Logger) Handle(ctx context.Context, e pet.Event) {
+	entry, ok := Entry(e, events.Actor(ctx))
+	if !ok {
+		return
+	}
+
+	if err := l.store.Add(ctx, &entry); err != nil {
+		log.Error("Error logging pet event", "pet_id", entry.PetID, "kind", entry.Kind, "error", err)
+	}
+}
+
+// Entry turns an event into an entry of the activity log. It returns false
+// for events that aren't logged and for pets that were never saved.
+func Entry(e pet.Event, actor string) (models.PetEvent, bool) {
+	p := e.EventPet()
+	if p == nil || p.ID == 0 {
+		return models.PetEvent{}, false
+	}
+
+	entry := models.PetEvent{
+		PetID:     p.ID,
+		Actor:     actor,
+		CreatedAt: e.EventTime(),
+	}
+
+	switch e := e.(type) {
+	case pet.Fed:
+		entry.Kind = KindFed
+		if e.Food != nil {
+			entry.Detail = e.Food.Name
+		}
+	case pet.Played:
+		entry.Kind = KindPlayed
+		if e.Game != "" {
+			entry.Detail = fmt.Sprintf("%s %d/%d", e.Game, e.Score, e.MaxScore)
+		}
+	case pet.Cleaned:
+		entry.Kind = KindCleaned
+	case pet.Medicated:
+		entry.Kind = KindMedicated
+		if e.Cured {
+			entry.Detail = "cured"
+		}
+	case pet.BecameSick:
+		entry.Kind = KindSick
+	case pet.Pooped:
+		entry.Kind = KindPooped
+	case pet.StageChanged:
+		entry.Kind = KindGrew
+		entry.Detail = e.To
+	case pet.Died:
+		entry.Kind = KindDied
+		entry.Detail = e.Cause
+	case pet.Renamed:
+		entry.Kind = KindRenamed
+		entry.Detail = e.From + " → " + e.To
+	case pet.HadPlaydate:
+		entry.Kind = KindPlaydate
+		entry.Detail = e.Friend
+	case pet.HadBaby:
+		entry.Kind = KindBaby
+		entry.Detail = e.Baby
+	case achievements.Unlocked:
+		entry.Kind = KindAchievement
+		entry.Detail = e.Achievement.Emoji + " " + e.Achievement.Name
+	default:
+		return models.PetEvent{}, false
+	}
+
+	return entry, true
+}
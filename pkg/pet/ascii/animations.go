@@ -199,33 +199,6 @@ var (
 		FPS: 4,
 	}
 
-	CakeEating = Animation{
-		Name: "CakeEating",
-		Frames: []string{
-			`
- /\_/\
-( o.o )    🍰
- > ^ <
-`,
-			`
- /\_/\
-( o.o )  🍰
- > ^ <
-`,
-			`
- /\_/\
-( o-o )🍰
- > ^ <
-`,
-			`
- /\_/\
-( ^-^ )
- > ^ <
-`,
-		},
-		FPS: 4,
-	}
-
 	RightEyeBlink = `
  /\_/\
 ( -.^ )
@@ -256,22 +229,8 @@ var (
 	}
 )
 
-// GetAnimationForState returns the appropriate animation for the given pet state
+// GetAnimationForState returns the appropriate animation of the classic cat
+// for the given pet state
 func GetAnimationForState(state PetState) Animation {
-	switch state {
-	case StateDead:
-		return Dead
-	case StateSleeping:
-		return LightsOff
-	case StateSick:
-		return Sick
-	case StateHungry:
-		return Hungry
-	case StateSad:
-		return Sad
-	case StateHappy:
-		return Happy
-	default:
-		return Idle
-	}
+	return Kitten.ForState(state)
 }
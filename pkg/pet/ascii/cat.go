@@ -0,0 +1,77 @@
+package ascii
+
+// Animation sets of the cat characters. Kitten is the classic hand drawn
+// cat, the others are drawn from their bodies.
+var (
+	Kitten = AnimationSet{
+		Happy:   Happy,
+		Idle:    Idle,
+		Sad:     Sad,
+		Sick:    Sick,
+		Hungry:  Hungry,
+		Sleepy:  Sleepy,
+		Dead:    Dead,
+		Playing: Playing,
+		Eating:  Eating,
+	}
+
+	Puffball = NewAnimationSet(Body{
+		Top:    "  .---.",
+		Face:   " ( %s )",
+		Bottom: "  `---'",
+	})
+
+	Scamp = NewAnimationSet(Body{
+		Top:    ` /\ /\`,
+		Face:   "( %s )~",
+		Bottom: ` /   \`,
+	})
+
+	Tabby = NewAnimationSet(Body{
+		Top:    `  /\_/\`,
+		Face:   "={ %s }=",
+		Bottom: "   )   (",
+	})
+
+	AlleyCat = NewAnimationSet(Body{
+		Top:    ` /|_/\`,
+		Face:   "( %s )",
+		Bottom: ` > ~ <`,
+	})
+
+	Lion = NewAnimationSet(Body{
+		Top:    ` \\|||//`,
+		Face:   "-( %s )-",
+		Bottom: ` //|||\\`,
+	})
+
+	HouseCat = NewAnimationSet(Body{
+		Top:    `  /\___/\`,
+		Face:   " (  %s  )",
+		Bottom: `  (")_(")`,
+	})
+
+	TomCat = NewAnimationSet(Body{
+		Top:    ` /\_/\`,
+		Face:   "( %s )>",
+		Bottom: ` \_=_/`,
+	})
+
+	Chonk = NewAnimationSet(Body{
+		Top:    `  /\___/\`,
+		Face:   " (  %s  )",
+		Bottom: "(         )\n `-------'",
+	})
+
+	Sage = NewAnimationSet(Body{
+		Top:    ` /\_/\`,
+		Face:   "( %s )",
+		Bottom: ` \|||/`,
+	})
+
+	Grump = NewAnimationSet(Body{
+		Top:    ` /\_/\`,
+		Face:   "( %s )=",
+		Bottom: ` > - <`,
+	})
+)
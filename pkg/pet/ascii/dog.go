@@ -0,0 +1,70 @@
+package ascii
+
+// Animation sets of the dog characters
+var (
+	Pup = NewAnimationSet(Body{
+		Top:    "  .--.",
+		Face:   "U %s U",
+		Bottom: "  '  '",
+	})
+
+	Puppy = NewAnimationSet(Body{
+		Top:    "  ___",
+		Face:   "U( %s )U",
+		Bottom: "  U   U",
+	})
+
+	Mutt = NewAnimationSet(Body{
+		Top:    "  _,_",
+		Face:   "V( %s )V",
+		Bottom: "  (_ _)~",
+	})
+
+	Beagle = NewAnimationSet(Body{
+		Top:    "  __ __",
+		Face:   "(( %s ))",
+		Bottom: "   (oo)",
+	})
+
+	Stray = NewAnimationSet(Body{
+		Top:    ` _/ \_`,
+		Face:   "|( %s )|",
+		Bottom: ` /|  |\~`,
+	})
+
+	Shepherd = NewAnimationSet(Body{
+		Top:    `  /\   /\`,
+		Face:   " / ( %s ) \\",
+		Bottom: `    \_Y_/`,
+	})
+
+	Retriever = NewAnimationSet(Body{
+		Top:    "   _____",
+		Face:   " U( %s )U",
+		Bottom: "   (_o_)~",
+	})
+
+	Hound = NewAnimationSet(Body{
+		Top:    " _       _",
+		Face:   "(_( %s )_)",
+		Bottom: `   \_w_/ ~`,
+	})
+
+	Bulldog = NewAnimationSet(Body{
+		Top:    "   _____",
+		Face:   " U(  %s  )U",
+		Bottom: "  (       )\n   '-----'",
+	})
+
+	Greymuzzle = NewAnimationSet(Body{
+		Top:    "   _____",
+		Face:   " U( %s )U",
+		Bottom: "   (~~~)",
+	})
+
+	Grouch = NewAnimationSet(Body{
+		Top:    "   _____",
+		Face:   " U( %s )U=",
+		Bottom: "   (---)",
+	})
+)
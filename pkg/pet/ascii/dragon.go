@@ -0,0 +1,70 @@
+package ascii
+
+// Animation sets of the dragon characters
+var (
+	Hatchling = NewAnimationSet(Body{
+		Top:    `  _/\_`,
+		Face:   " ( %s )",
+		Bottom: `  \___/`,
+	})
+
+	Whelp = NewAnimationSet(Body{
+		Top:    `  /\ /\`,
+		Face:   " <( %s )>",
+		Bottom: `   /vv\`,
+	})
+
+	Runt = NewAnimationSet(Body{
+		Top:    `  /\ /|`,
+		Face:   " <( %s )",
+		Bottom: `   /vv\`,
+	})
+
+	Drake = NewAnimationSet(Body{
+		Top:    ` /\_/\_/\`,
+		Face:   " \\( %s )/",
+		Bottom: `   /VV\~`,
+	})
+
+	Cinder = NewAnimationSet(Body{
+		Top:    ` /|_/\_|\`,
+		Face:   " \\( %s )/",
+		Bottom: `   /vv\ .`,
+	})
+
+	GoldDragon = NewAnimationSet(Body{
+		Top:    `/\  /\_/\  /\`,
+		Face:   "\\ \\ ( %s ) / /",
+		Bottom: `   \/VVV\/~`,
+	})
+
+	Dragon = NewAnimationSet(Body{
+		Top:    ` /\_/\_/\`,
+		Face:   "<( %s )>~",
+		Bottom: `   /VVV\`,
+	})
+
+	Wyvern = NewAnimationSet(Body{
+		Top:    `\/\_/\_/\/`,
+		Face:   " >( %s )<",
+		Bottom: `   /vv\~~`,
+	})
+
+	Hoarder = NewAnimationSet(Body{
+		Top:    `  /\_/\_/\`,
+		Face:   " <(  %s  )>",
+		Bottom: "(    $$$    )\n '-----------'",
+	})
+
+	Ancient = NewAnimationSet(Body{
+		Top:    ` /\_/\_/\`,
+		Face:   "<( %s )>",
+		Bottom: `  /|||\~`,
+	})
+
+	Ashen = NewAnimationSet(Body{
+		Top:    ` /\_/\_/\`,
+		Face:   "<( %s )>.",
+		Bottom: `  /...\`,
+	})
+)
@@ -0,0 +1,129 @@
+package ascii
+
+import (
+	"fmt"
+	"strings"
+)
+
+// AnimationSet holds every animation a character can show
+type AnimationSet struct {
+	Happy   Animation
+	Idle    Animation
+	Sad     Animation
+	Sick    Animation
+	Hungry  Animation
+	Sleepy  Animation
+	Dead    Animation
+	Playing Animation
+
+	// Eating shows the pet eating a burger, use EatingFood for other foods
+	Eating Animation
+}
+
+// ForState returns the animation of the set for the given pet state
+func (s AnimationSet) ForState(state PetState) Animation {
+	switch state {
+	case StateDead:
+		return s.Dead
+	case StateSleeping:
+		return LightsOff
+	case StateSick:
+		return s.Sick
+	case StateHungry:
+		return s.Hungry
+	case StateSad:
+		return s.Sad
+	case StateHappy:
+		return s.Happy
+	default:
+		return s.Idle
+	}
+}
+
+// eatingPlaceholder is the food drawn in the Eating animation
+const eatingPlaceholder = "🍔"
+
+// EatingFood returns the eating animation with the given food, usually an
+// emoji, in place of the burger
+func (s AnimationSet) EatingFood(food string) Animation {
+	if food == "" || food == eatingPlaceholder {
+		return s.Eating
+	}
+
+	frames := make([]string, len(s.Eating.Frames))
+	for i, frame := range s.Eating.Frames {
+		frames[i] = strings.ReplaceAll(frame, eatingPlaceholder, food)
+	}
+
+	return Animation{Name: s.Eating.Name, Frames: frames, FPS: s.Eating.FPS}
+}
+
+// Body is the ASCII art of a character split in three parts. Face is a
+// format string with a single %s that is replaced by the expression, for
+// example "( %s )". Expressions are always three characters wide.
+type Body struct {
+	Top    string
+	Face   string
+	Bottom string
+}
+
+// frame draws the body with the given expression and anything shown to the
+// right of the face, like a ball or food
+func (b Body) frame(expression, extra string) string {
+	return "\n" + b.Top + "\n" + fmt.Sprintf(b.Face, expression) + extra + "\n" + b.Bottom + "\n"
+}
+
+// animation draws one frame per expression
+func (b Body) animation(name string, fps int, expressions ...string) Animation {
+	frames := make([]string, len(expressions))
+	for i, expression := range expressions {
+		frames[i] = b.frame(expression, "")
+	}
+
+	return Animation{Name: name, Frames: frames, FPS: fps}
+}
+
+// eating draws the body watching food come closer before eating it
+func (b Body) eating(food string) Animation {
+	return Animation{
+		Name: "Eating",
+		Frames: []string{
+			b.frame("o.o", "    "+food),
+			b.frame("o.o", "  "+food),
+			b.frame("o-o", food),
+			b.frame("^-^", ""),
+		},
+		FPS: 4,
+	}
+}
+
+// NewAnimationSet draws a full set of animations for a body
+func NewAnimationSet(b Body) AnimationSet {
+	return AnimationSet{
+		Happy: b.animation("Happy", 2, "^.^", "^o^"),
+		Idle:  b.animation("Idle", 1, "^.^", "^-^", "^.^", "-.-", "^.^"),
+		Sad:   b.animation("Sad", 2, "T.T", "u.u"),
+		Sick:  b.animation("Sick", 2, "@.@", "@-@"),
+		Hungry: Animation{
+			Name:   "Hungry",
+			Frames: []string{b.frame("o.o", ""), b.frame("o.o", " ?")},
+			FPS:    2,
+		},
+		Sleepy: Animation{
+			Name:   "Sleepy",
+			Frames: []string{b.frame("-.-", " z"), b.frame("-.-", " Z")},
+			FPS:    2,
+		},
+		Dead: b.animation("Dead", 1, "x.x"),
+		Playing: Animation{
+			Name: "Playing",
+			Frames: []string{
+				b.frame("^.^", "  ◯"),
+				b.frame("^o^", " ◯"),
+				b.frame("^.^", "◯"),
+			},
+			FPS: 3,
+		},
+		Eating: b.eating(eatingPlaceholder),
+	}
+}
@@ -0,0 +1,70 @@
+package ascii
+
+// Animation sets of the slime characters
+var (
+	Droplet = NewAnimationSet(Body{
+		Top:    "    ,",
+		Face:   "  ( %s )",
+		Bottom: "   `~~'",
+	})
+
+	Slimeling = NewAnimationSet(Body{
+		Top:    "  .---.",
+		Face:   " ( %s )",
+		Bottom: "(_______)",
+	})
+
+	Goo = NewAnimationSet(Body{
+		Top:    "  .~~~.",
+		Face:   " ( %s )",
+		Bottom: "(_.~.~._)",
+	})
+
+	Jelly = NewAnimationSet(Body{
+		Top:    "   .-.",
+		Face:   " (( %s ))",
+		Bottom: "(_________)",
+	})
+
+	Sludge = NewAnimationSet(Body{
+		Top:    "  .~~~.",
+		Face:   " ( %s )",
+		Bottom: "(_~_~_~_)",
+	})
+
+	Crystal = NewAnimationSet(Body{
+		Top:    `    /\`,
+		Face:   " <( %s )>",
+		Bottom: `  \______/`,
+	})
+
+	Slime = NewAnimationSet(Body{
+		Top:    "  .----.",
+		Face:   " (  %s  )",
+		Bottom: "(_________)",
+	})
+
+	Ooze = NewAnimationSet(Body{
+		Top:    "  .-~~-.",
+		Face:   " (  %s  )~",
+		Bottom: "(__.--.___)",
+	})
+
+	Blob = NewAnimationSet(Body{
+		Top:    "  .------.",
+		Face:   " (   %s   )",
+		Bottom: "(           )\n '-----------'",
+	})
+
+	RoyalSlime = NewAnimationSet(Body{
+		Top:    "   _www_",
+		Face:   " (  %s  )",
+		Bottom: "(_________)",
+	})
+
+	Muck = NewAnimationSet(Body{
+		Top:    "  .~~~~.",
+		Face:   " (  %s  )",
+		Bottom: "(_~_~_~_~_)",
+	})
+)
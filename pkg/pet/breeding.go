@@ -0,0 +1,81 @@
+package pet
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// BreedCooldown is how long a pet rests after having a baby before it can
+// have another
+const BreedCooldown = 7 * 24 * time.Hour
+
+// CanBreedWith returns why the pet can't have a baby with mate at the given
+// time, or nil if it can. Both pets have to be grown up and healthy.
+func (p *Pet) CanBreedWith(mate *Pet, t time.Time) error {
+	if p.ID != 0 && p.ID == mate.ID {
+		return fmt.Errorf("%s can't have a baby on its own", p.Name)
+	}
+
+	for _, partner := range []*Pet{p, mate} {
+		switch stage := partner.LifeStageAt(t); {
+		case partner.IsDead():
+			return fmt.Errorf("%s is no longer with us", partner.Name)
+		case stage != StageAdult && stage != StageSenior:
+			return fmt.Errorf("%s isn't grown up yet", partner.Name)
+		case partner.IsSick:
+			return fmt.Errorf("%s is too sick", partner.Name)
+		case !partner.BredAt.IsZero() && t.Sub(partner.BredAt) < BreedCooldown:
+			return fmt.Errorf("%s is still resting after having a baby", partner.Name)
+		}
+	}
+
+	return nil
+}
+
+// Breed has the pet have a baby with mate. The baby is born at the current
+// time of the simulator's clock, belongs to parent and inherits its species
+// and genes from both pets, rolled with the simulator's random source.
+func (s *Simulator) Breed(p *Pet, mate *Pet, name string, parent *Parent) *Pet {
+	t := s.clock.Now()
+
+	species := p.SpeciesID
+	if s.rng.Intn(2) == 0 {
+		species = mate.SpeciesID
+	}
+	if s.rng.Float64() < speciesMutation {
+		species = speciesList[s.rng.Intn(len(speciesList))].ID
+	}
+
+	baby := NewPet(name, species, t, parent)
+	baby.Genes = Inherit(s.rng, p.Genes, mate.Genes)
+	baby.Happiness = 80
+
+	p.RecordBaby(mate, name, t)
+
+	return baby
+}
+
+// RecordBaby records that the pet had the named baby with mate at the given
+// time. The pet rests afterwards, even if the baby stays with the owner of
+// mate.
+func (p *Pet) RecordBaby(mate *Pet, name string, t time.Time) {
+	p.BredAt = t
+	p.LastAction = t
+
+	p.record(HadBaby{EventInfo: p.info(t), Mate: mate.Name, Baby: name})
+}
+
+// BabyName makes up a name for a baby from the names of its parents
+func BabyName(a, b string) string {
+	ra, rb := []rune(a), []rune(b)
+	name := []rune(strings.ToLower(string(ra[:(len(ra)+1)/2]) + string(rb[len(rb)/2:])))
+	if len(name) == 0 {
+		return "Baby"
+	}
+
+	name[0] = unicode.ToUpper(name[0])
+
+	return string(name)
+}
@@ -0,0 +1,56 @@
+package pet
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestBreedWithSameSeedGivesSameBaby(t *testing.T) {
+	at := birth.Add(100 * 24 * time.Hour)
+
+	breed := func() *Pet {
+		sim := NewSimulator(fixedClock(at), rand.New(rand.NewSource(3)))
+
+		mom, dad := newTestPet(at), newTestPet(at)
+		mom.Genes = Genes{Color: colors[0].ID, Traits: []string{traits[0].ID}, Appetite: MaxGene}
+		dad.Genes = Genes{Color: colors[1].ID, Traits: []string{traits[1].ID}, Appetite: MinGene}
+
+		baby := sim.Breed(mom, dad, "Kit", mom.Parent)
+
+		if !mom.BredAt.Equal(at) {
+			t.Errorf("bred at %v, want %v", mom.BredAt, at)
+		}
+		if !baby.BirthDate.Equal(at) {
+			t.Errorf("born at %v, want %v", baby.BirthDate, at)
+		}
+
+		return baby
+	}
+
+	first, second := breed(), breed()
+	if !reflect.DeepEqual(first.Genes, second.Genes) || first.SpeciesID != second.SpeciesID {
+		t.Errorf("babies differ: %+v %s and %+v %s", first.Genes, first.SpeciesID, second.Genes, second.SpeciesID)
+	}
+}
+
+func TestAdoptWithSameSeedGivesSameGenes(t *testing.T) {
+	at := birth.Add(time.Hour)
+
+	adopt := func() *Pet {
+		sim := NewSimulator(fixedClock(at), rand.New(rand.NewSource(5)))
+		return sim.Adopt("Rex", DefaultSpecies, NewParent(1, "alice"))
+	}
+
+	first, second := adopt(), adopt()
+	if !reflect.DeepEqual(first, second) {
+		t.Errorf("adopted pets differ: %+v and %+v", first, second)
+	}
+	if !first.BirthDate.Equal(at) || !first.LastAction.Equal(at) {
+		t.Errorf("born at %v with last action at %v, want %v", first.BirthDate, first.LastAction, at)
+	}
+	if first.Genes.Color == "" {
+		t.Errorf("adopted pet has no color")
+	}
+}
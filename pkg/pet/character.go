@@ -0,0 +1,167 @@
+package pet
+
+import "github.com/kirkegaard/terminal-pet/pkg/pet/ascii"
+
+// Evolution forms. A pet takes a new form every time it reaches a new life
+// stage, chosen by how well it was cared for during the previous one. Every
+// species has its own character for each form.
+const (
+	FormBaby            = "baby"
+	FormChild           = "child"
+	FormNeglectedChild  = "neglected_child"
+	FormTeen            = "teen"
+	FormNeglectedTeen   = "neglected_teen"
+	FormExemplaryAdult  = "exemplary_adult"
+	FormAdult           = "adult"
+	FormUnrulyAdult     = "unruly_adult"
+	FormOverweightAdult = "overweight_adult"
+	FormSenior          = "senior"
+	FormNeglectedSenior = "neglected_senior"
+)
+
+// formStages maps every form to the life stage it belongs to
+var formStages = map[string]string{
+	FormBaby:            StageBaby,
+	FormChild:           StageChild,
+	FormNeglectedChild:  StageChild,
+	FormTeen:            StageTeen,
+	FormNeglectedTeen:   StageTeen,
+	FormExemplaryAdult:  StageAdult,
+	FormAdult:           StageAdult,
+	FormUnrulyAdult:     StageAdult,
+	FormOverweightAdult: StageAdult,
+	FormSenior:          StageSenior,
+	FormNeglectedSenior: StageSenior,
+}
+
+// stageForms maps every life stage to the form pets take when nothing
+// about their care stands out
+var stageForms = map[string]string{
+	StageBaby:   FormBaby,
+	StageChild:  FormChild,
+	StageTeen:   FormTeen,
+	StageAdult:  FormAdult,
+	StageSenior: FormSenior,
+}
+
+// Character is the form a pet of a species takes during a life stage.
+// Besides looking different, characters get hungry, bored and poop at
+// slightly different rates.
+type Character struct {
+	ID          string
+	Name        string
+	Description string
+	Animations  ascii.AnimationSet
+
+	// Multipliers for the species' rates, 1 leaves a rate unchanged
+	HungerRate    float64
+	HappinessRate float64
+	PoopRate      float64
+
+	// Filled in when the species is registered
+	Species string
+	Form    string
+	Stage   string
+}
+
+// characters indexes the characters of every species by ID
+var characters = map[string]*Character{}
+
+// CharacterByID returns the character with the given ID
+func CharacterByID(id string) (*Character, bool) {
+	c, ok := characters[id]
+	return c, ok
+}
+
+// Character returns the character the pet currently is. Pets without a
+// known character look like their species' ordinary character for the life
+// stage they are in, so they don't evolve into the stage they are already in.
+func (p *Pet) Character() *Character {
+	if c, ok := characters[p.CharacterID]; ok {
+		return c
+	}
+
+	at := p.SimulatedAt
+	if at.IsZero() {
+		at = p.BirthDate
+	}
+
+	return p.Species().Characters[stageForms[p.LifeStageAt(at)]]
+}
+
+// Animations returns the animation set of the pet's character
+func (p *Pet) Animations() ascii.AnimationSet {
+	return p.Character().Animations
+}
+
+// scale applies the character's multipliers to the given rates
+func (c *Character) scale(r Rates) Rates {
+	r.Hunger *= c.HungerRate
+	r.SleepHunger *= c.HungerRate
+	r.HappinessLoss *= c.HappinessRate
+	r.PoopChance *= c.PoopRate
+
+	return r
+}
+
+// AverageHunger returns the average hunger of the pet during its current
+// life stage
+func (p *Pet) AverageHunger() int {
+	if p.CareSteps == 0 {
+		return p.Hunger
+	}
+
+	return p.HungerTotal / p.CareSteps
+}
+
+// wellCaredFor reports whether the pet was kept fed and its calls for
+// attention were answered during its current life stage
+func (p *Pet) wellCaredFor() bool {
+	return p.AverageHunger() < 50 && p.MissedCalls <= 2
+}
+
+// chooseForm picks the form the pet takes when it reaches the given life
+// stage, based on how it was cared for in the stage it leaves
+func (p *Pet) chooseForm(stage string) string {
+	cared := p.wellCaredFor()
+
+	switch stage {
+	case StageBaby:
+		return FormBaby
+	case StageChild:
+		if cared {
+			return FormChild
+		}
+		return FormNeglectedChild
+	case StageTeen:
+		if cared {
+			return FormTeen
+		}
+		return FormNeglectedTeen
+	case StageAdult:
+		switch {
+		case p.Weight > 75:
+			return FormOverweightAdult
+		case cared && p.Discipline >= 75:
+			return FormExemplaryAdult
+		case p.Discipline >= 40:
+			return FormAdult
+		default:
+			return FormUnrulyAdult
+		}
+	default:
+		if cared {
+			return FormSenior
+		}
+		return FormNeglectedSenior
+	}
+}
+
+// evolve turns the pet into its species' character for the given life stage
+// and starts tracking its care for the new stage from scratch
+func (p *Pet) evolve(stage string) {
+	p.CharacterID = p.Species().Characters[p.chooseForm(stage)].ID
+	p.MissedCalls = 0
+	p.CareSteps = 0
+	p.HungerTotal = 0
+}
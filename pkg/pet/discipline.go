@@ -0,0 +1,94 @@
+package pet
+
+import (
+	"time"
+)
+
+// Kinds of misbehaviour the player can respond to by scolding
+const (
+	// MisbehaviorFalseCall is an attention call without anything being wrong
+	MisbehaviorFalseCall = "false_call"
+	// MisbehaviorRefusal is refusing food or play
+	MisbehaviorRefusal = "refusal"
+)
+
+// MisbehaviorTimeout is how long the player has to respond to misbehaviour
+// before it counts as a missed call
+const MisbehaviorTimeout = 15 * time.Minute
+
+// maxRefusalChance is the chance a pet without any discipline refuses food
+// or play
+const maxRefusalChance = 0.3
+
+// IsMisbehaving reports whether the pet is waiting to be disciplined
+func (p *Pet) IsMisbehaving() bool {
+	return p.Misbehavior != ""
+}
+
+// misbehave starts a misbehaviour the player can respond to
+func (p *Pet) misbehave(kind string, t time.Time) {
+	p.Misbehavior = kind
+	p.MisbehavedAt = t
+}
+
+// stopMisbehaving clears the current misbehaviour
+func (p *Pet) stopMisbehaving() {
+	p.Misbehavior = ""
+	p.MisbehavedAt = time.Time{}
+}
+
+// refusalChance returns the chance the pet refuses food or play. Fully
+// disciplined pets always obey.
+func (p *Pet) refusalChance() float64 {
+	return maxRefusalChance * float64(100-clamp(p.Discipline, 0, 100)) / 100
+}
+
+// Refuses rolls the simulator's random source for whether the pet refuses an
+// action, and starts a refusal misbehaviour if it does
+func (s *Simulator) Refuses(p *Pet) bool {
+	if s.rng.Float64() >= p.refusalChance() {
+		return false
+	}
+
+	p.misbehave(MisbehaviorRefusal, s.clock.Now())
+
+	return true
+}
+
+// Scold disciplines the pet at the given time. It reports whether the pet
+// deserved it; scolding a pet that did nothing wrong only makes it sad.
+func (p *Pet) Scold(t time.Time) bool {
+	p.LastAction = t
+
+	if !p.IsMisbehaving() {
+		p.Happiness -= 15
+		p.clampStats()
+		return false
+	}
+
+	p.stopMisbehaving()
+	p.Discipline += 25
+	p.Happiness -= 5
+	p.clampStats()
+
+	return true
+}
+
+// Praise rewards the pet at the given time. It reports whether the pet
+// deserved it; praising misbehaviour teaches the pet it can get away with it.
+func (p *Pet) Praise(t time.Time) bool {
+	p.LastAction = t
+
+	if p.IsMisbehaving() {
+		p.stopMisbehaving()
+		p.Discipline -= 10
+		p.Happiness += 5
+		p.clampStats()
+		return false
+	}
+
+	p.Happiness += 5
+	p.clampStats()
+
+	return true
+}
@@ -0,0 +1,121 @@
+package pet
+
+import (
+	"time"
+)
+
+// Event is something that happened to a pet. Pets record the events of
+// their actions and of the simulation, and whoever changed the pet takes
+// them with TakeEvents to publish them once the change is done.
+type Event interface {
+	// EventPet returns the pet the event happened to
+	EventPet() *Pet
+	// EventTime returns when the event happened
+	EventTime() time.Time
+}
+
+// EventInfo is embedded in every event
+type EventInfo struct {
+	Pet *Pet
+	At  time.Time
+}
+
+func (e EventInfo) EventPet() *Pet {
+	return e.Pet
+}
+
+func (e EventInfo) EventTime() time.Time {
+	return e.At
+}
+
+// Fed is recorded when the pet ate
+type Fed struct {
+	EventInfo
+	Food *Food
+}
+
+// Played is recorded when the pet played, Game is empty for a quick game
+// without a score
+type Played struct {
+	EventInfo
+	Game     string
+	Score    int
+	MaxScore int
+}
+
+// Cleaned is recorded when the pet's poop was cleaned up
+type Cleaned struct {
+	EventInfo
+}
+
+// Medicated is recorded when the pet was given medicine, Cured tells
+// whether it was sick
+type Medicated struct {
+	EventInfo
+	Cured bool
+}
+
+// BecameSick is recorded when the pet got sick
+type BecameSick struct {
+	EventInfo
+}
+
+// Pooped is recorded when the pet pooped
+type Pooped struct {
+	EventInfo
+}
+
+// StageChanged is recorded when the pet grew into a new life stage and
+// became a new character
+type StageChanged struct {
+	EventInfo
+	From      string
+	To        string
+	Character string
+}
+
+// Died is recorded when the pet passed away
+type Died struct {
+	EventInfo
+	Cause string
+}
+
+// Renamed is recorded when the pet got a new name
+type Renamed struct {
+	EventInfo
+	From string
+	To   string
+}
+
+// HadPlaydate is recorded when the pet played with the pet of another
+// player
+type HadPlaydate struct {
+	EventInfo
+	Friend string
+}
+
+// HadBaby is recorded when the pet had a baby with the pet of another
+// player
+type HadBaby struct {
+	EventInfo
+	Mate string
+	Baby string
+}
+
+// info returns the common part of an event that happened at the given time
+func (p *Pet) info(t time.Time) EventInfo {
+	return EventInfo{Pet: p, At: t}
+}
+
+// record keeps an event until it is taken
+func (p *Pet) record(e Event) {
+	p.events = append(p.events, e)
+}
+
+// TakeEvents returns the events recorded since they were last taken, oldest
+// first, and forgets them
+func (p *Pet) TakeEvents() []Event {
+	events := p.events
+	p.events = nil
+	return events
+}
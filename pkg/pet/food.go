@@ -1,25 +1,180 @@
 package pet
 
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// defaultFoods is the food catalog the game ships with
+//
+//go:embed foods.json
+var defaultFoods []byte
+
+// Food is something a pet can eat. Its stats are applied by Simulator.Feed.
 type Food struct {
-	Name   string
-	Weight int
-	Health int
-	Hunger int
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Emoji       string `json:"emoji"`
+	Description string `json:"description"`
+
+	// Stat changes when eaten. Hunger is how much hunger is taken away.
+	Hunger    int `json:"hunger"`
+	Health    int `json:"health"`
+	Happiness int `json:"happiness"`
+	Weight    int `json:"weight"`
+
+	// SicknessRisk is the chance from 0 to 1 that eating it makes the pet sick
+	SicknessRisk float64 `json:"sicknessRisk"`
+
+	// Treats can be fed even when the pet isn't hungry
+	Treat bool `json:"treat"`
+
+	// Price is what the food costs in the shop, in coins
+	Price int `json:"price"`
+
+	// Species IDs that enjoy or dislike the food
+	Likes    []string `json:"likes"`
+	Dislikes []string `json:"dislikes"`
+}
+
+// LikedBy reports whether the species enjoys the food
+func (f *Food) LikedBy(species string) bool {
+	return slices.Contains(f.Likes, species)
+}
+
+// DislikedBy reports whether the species dislikes the food
+func (f *Food) DislikedBy(species string) bool {
+	return slices.Contains(f.Dislikes, species)
+}
+
+// FoodCatalog is the list of foods on the menu, in menu order
+type FoodCatalog struct {
+	foods []*Food
+	byID  map[string]*Food
 }
 
-func NewFood(name string, weight int, health int, hunger int) *Food {
-	return &Food{
-		Name:   name,
-		Weight: weight,
-		Health: health,
-		Hunger: hunger,
+// ParseFoodCatalog reads a catalog from JSON of the form
+// {"foods": [{"id": "burger", "name": "Burger", ...}]}
+func ParseFoodCatalog(data []byte) (*FoodCatalog, error) {
+	var doc struct {
+		Foods []*Food `json:"foods"`
+	}
+
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("parse food catalog: %w", err)
+	}
+
+	if len(doc.Foods) == 0 {
+		return nil, fmt.Errorf("food catalog has no foods")
+	}
+
+	c := &FoodCatalog{
+		foods: doc.Foods,
+		byID:  make(map[string]*Food, len(doc.Foods)),
+	}
+
+	for _, f := range doc.Foods {
+		switch {
+		case f.ID == "" || f.ID != strings.ToLower(f.ID) || strings.ContainsAny(f.ID, " \t"):
+			return nil, fmt.Errorf("food %q must have a lowercase id without spaces", f.Name)
+		case f.Name == "":
+			return nil, fmt.Errorf("food %q has no name", f.ID)
+		case f.SicknessRisk < 0 || f.SicknessRisk > 1:
+			return nil, fmt.Errorf("food %q has a sickness risk outside 0 to 1", f.ID)
+		case f.Price < 0:
+			return nil, fmt.Errorf("food %q has a negative price", f.ID)
+		}
+
+		for _, species := range slices.Concat(f.Likes, f.Dislikes) {
+			if _, ok := SpeciesByID(species); !ok {
+				return nil, fmt.Errorf("food %q refers to unknown species %q", f.ID, species)
+			}
+		}
+
+		if _, exists := c.byID[f.ID]; exists {
+			return nil, fmt.Errorf("duplicate food %q", f.ID)
+		}
+		c.byID[f.ID] = f
 	}
+
+	return c, nil
 }
 
-func Burger() (food *Food) {
-	return NewFood("Burger", 1, 10, 30)
+// LoadFoodCatalog reads a catalog from a JSON file
+func LoadFoodCatalog(path string) (*FoodCatalog, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read food catalog: %w", err)
+	}
+
+	return ParseFoodCatalog(data)
+}
+
+// All returns every food in menu order
+func (c *FoodCatalog) All() []*Food {
+	return c.foods
+}
+
+// Get returns the food with the given ID, ignoring case
+func (c *FoodCatalog) Get(id string) (*Food, bool) {
+	f, ok := c.byID[strings.ToLower(id)]
+	return f, ok
 }
 
-func Cake() (food *Food) {
-	return NewFood("Cake", 10, 0, 20)
+// IDs returns the ID of every food in menu order
+func (c *FoodCatalog) IDs() []string {
+	ids := make([]string, len(c.foods))
+	for i, f := range c.foods {
+		ids[i] = f.ID
+	}
+
+	return ids
+}
+
+// Names returns the name of every food in menu order
+func (c *FoodCatalog) Names() []string {
+	names := make([]string, len(c.foods))
+	for i, f := range c.foods {
+		names[i] = f.Name
+	}
+
+	return names
+}
+
+// foods is the catalog in use, the default one is parsed once the species
+// it refers to are registered
+var (
+	foodsMu sync.RWMutex
+	foods   *FoodCatalog
+)
+
+func mustParseFoodCatalog(data []byte) *FoodCatalog {
+	c, err := ParseFoodCatalog(data)
+	if err != nil {
+		panic(err)
+	}
+
+	return c
+}
+
+// Foods returns the food catalog in use
+func Foods() *FoodCatalog {
+	foodsMu.RLock()
+	defer foodsMu.RUnlock()
+
+	return foods
+}
+
+// SetFoods replaces the food catalog in use, for example with one loaded
+// from GAME_FOOD_CATALOG at startup
+func SetFoods(c *FoodCatalog) {
+	foodsMu.Lock()
+	defer foodsMu.Unlock()
+
+	foods = c
 }
@@ -0,0 +1,209 @@
+package pet
+
+import (
+	"math/rand"
+	"slices"
+)
+
+// Base stats range from MinGene to MaxGene, NeutralGene leaves the rates of
+// the simulation unchanged
+const (
+	MinGene     = 1
+	MaxGene     = 10
+	NeutralGene = 5
+)
+
+// MaxTraits is the number of personality traits a pet can have
+const MaxTraits = 2
+
+// Chances of a baby getting something from neither parent
+const (
+	colorMutation   = 0.1
+	traitMutation   = 0.15
+	speciesMutation = 0.05
+)
+
+// Genes are what a pet passes on to its babies: its color, personality and
+// base stats. Pets from before breeding have neutral genes and no color.
+type Genes struct {
+	Color  string   `json:"color"`
+	Traits []string `json:"traits"`
+
+	// Base stats: how hungry the pet gets, how well it keeps its spirits up
+	// and how well it fights off sickness
+	Appetite int `json:"appetite"`
+	Spirit   int `json:"spirit"`
+	Vigor    int `json:"vigor"`
+}
+
+// Color is the color of a pet's coat
+type Color struct {
+	ID   string
+	Name string
+	Hex  string
+}
+
+var colors = []Color{
+	{ID: "white", Name: "White", Hex: "#FFFFFF"},
+	{ID: "cream", Name: "Cream", Hex: "#F3E5AB"},
+	{ID: "ginger", Name: "Ginger", Hex: "#FF9F45"},
+	{ID: "brown", Name: "Brown", Hex: "#C68642"},
+	{ID: "grey", Name: "Grey", Hex: "#A0A0A0"},
+	{ID: "pink", Name: "Pink", Hex: "#FFB6C1"},
+	{ID: "blue", Name: "Blue", Hex: "#6CA0DC"},
+	{ID: "green", Name: "Green", Hex: "#77DD77"},
+	{ID: "gold", Name: "Gold", Hex: "#FFD700"},
+}
+
+// ColorByID returns the color with the given ID
+func ColorByID(id string) (Color, bool) {
+	for _, c := range colors {
+		if c.ID == id {
+			return c, true
+		}
+	}
+
+	return Color{}, false
+}
+
+// Trait is a personality trait. Traits change the rates of the simulation a
+// little, and a pet can't have a trait and its opposite.
+type Trait struct {
+	ID          string
+	Name        string
+	Description string
+	Opposite    string
+
+	scale func(r *Rates)
+}
+
+var traits = []Trait{
+	{ID: "tidy", Name: "Tidy", Description: "Poops less often", Opposite: "messy", scale: func(r *Rates) { r.PoopChance *= 0.75 }},
+	{ID: "messy", Name: "Messy", Description: "Poops more often", Opposite: "tidy", scale: func(r *Rates) { r.PoopChance *= 1.25 }},
+	{ID: "cheerful", Name: "Cheerful", Description: "Stays happy for longer", Opposite: "moody", scale: func(r *Rates) { r.HappinessLoss *= 0.85 }},
+	{ID: "moody", Name: "Moody", Description: "Gets bored quickly", Opposite: "cheerful", scale: func(r *Rates) { r.HappinessLoss *= 1.15 }},
+	{ID: "hardy", Name: "Hardy", Description: "Rarely gets sick", Opposite: "delicate", scale: func(r *Rates) { r.SickChance *= 0.7 }},
+	{ID: "delicate", Name: "Delicate", Description: "Gets sick easily", Opposite: "hardy", scale: func(r *Rates) { r.SickChance *= 1.3 }},
+	{ID: "calm", Name: "Calm", Description: "Seldom calls for no reason", Opposite: "needy", scale: func(r *Rates) { r.FalseCall *= 0.5 }},
+	{ID: "needy", Name: "Needy", Description: "Often calls for no reason", Opposite: "calm", scale: func(r *Rates) { r.FalseCall *= 1.5 }},
+}
+
+// TraitByID returns the trait with the given ID
+func TraitByID(id string) (Trait, bool) {
+	for _, t := range traits {
+		if t.ID == id {
+			return t, true
+		}
+	}
+
+	return Trait{}, false
+}
+
+// RandomGenes returns the genes of a pet without parents. The rolls come
+// from rng.
+func RandomGenes(rng *rand.Rand) Genes {
+	g := Genes{
+		Color:    colors[rng.Intn(len(colors))].ID,
+		Appetite: randomGene(rng),
+		Spirit:   randomGene(rng),
+		Vigor:    randomGene(rng),
+	}
+	g.Traits = addTrait(g.Traits, traits[rng.Intn(len(traits))].ID)
+
+	return g
+}
+
+// randomGene returns a base stat close to neutral
+func randomGene(rng *rand.Rand) int {
+	return NeutralGene + rng.Intn(5) - 2
+}
+
+// Inherit returns the genes of a baby of pets with genes a and b. Each parent
+// passes on about half of what it has, and now and then the baby gets
+// something neither of them had. The rolls come from rng.
+func Inherit(rng *rand.Rand, a, b Genes) Genes {
+	var g Genes
+
+	g.Color = a.Color
+	if rng.Intn(2) == 0 || g.Color == "" {
+		g.Color = b.Color
+	}
+	if g.Color == "" {
+		g.Color = a.Color
+	}
+	if _, ok := ColorByID(g.Color); !ok || rng.Float64() < colorMutation {
+		g.Color = colors[rng.Intn(len(colors))].ID
+	}
+
+	for _, id := range append(slices.Clone(a.Traits), b.Traits...) {
+		if rng.Intn(2) == 0 {
+			g.Traits = addTrait(g.Traits, id)
+		}
+	}
+	if rng.Float64() < traitMutation {
+		g.Traits = addTrait(g.Traits, traits[rng.Intn(len(traits))].ID)
+	}
+
+	g.Appetite = inheritGene(rng, a.Appetite, b.Appetite)
+	g.Spirit = inheritGene(rng, a.Spirit, b.Spirit)
+	g.Vigor = inheritGene(rng, a.Vigor, b.Vigor)
+
+	return g
+}
+
+// inheritGene returns a base stat between those of the parents, give or
+// take one
+func inheritGene(rng *rand.Rand, a, b int) int {
+	gene := (neutral(a) + neutral(b) + rng.Intn(2)) / 2
+	gene += rng.Intn(3) - 1
+
+	return max(MinGene, min(gene, MaxGene))
+}
+
+// neutral returns the base stat, or NeutralGene if it was never set
+func neutral(gene int) int {
+	if gene == 0 {
+		return NeutralGene
+	}
+
+	return gene
+}
+
+// addTrait adds a known trait unless the pet already has it, its opposite
+// or as many traits as it can have
+func addTrait(ids []string, id string) []string {
+	t, ok := TraitByID(id)
+	if !ok || len(ids) >= MaxTraits || slices.Contains(ids, id) || slices.Contains(ids, t.Opposite) {
+		return ids
+	}
+
+	return append(ids, id)
+}
+
+// geneFactor turns a base stat into a rate multiplier, each point away from
+// neutral is 5%
+func geneFactor(gene int) float64 {
+	return 1 + float64(neutral(gene)-NeutralGene)*0.05
+}
+
+// scale changes rates by the pet's base stats and traits
+func (g Genes) scale(r Rates) Rates {
+	r.Hunger *= geneFactor(g.Appetite)
+	r.SleepHunger *= geneFactor(g.Appetite)
+	r.HappinessLoss /= geneFactor(g.Spirit)
+	r.SickChance /= geneFactor(g.Vigor)
+	r.Recovery *= geneFactor(g.Vigor)
+
+	for _, id := range g.Traits {
+		if t, ok := TraitByID(id); ok {
+			t.scale(&r)
+		}
+	}
+
+	return r
+}
+
+// Color returns the color of the pet, if it has one
+func (p *Pet) Color() (Color, bool) {
+	return ColorByID(p.Genes.Color)
+}
@@ -9,9 +9,10 @@ import (
 type Pet struct {
 	ID         int       `json:"id"`
 	Name       string    `json:"name"`
+	SpeciesID  string    `json:"species"`
 	BirthDate  time.Time `json:"birthDate"`
 	Parent     *Parent
-	Discipline int       // Not yet implemented
+	Discipline int       `json:"discipline"`
 	Hunger     int       `json:"hunger"`
 	Happiness  int       `json:"happiness"`
 	Health     int       `json:"health"`
@@ -21,11 +22,54 @@ type Pet struct {
 	LightsOn   bool      `json:"lightsOn"`
 	LastAction time.Time `json:"lastAction"`
 	LastVisit  time.Time `json:"lastVisit"`
+
+	// SimulatedAt is the point in time the simulation has advanced the pet to
+	SimulatedAt time.Time `json:"simulatedAt"`
+
+	// Revision counts the saves of the pet, so a save can tell whether
+	// someone else saved it since it was loaded
+	Revision int `json:"revision"`
+
+	// Discipline: the current misbehaviour waiting for a scolding and how
+	// many went unanswered during the current life stage
+	Misbehavior  string    `json:"misbehavior"`
+	MisbehavedAt time.Time `json:"misbehavedAt"`
+	MissedCalls  int       `json:"missedCalls"`
+
+	// Evolution: the character the pet currently is and the care it got
+	// during the current life stage, used to pick the next character
+	CharacterID string `json:"character"`
+	CareSteps   int    `json:"careSteps"`
+	HungerTotal int    `json:"hungerTotal"`
+
+	// Breeding: what the pet passes on to its babies and when it last had
+	// one
+	Genes  Genes     `json:"genes"`
+	BredAt time.Time `json:"bredAt"`
+
+	// LifespanStart is when the pet's lifespan started counting, if not at
+	// birth. Pets from before lifespans existed count it from then.
+	LifespanStart time.Time `json:"lifespanStart"`
+
+	// Set once the pet has died
+	DiedAt       time.Time `json:"diedAt"`
+	CauseOfDeath string    `json:"causeOfDeath"`
+	Epitaph      string    `json:"epitaph"`
+
+	// events recorded since they were last taken
+	events []Event
 }
 
-func NewPet(name string, birthday time.Time, parent *Parent) *Pet {
-	return &Pet{
+// NewPet creates a newborn pet of the given species with neutral genes.
+// Unknown species fall back to the default one.
+func NewPet(name string, species string, birthday time.Time, parent *Parent) *Pet {
+	if _, ok := SpeciesByID(species); !ok {
+		species = DefaultSpecies
+	}
+
+	p := &Pet{
 		Name:       name,
+		SpeciesID:  species,
 		BirthDate:  birthday,
 		Parent:     parent,
 		Hunger:     0,
@@ -36,9 +80,23 @@ func NewPet(name string, birthday time.Time, parent *Parent) *Pet {
 		IsSick:     false,
 		HasPooped:  false,
 		LightsOn:   true,
-		LastAction: time.Now(),
-		LastVisit:  time.Now(),
+		LastAction: birthday,
+		LastVisit:  birthday,
+
+		SimulatedAt: birthday,
 	}
+	p.CharacterID = p.Species().Characters[FormBaby].ID
+
+	return p
+}
+
+// Adopt creates a pet without parents, born at the time of the simulator's
+// clock with genes rolled from its random source
+func (s *Simulator) Adopt(name string, species string, parent *Parent) *Pet {
+	p := NewPet(name, species, s.clock.Now(), parent)
+	p.Genes = RandomGenes(s.rng)
+
+	return p
 }
 
 func (p *Pet) String() string {
@@ -87,16 +145,23 @@ const (
 	StageSenior = "Senior"
 )
 
-func (p *Pet) Age() int {
-	return int(time.Since(p.BirthDate).Hours())
+// AgeAt returns the age of the pet in hours at the given time
+func (p *Pet) AgeAt(t time.Time) int {
+	return int(t.Sub(p.BirthDate).Hours())
 }
 
-func (p *Pet) AgeInYears() int {
-	return int(time.Since(p.BirthDate).Hours() / 24 / 15)
+func (p *Pet) ageInYearsAt(t time.Time) int {
+	return yearsBetween(p.BirthDate, t)
 }
 
-func (p *Pet) LifeStage() string {
-	ageInYears := p.AgeInYears()
+// yearsBetween returns the number of whole pet years between two times
+func yearsBetween(from, to time.Time) int {
+	return int(to.Sub(from).Hours() / 24 / 15)
+}
+
+// LifeStageAt returns the life stage the pet is in at the given time
+func (p *Pet) LifeStageAt(t time.Time) string {
+	ageInYears := p.ageInYearsAt(t)
 
 	switch {
 	case ageInYears < 1:
@@ -112,29 +177,138 @@ func (p *Pet) LifeStage() string {
 	}
 }
 
-func (p *Pet) IsAdult() bool {
-	stage := p.LifeStage()
+// IsAdultAt reports whether the pet is grown up at the given time
+func (p *Pet) IsAdultAt(t time.Time) bool {
+	stage := p.LifeStageAt(t)
 	return stage == StageAdult || stage == StageSenior
 }
 
-func (p *Pet) IsSenior() bool {
-	return p.LifeStage() == StageSenior
+// IsSeniorAt reports whether the pet is a senior at the given time
+func (p *Pet) IsSeniorAt(t time.Time) bool {
+	return p.LifeStageAt(t) == StageSenior
 }
 
 func (p *Pet) IsDead() bool {
 	return p.Health <= 0
 }
 
-func (p *Pet) Feed(food *Food) {
-	p.LastAction = time.Now()
+// Causes of death
+const (
+	CauseStarvation = "starvation"
+	CauseSickness   = "sickness"
+	CauseNeglect    = "neglect"
+	CauseObesity    = "obesity"
+	CauseOldAge     = "old age"
+)
+
+// HasOutlived reports whether the pet has reached the end of its species'
+// lifespan at the given time
+func (p *Pet) HasOutlived(t time.Time) bool {
+	start := p.BirthDate
+	if p.LifespanStart.After(start) {
+		start = p.LifespanStart
+	}
+
+	return yearsBetween(start, t) >= p.Species().Lifespan
+}
+
+// causeOfDeath works out what killed the pet at the given time from its age
+// and current stats
+func (p *Pet) causeOfDeath(t time.Time) string {
+	switch {
+	case p.HasOutlived(t):
+		return CauseOldAge
+	case p.Weight > 100:
+		return CauseObesity
+	case p.Hunger > 90:
+		return CauseStarvation
+	case p.IsSick:
+		return CauseSickness
+	default:
+		return CauseNeglect
+	}
+}
+
+// MarkDead records the time and cause of death the first time the pet is
+// found dead. It reports whether the pet just died.
+func (p *Pet) MarkDead(t time.Time) bool {
+	if !p.IsDead() || !p.DiedAt.IsZero() {
+		return false
+	}
+
+	p.DiedAt = t
+	p.CauseOfDeath = p.causeOfDeath(t)
+
+	p.record(Died{EventInfo: p.info(t), Cause: p.CauseOfDeath})
+
+	return true
+}
+
+// Revive brings a dead pet back, only used for debugging
+func (p *Pet) Revive(health int) {
+	p.Health = health
+	p.DiedAt = time.Time{}
+	p.CauseOfDeath = ""
+}
+
+// Lifetime returns how long the pet has lived by the given time, or lived
+// for if it is dead
+func (p *Pet) Lifetime(now time.Time) time.Duration {
+	if !p.DiedAt.IsZero() {
+		return p.DiedAt.Sub(p.BirthDate)
+	}
+
+	return now.Sub(p.BirthDate)
+}
+
+// FinalLifeStage returns the life stage the pet was in when it died, or is
+// in at the given time if it is alive
+func (p *Pet) FinalLifeStage(now time.Time) string {
+	if !p.DiedAt.IsZero() {
+		return p.LifeStageAt(p.DiedAt)
+	}
+
+	return p.LifeStageAt(now)
+}
+
+// Feed feeds the pet and reports whether it ate. Poorly disciplined pets
+// sometimes refuse, unless they are starving. Pets enjoy the foods their
+// species likes and get less out of the ones it dislikes. Risky food makes
+// the pet sick on a roll of the simulator's random source.
+func (s *Simulator) Feed(p *Pet, food *Food) bool {
+	p.LastAction = s.clock.Now()
+
+	if p.Hunger <= 90 && s.Refuses(p) {
+		return false
+	}
+
+	defer p.clampStats()
+
+	hunger := food.Hunger
+	p.Happiness += food.Happiness
+
+	switch {
+	case food.LikedBy(p.SpeciesID):
+		p.Happiness += 10
+	case food.DislikedBy(p.SpeciesID):
+		p.Happiness -= 10
+		hunger /= 2
+	}
+
+	p.record(Fed{EventInfo: p.info(p.LastAction), Food: food})
+
+	if food.SicknessRisk > 0 && s.rng.Float64() < food.SicknessRisk && !p.IsSick {
+		p.IsSick = true
+		p.record(BecameSick{EventInfo: p.info(p.LastAction)})
+	}
 
 	if p.Hunger <= 0 {
 		p.Health -= 2
 		p.Weight += food.Weight
-		return
+		return true
 	}
 
-	p.Hunger -= food.Hunger
+	p.Hunger -= hunger
 	if p.Hunger < 0 {
 		p.Hunger = 0
 	}
@@ -145,10 +319,18 @@ func (p *Pet) Feed(food *Food) {
 	if p.Health > 100 {
 		p.Health = 100
 	}
+
+	return true
 }
 
-func (p *Pet) Play() {
-	p.LastAction = time.Now()
+// Play plays with the pet and reports whether it played along. Poorly
+// disciplined pets sometimes refuse.
+func (s *Simulator) Play(p *Pet) bool {
+	p.LastAction = s.clock.Now()
+
+	if s.Refuses(p) {
+		return false
+	}
 
 	p.Happiness += 10
 	if p.Happiness > 100 {
@@ -161,10 +343,73 @@ func (p *Pet) Play() {
 	}
 
 	p.Weight -= 1
+
+	p.record(Played{EventInfo: p.info(p.LastAction)})
+
+	return true
+}
+
+// Cheer makes the pet happier, e.g. from playing with a favourite toy
+func (p *Pet) Cheer(amount int) {
+	p.Happiness += amount
+	if p.Happiness > 100 {
+		p.Happiness = 100
+	}
+}
+
+// MinGameWeight is the weight below which games stop slimming a pet down
+const MinGameWeight = 10
+
+// GameResult is the outcome of a minigame and its effect on the pet
+type GameResult struct {
+	Game      string
+	Score     int
+	MaxScore  int
+	Happiness int
+	Weight    int
+}
+
+// PlayGame applies the effects of a finished minigame
+func (s *Simulator) PlayGame(p *Pet, result GameResult) {
+	p.LastAction = s.clock.Now()
+
+	p.Happiness += result.Happiness
+	if p.Happiness > 100 {
+		p.Happiness = 100
+	} else if p.Happiness < 0 {
+		p.Happiness = 0
+	}
+
+	// Games burn off weight, but never make a pet too thin
+	if result.Weight < 0 {
+		p.Weight = max(p.Weight+result.Weight, min(p.Weight, MinGameWeight))
+	} else {
+		p.Weight += result.Weight
+	}
+
+	p.record(Played{
+		EventInfo: p.info(p.LastAction),
+		Game:      result.Game,
+		Score:     result.Score,
+		MaxScore:  result.MaxScore,
+	})
 }
 
-func (p *Pet) GiveMedicine() {
-	p.LastAction = time.Now()
+// PlayWith cheers the pet up after a playdate with the named pet of another
+// player
+func (s *Simulator) PlayWith(p *Pet, friend string, happiness int) {
+	p.LastAction = s.clock.Now()
+
+	p.Happiness = min(p.Happiness+happiness, 100)
+
+	p.record(HadPlaydate{EventInfo: p.info(p.LastAction), Friend: friend})
+}
+
+// GiveMedicine cures a sick pet, medicine makes a healthy pet feel worse
+func (s *Simulator) GiveMedicine(p *Pet) {
+	p.LastAction = s.clock.Now()
+
+	p.record(Medicated{EventInfo: p.info(p.LastAction), Cured: p.IsSick})
 
 	if p.IsSick {
 		p.IsSick = false
@@ -190,10 +435,13 @@ func (p *Pet) GiveMedicine() {
 	}
 }
 
-func (p *Pet) Clean() {
-	p.LastAction = time.Now()
+// Clean cleans up after the pet
+func (s *Simulator) Clean(p *Pet) {
+	p.LastAction = s.clock.Now()
 
 	if p.HasPooped {
+		p.record(Cleaned{EventInfo: p.info(p.LastAction)})
+
 		p.HasPooped = false
 		p.Happiness += 5
 		if p.Happiness > 100 {
@@ -207,7 +455,20 @@ func (p *Pet) Clean() {
 	}
 }
 
-func (p *Pet) ToggleLights() {
-	p.LastAction = time.Now()
+// Rename gives the pet a new name
+func (s *Simulator) Rename(p *Pet, name string) {
+	p.LastAction = s.clock.Now()
+
+	if name == p.Name {
+		return
+	}
+
+	p.record(Renamed{EventInfo: p.info(p.LastAction), From: p.Name, To: name})
+	p.Name = name
+}
+
+// ToggleLights turns the lights on or off
+func (s *Simulator) ToggleLights(p *Pet) {
+	p.LastAction = s.clock.Now()
 	p.LightsOn = !p.LightsOn
 }
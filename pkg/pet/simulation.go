@@ -0,0 +1,334 @@
+package pet
+
+import (
+	"math/rand"
+	"time"
+)
+
+// SimulationStep is the fixed amount of game time covered by a single
+// simulation tick. Time is always simulated in whole steps so that advancing
+// a pet in many small chunks gives the same result as one large jump.
+const SimulationStep = time.Minute
+
+const minWeight = 10
+
+// Clock provides the current time to the simulation
+type Clock interface {
+	Now() time.Time
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
+
+// SystemClock is a Clock backed by time.Now
+var SystemClock Clock = systemClock{}
+
+// Rates holds the balance values used by the simulation. All values are
+// expected occurrences per hour of game time.
+type Rates struct {
+	Hunger           float64 // Hunger gained while awake
+	HappinessLoss    float64 // Happiness lost while awake
+	StarvingDamage   float64 // Health lost while hunger is above 90
+	Recovery         float64 // Health regained while fed and healthy
+	Aging            float64 // Health lost to old age
+	SickDamage       float64 // Health lost while sick
+	SickChance       float64 // Chance of getting sick
+	PoopChance       float64 // Chance of pooping as an adult
+	PoopDamage       float64 // Health and happiness lost to uncleaned poop
+	HungryWeightLoss float64 // Weight lost while hungry
+	FatDamage        float64 // Health lost while weight is above 75
+	ObeseDamage      float64 // Health lost while weight is above 100
+	SleepRecovery    float64 // Health regained while sleeping
+	SleepHappiness   float64 // Happiness regained while sleeping
+	SleepHunger      float64 // Hunger gained while sleeping
+	FalseCall        float64 // Chance of calling for attention for no reason
+}
+
+// DefaultRates are the rates of the default species, the other species
+// adjust them to their needs
+var DefaultRates = Rates{
+	Hunger:           1.5,
+	HappinessLoss:    1.5,
+	StarvingDamage:   1,
+	Recovery:         1,
+	Aging:            0.05,
+	SickDamage:       0.5,
+	SickChance:       1.0 / 120,
+	PoopChance:       1.0 / 6,
+	PoopDamage:       0.25,
+	HungryWeightLoss: 0.1,
+	FatDamage:        0.25,
+	ObeseDamage:      0.5,
+	SleepRecovery:    1,
+	SleepHappiness:   0.5,
+	SleepHunger:      0.5,
+	FalseCall:        1.0 / 3,
+}
+
+// Simulator advances pets through time. It is the only place where stats
+// change on their own, both while the player is connected and while away.
+// Actions left to chance, like feeding, playing and breeding, roll its random
+// source and happen at the time of its clock, so a seeded simulator always
+// gives the same results.
+type Simulator struct {
+	clock Clock
+	rng   *rand.Rand
+
+	// rates overrides the rates of the pet's species when set
+	rates *Rates
+}
+
+// NewSimulator creates a simulator using the given clock and random source
+func NewSimulator(clock Clock, rng *rand.Rand) *Simulator {
+	if clock == nil {
+		clock = SystemClock
+	}
+
+	if rng == nil {
+		rng = rand.New(rand.NewSource(clock.Now().UnixNano()))
+	}
+
+	return &Simulator{
+		clock: clock,
+		rng:   rng,
+	}
+}
+
+// WithRates returns a copy of the simulator using the given rates for every
+// pet instead of the rates of its species
+func (s *Simulator) WithRates(rates Rates) *Simulator {
+	return &Simulator{
+		clock: s.clock,
+		rng:   s.rng,
+		rates: &rates,
+	}
+}
+
+// ratesFor returns the rates that apply to the pet right now
+func (s *Simulator) ratesFor(p *Pet) Rates {
+	base := p.Species().Rates
+	if s.rates != nil {
+		base = *s.rates
+	}
+
+	return p.Genes.scale(p.Character().scale(base))
+}
+
+// Now returns the current time according to the simulator's clock
+func (s *Simulator) Now() time.Time {
+	return s.clock.Now()
+}
+
+// Update advances the pet up to the current time of the clock
+func (s *Simulator) Update(p *Pet) int {
+	return s.AdvanceTo(p, s.clock.Now())
+}
+
+// Advance simulates the given duration of time passing for the pet
+func (s *Simulator) Advance(p *Pet, d time.Duration) int {
+	if p.SimulatedAt.IsZero() {
+		p.SimulatedAt = s.clock.Now()
+	}
+
+	return s.AdvanceTo(p, p.SimulatedAt.Add(d))
+}
+
+// AdvanceTo simulates the pet up to the given time and returns the number
+// of steps that were applied. Any remainder shorter than a step is carried
+// over to the next call.
+func (s *Simulator) AdvanceTo(p *Pet, t time.Time) int {
+	if p.SimulatedAt.IsZero() {
+		p.SimulatedAt = t
+		return 0
+	}
+
+	steps := 0
+	for !p.SimulatedAt.Add(SimulationStep).After(t) {
+		p.SimulatedAt = p.SimulatedAt.Add(SimulationStep)
+
+		if p.IsDead() {
+			continue
+		}
+
+		s.step(p)
+		p.MarkDead(p.SimulatedAt)
+		steps++
+	}
+
+	return steps
+}
+
+// chance rolls for an event that happens rate times per hour on average
+func (s *Simulator) chance(rate float64) bool {
+	return s.rng.Float64() < rate*SimulationStep.Hours()
+}
+
+// step applies a single simulation tick to the pet
+func (s *Simulator) step(p *Pet) {
+	defer p.clampStats()
+
+	// Pets that reach the end of their lifespan pass away peacefully
+	if p.HasOutlived(p.SimulatedAt) {
+		p.Health = 0
+		return
+	}
+
+	s.stepEvolution(p)
+
+	r := s.ratesFor(p)
+
+	s.stepDiscipline(p, r)
+
+	// Sleeping pets recover and get hungry more slowly
+	if !p.LightsOn {
+		if s.chance(r.SleepRecovery) {
+			p.Health++
+		}
+
+		if s.chance(r.SleepHappiness) {
+			p.Happiness++
+		}
+
+		if s.chance(r.SleepHunger) {
+			p.Hunger++
+		}
+
+		return
+	}
+
+	if s.chance(r.Hunger) {
+		p.Hunger++
+	}
+
+	if s.chance(r.HappinessLoss) {
+		p.Happiness--
+	}
+
+	if p.Hunger > 90 && s.chance(r.StarvingDamage) {
+		p.Health--
+	}
+
+	if p.Hunger < 80 && !p.IsSick && !p.HasPooped && s.chance(r.Recovery) {
+		p.Health++
+	}
+
+	if s.chance(r.Aging) {
+		p.Health--
+	}
+
+	// Heavier pets lose health faster
+	if p.Weight > 100 {
+		if s.chance(r.ObeseDamage) {
+			p.Health--
+		}
+	} else if p.Weight > 75 {
+		if s.chance(r.FatDamage) {
+			p.Health--
+		}
+	}
+
+	if p.Hunger > 50 && p.Weight > minWeight && s.chance(r.HungryWeightLoss) {
+		p.Weight--
+	}
+
+	if !p.IsSick {
+		// Uncleaned poop makes the pet more likely to get sick
+		sickChance := r.SickChance
+		if p.HasPooped {
+			sickChance *= 3
+		}
+
+		if s.chance(sickChance) {
+			p.IsSick = true
+			p.record(BecameSick{EventInfo: p.info(p.SimulatedAt)})
+		}
+	} else if s.chance(r.SickDamage) {
+		p.Health--
+	}
+
+	if !p.HasPooped {
+		poopChance := r.PoopChance
+
+		// Babies and children poop more often
+		switch p.LifeStageAt(p.SimulatedAt) {
+		case StageBaby:
+			poopChance *= 6
+		case StageChild:
+			poopChance *= 2
+		}
+
+		// Recently fed pets poop more
+		if p.Hunger < 30 {
+			poopChance *= 2
+		}
+
+		if s.chance(poopChance) {
+			p.HasPooped = true
+			p.record(Pooped{EventInfo: p.info(p.SimulatedAt)})
+		}
+	} else if s.chance(r.PoopDamage) {
+		p.Health--
+		p.Happiness--
+	}
+}
+
+// stepEvolution turns the pet into a new character when it reaches a new
+// life stage and keeps track of how it is cared for in the meantime
+func (s *Simulator) stepEvolution(p *Pet) {
+	stage := p.LifeStageAt(p.SimulatedAt)
+
+	if c, ok := CharacterByID(p.CharacterID); !ok || c.Stage != stage {
+		from := p.Character().Stage
+		p.evolve(stage)
+
+		if from != stage {
+			p.record(StageChanged{
+				EventInfo: p.info(p.SimulatedAt),
+				From:      from,
+				To:        stage,
+				Character: p.CharacterID,
+			})
+		}
+	}
+
+	p.CareSteps++
+	p.HungerTotal += p.Hunger
+}
+
+// stepDiscipline expires unanswered misbehaviour and makes awake pets call
+// for attention for no reason now and then
+func (s *Simulator) stepDiscipline(p *Pet, r Rates) {
+	stage := p.LifeStageAt(p.SimulatedAt)
+
+	if p.IsMisbehaving() && p.SimulatedAt.Sub(p.MisbehavedAt) >= MisbehaviorTimeout {
+		// The pet learns it can get away with it
+		p.stopMisbehaving()
+		p.MissedCalls++
+		p.Discipline -= 5
+	}
+
+	if !p.IsMisbehaving() && p.LightsOn && stage != StageBaby && s.chance(r.FalseCall) {
+		p.misbehave(MisbehaviorFalseCall, p.SimulatedAt)
+	}
+}
+
+// clampStats keeps all stats within their valid ranges
+func (p *Pet) clampStats() {
+	p.Hunger = clamp(p.Hunger, 0, 100)
+	p.Happiness = clamp(p.Happiness, 0, 100)
+	p.Health = clamp(p.Health, 0, 100)
+	p.Discipline = clamp(p.Discipline, 0, 100)
+}
+
+func clamp(value, min, max int) int {
+	if value < min {
+		return min
+	}
+	if value > max {
+		return max
+	}
+	return value
+}
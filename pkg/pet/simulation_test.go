@@ -0,0 +1,246 @@
+package pet
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// fixedClock is a clock that always returns the same time
+type fixedClock time.Time
+
+func (c fixedClock) Now() time.Time {
+	return time.Time(c)
+}
+
+var birth = time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+
+// always is a rate high enough that its event happens on every step
+const always = 600
+
+// newTestPet returns a pet with neutral genes that was simulated up to the
+// given time and has the standard character of its stage then
+func newTestPet(at time.Time) *Pet {
+	p := NewPet("Rex", DefaultSpecies, birth, NewParent(1, "alice"))
+	p.Happiness = 50
+	p.SimulatedAt = at
+	p.LastAction, p.LastVisit = at, at
+	p.CharacterID = p.Species().Characters[stageForms[p.LifeStageAt(at)]].ID
+
+	return p
+}
+
+// newTestSimulator returns a simulator with a fixed seed using only the
+// given rates
+func newTestSimulator(rates Rates) *Simulator {
+	return NewSimulator(fixedClock(birth), rand.New(rand.NewSource(1))).WithRates(rates)
+}
+
+// eventsOf returns the events of type T recorded on the pet
+func eventsOf[T Event](p *Pet) []T {
+	var found []T
+	for _, e := range p.TakeEvents() {
+		if e, ok := e.(T); ok {
+			found = append(found, e)
+		}
+	}
+
+	return found
+}
+
+func TestAdvanceToDecay(t *testing.T) {
+	start := birth.Add(100 * 24 * time.Hour)
+
+	tests := []struct {
+		name          string
+		rates         Rates
+		lightsOn      bool
+		advance       time.Duration
+		wantSteps     int
+		wantHunger    int
+		wantHappiness int
+	}{
+		{"nothing happens", Rates{}, true, 10 * time.Minute, 10, 0, 50},
+		{"hunger and sadness", Rates{Hunger: always, HappinessLoss: always}, true, 10 * time.Minute, 10, 10, 40},
+		{"partial steps carry over", Rates{Hunger: always}, true, 90 * time.Second, 1, 1, 50},
+		{"sleeping pets use the sleep rates", Rates{Hunger: always, SleepHappiness: always}, false, 5 * time.Minute, 5, 0, 55},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestPet(start)
+			p.LightsOn = tt.lightsOn
+
+			steps := newTestSimulator(tt.rates).AdvanceTo(p, start.Add(tt.advance))
+
+			if steps != tt.wantSteps {
+				t.Errorf("steps = %d, want %d", steps, tt.wantSteps)
+			}
+			if p.Hunger != tt.wantHunger {
+				t.Errorf("hunger = %d, want %d", p.Hunger, tt.wantHunger)
+			}
+			if p.Happiness != tt.wantHappiness {
+				t.Errorf("happiness = %d, want %d", p.Happiness, tt.wantHappiness)
+			}
+			if want := start.Add(time.Duration(tt.wantSteps) * SimulationStep); !p.SimulatedAt.Equal(want) {
+				t.Errorf("simulated at %v, want %v", p.SimulatedAt, want)
+			}
+		})
+	}
+}
+
+func TestAdvanceToInChunksMatchesOneJump(t *testing.T) {
+	start := birth.Add(100 * 24 * time.Hour)
+	end := start.Add(12 * time.Hour)
+
+	whole := newTestPet(start)
+	NewSimulator(fixedClock(birth), rand.New(rand.NewSource(7))).AdvanceTo(whole, end)
+
+	chunked := newTestPet(start)
+	sim := NewSimulator(fixedClock(birth), rand.New(rand.NewSource(7)))
+	for at := start; at.Before(end); at = at.Add(7*time.Minute + 30*time.Second) {
+		sim.AdvanceTo(chunked, at)
+	}
+	sim.AdvanceTo(chunked, end)
+
+	whole.events, chunked.events = nil, nil
+	if !reflect.DeepEqual(whole, chunked) {
+		t.Errorf("simulating in chunks gave %+v, want %+v", *chunked, *whole)
+	}
+}
+
+func TestAdvanceToSickness(t *testing.T) {
+	start := birth.Add(100 * 24 * time.Hour)
+
+	tests := []struct {
+		name       string
+		rates      Rates
+		sick       bool
+		wantSick   bool
+		wantHealth int
+		wantEvents int
+	}{
+		{"healthy pet stays healthy", Rates{}, false, false, 100, 0},
+		{"pet falls sick once", Rates{SickChance: always}, false, true, 100, 1},
+		{"sickness hurts", Rates{SickChance: always, SickDamage: always}, true, true, 90, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestPet(start)
+			p.IsSick = tt.sick
+
+			newTestSimulator(tt.rates).AdvanceTo(p, start.Add(10*time.Minute))
+
+			if p.IsSick != tt.wantSick {
+				t.Errorf("sick = %v, want %v", p.IsSick, tt.wantSick)
+			}
+			if p.Health != tt.wantHealth {
+				t.Errorf("health = %d, want %d", p.Health, tt.wantHealth)
+			}
+			if got := len(eventsOf[BecameSick](p)); got != tt.wantEvents {
+				t.Errorf("%d sickness events, want %d", got, tt.wantEvents)
+			}
+		})
+	}
+}
+
+func TestAdvanceToEvolution(t *testing.T) {
+	day := 24 * time.Hour
+
+	// becomes turns the pet into the character of the given form
+	becomes := func(form string) func(p *Pet) {
+		return func(p *Pet) { p.CharacterID = p.Species().Characters[form].ID }
+	}
+
+	tests := []struct {
+		name      string
+		start     time.Time
+		prepare   func(p *Pet)
+		wantForm  string
+		wantStage string
+		wantFrom  string
+	}{
+		{"cared for baby grows into a child", birth.Add(15*day - 5*time.Minute), func(p *Pet) {}, FormChild, StageChild, StageBaby},
+		{"hungry baby grows into a neglected child", birth.Add(15*day - 5*time.Minute), func(p *Pet) { p.Hunger = 80 }, FormNeglectedChild, StageChild, StageBaby},
+		{"heavy teen grows into an overweight adult", birth.Add(90*day - 5*time.Minute), func(p *Pet) { becomes(FormTeen)(p); p.Weight = 90 }, FormOverweightAdult, StageAdult, StageTeen},
+		{"no change within a stage", birth.Add(50 * day), becomes(FormTeen), FormTeen, StageTeen, ""},
+		{"unknown character takes the stage's form quietly", birth.Add(50 * day), func(p *Pet) { p.CharacterID = "retired" }, FormTeen, StageTeen, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestPet(tt.start)
+			tt.prepare(p)
+
+			newTestSimulator(Rates{}).AdvanceTo(p, tt.start.Add(10*time.Minute))
+
+			c := p.Character()
+			if c.Form != tt.wantForm || c.Stage != tt.wantStage {
+				t.Errorf("form %s of stage %s, want %s of stage %s", c.Form, c.Stage, tt.wantForm, tt.wantStage)
+			}
+			if c.Species != p.SpeciesID {
+				t.Errorf("character of species %s, want %s", c.Species, p.SpeciesID)
+			}
+
+			changes := eventsOf[StageChanged](p)
+			if tt.wantFrom == "" {
+				if len(changes) > 0 {
+					t.Errorf("unexpected stage changes %+v", changes)
+				}
+				return
+			}
+
+			if len(changes) != 1 {
+				t.Fatalf("%d stage changes, want 1", len(changes))
+			}
+			if changes[0].From != tt.wantFrom || changes[0].To != tt.wantStage || changes[0].Character != p.CharacterID {
+				t.Errorf("changed from %s to %s as %s, want %s to %s as %s", changes[0].From, changes[0].To, changes[0].Character, tt.wantFrom, tt.wantStage, p.CharacterID)
+			}
+		})
+	}
+}
+
+func TestAdvanceToDeath(t *testing.T) {
+	start := birth.Add(100 * 24 * time.Hour)
+	lifespan, _ := SpeciesByID(DefaultSpecies)
+
+	tests := []struct {
+		name      string
+		start     time.Time
+		rates     Rates
+		prepare   func(p *Pet)
+		wantCause string
+	}{
+		{"starvation", start, Rates{StarvingDamage: always}, func(p *Pet) { p.Hunger, p.Health = 100, 3 }, CauseStarvation},
+		{"sickness", start, Rates{SickDamage: always}, func(p *Pet) { p.IsSick, p.Health = true, 3 }, CauseSickness},
+		{"obesity", start, Rates{ObeseDamage: always}, func(p *Pet) { p.Weight, p.Health = 120, 3 }, CauseObesity},
+		{"old age", birth.Add(time.Duration(lifespan.Lifespan) * 15 * 24 * time.Hour), Rates{}, func(p *Pet) {}, CauseOldAge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := newTestPet(tt.start)
+			tt.prepare(p)
+
+			steps := newTestSimulator(tt.rates).AdvanceTo(p, tt.start.Add(time.Hour))
+
+			if !p.IsDead() {
+				t.Fatalf("pet is alive with health %d", p.Health)
+			}
+			if p.CauseOfDeath != tt.wantCause {
+				t.Errorf("cause = %q, want %q", p.CauseOfDeath, tt.wantCause)
+			}
+			if want := tt.start.Add(time.Duration(steps) * SimulationStep); !p.DiedAt.Equal(want) {
+				t.Errorf("died at %v, want %v", p.DiedAt, want)
+			}
+			if steps >= 60 {
+				t.Errorf("%d steps were applied after death", steps)
+			}
+			if got := len(eventsOf[Died](p)); got != 1 {
+				t.Errorf("%d death events, want 1", got)
+			}
+		})
+	}
+}
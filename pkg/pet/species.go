@@ -0,0 +1,230 @@
+package pet
+
+import (
+	"fmt"
+
+	"github.com/kirkegaard/terminal-pet/pkg/pet/ascii"
+)
+
+// Species IDs
+const (
+	SpeciesCat    = "cat"
+	SpeciesDog    = "dog"
+	SpeciesSlime  = "slime"
+	SpeciesDragon = "dragon"
+)
+
+// DefaultSpecies is the species of pets adopted without choosing one, and of
+// pets from before there was a choice
+const DefaultSpecies = SpeciesCat
+
+// Species defines what kind of creature a pet is: how it looks as it grows
+// up, how quickly its needs build up and how long it lives. What it likes to
+// eat is part of the food catalog.
+type Species struct {
+	ID          string
+	Name        string
+	Description string
+
+	// Rates are the base rates of the simulation, adjusted by the pet's
+	// character
+	Rates Rates
+
+	// Lifespan is the age in years at which the pet dies of old age
+	Lifespan int
+
+	// Characters holds the character for every evolution form
+	Characters map[string]*Character
+}
+
+// FavoriteFoods returns the names of the foods in the catalog the species
+// enjoys
+func (s *Species) FavoriteFoods() []string {
+	var names []string
+	for _, f := range Foods().All() {
+		if f.LikedBy(s.ID) {
+			names = append(names, f.Name)
+		}
+	}
+
+	return names
+}
+
+// DislikedFoods returns the names of the foods in the catalog the species
+// dislikes
+func (s *Species) DislikedFoods() []string {
+	var names []string
+	for _, f := range Foods().All() {
+		if f.DislikedBy(s.ID) {
+			names = append(names, f.Name)
+		}
+	}
+
+	return names
+}
+
+// speciesList holds every species in the order they are offered at adoption
+var speciesList []*Species
+
+var speciesByID = map[string]*Species{}
+
+// AllSpecies returns every species in the order they are offered at adoption
+func AllSpecies() []*Species {
+	return speciesList
+}
+
+// SpeciesByID returns the species with the given ID
+func SpeciesByID(id string) (*Species, bool) {
+	s, ok := speciesByID[id]
+	return s, ok
+}
+
+// Species returns the species of the pet
+func (p *Pet) Species() *Species {
+	if s, ok := speciesByID[p.SpeciesID]; ok {
+		return s
+	}
+
+	return speciesByID[DefaultSpecies]
+}
+
+// registerSpecies adds a species and its characters to the registries. It
+// panics if the species is missing a character for any form.
+func registerSpecies(s *Species) {
+	for form, stage := range formStages {
+		c, ok := s.Characters[form]
+		if !ok {
+			panic(fmt.Sprintf("species %q has no character for form %q", s.ID, form))
+		}
+
+		c.Species = s.ID
+		c.Form = form
+		c.Stage = stage
+		characters[c.ID] = c
+	}
+
+	speciesList = append(speciesList, s)
+	speciesByID[s.ID] = s
+}
+
+// ratesWith returns the default rates changed by fn
+func ratesWith(fn func(r *Rates)) Rates {
+	r := DefaultRates
+	fn(&r)
+	return r
+}
+
+// character creates a character with the given rate multipliers
+func character(id, name, description string, animations ascii.AnimationSet, hunger, happiness, poop float64) *Character {
+	return &Character{
+		ID:            id,
+		Name:          name,
+		Description:   description,
+		Animations:    animations,
+		HungerRate:    hunger,
+		HappinessRate: happiness,
+		PoopRate:      poop,
+	}
+}
+
+func init() {
+	registerSpecies(&Species{
+		ID:          SpeciesCat,
+		Name:        "Cat",
+		Description: "The classic. Independent, but doesn't like to be ignored",
+		Rates:       DefaultRates,
+		Lifespan:    20,
+		Characters: map[string]*Character{
+			FormBaby:            character("puffball", "Puffball", "A fluffy newborn that needs a lot of looking after", ascii.Puffball, 1, 1, 1),
+			FormChild:           character("kitten", "Kitten", "A well fed and attended to youngster", ascii.Kitten, 1, 0.9, 1),
+			FormNeglectedChild:  character("scamp", "Scamp", "A neglected youngster that gets bored quickly", ascii.Scamp, 1.1, 1.2, 1),
+			FormTeen:            character("tabby", "Tabby", "A content teenager", ascii.Tabby, 1, 0.9, 0.9),
+			FormNeglectedTeen:   character("alleycat", "Alley Cat", "A scruffy teenager that fends for itself", ascii.AlleyCat, 1.2, 1.1, 1.1),
+			FormExemplaryAdult:  character("lion", "Lion", "The proud result of perfect care and discipline", ascii.Lion, 0.9, 0.8, 0.9),
+			FormAdult:           character("housecat", "House Cat", "A well behaved, ordinary adult", ascii.HouseCat, 1, 1, 1),
+			FormUnrulyAdult:     character("tomcat", "Tom Cat", "An unruly adult that never learned any manners", ascii.TomCat, 1.1, 1.2, 1.1),
+			FormOverweightAdult: character("chonk", "Chonk", "An adult that was fed a few too many treats", ascii.Chonk, 1.3, 0.9, 1.2),
+			FormSenior:          character("sage", "Sage", "A calm elder that had a good life", ascii.Sage, 0.8, 0.8, 0.8),
+			FormNeglectedSenior: character("grump", "Grump", "A grumpy elder that remembers every missed meal", ascii.Grump, 1, 1.2, 1),
+		},
+	})
+
+	registerSpecies(&Species{
+		ID:          SpeciesDog,
+		Name:        "Dog",
+		Description: "Loyal and always hungry. Gets bored fast without play",
+		Rates: ratesWith(func(r *Rates) {
+			r.Hunger = 1.8
+			r.HappinessLoss = 2
+			r.PoopChance = 1.0 / 5
+		}),
+		Lifespan: 16,
+		Characters: map[string]*Character{
+			FormBaby:            character("pup", "Pup", "A tiny pup with big paws", ascii.Pup, 1, 1, 1),
+			FormChild:           character("puppy", "Puppy", "A bouncy, well looked after puppy", ascii.Puppy, 1, 0.9, 1),
+			FormNeglectedChild:  character("mutt", "Mutt", "A scrappy puppy that had to fend for itself", ascii.Mutt, 1.1, 1.2, 1),
+			FormTeen:            character("beagle", "Beagle", "A curious teenager with a good nose", ascii.Beagle, 1, 0.9, 0.9),
+			FormNeglectedTeen:   character("stray", "Stray", "A teenager that spends its days roaming", ascii.Stray, 1.2, 1.1, 1.1),
+			FormExemplaryAdult:  character("shepherd", "Shepherd", "A well trained and loyal companion", ascii.Shepherd, 0.9, 0.8, 0.9),
+			FormAdult:           character("retriever", "Retriever", "A friendly, ordinary adult", ascii.Retriever, 1, 1, 1),
+			FormUnrulyAdult:     character("hound", "Hound", "An adult that howls and never listens", ascii.Hound, 1.1, 1.2, 1.1),
+			FormOverweightAdult: character("bulldog", "Bulldog", "An adult that got a few too many scraps", ascii.Bulldog, 1.3, 0.9, 1.2),
+			FormSenior:          character("greymuzzle", "Greymuzzle", "A gentle old dog that had a good life", ascii.Greymuzzle, 0.8, 0.8, 0.8),
+			FormNeglectedSenior: character("grouch", "Grouch", "An old dog that growls at everyone", ascii.Grouch, 1, 1.2, 1),
+		},
+	})
+
+	registerSpecies(&Species{
+		ID:          SpeciesSlime,
+		Name:        "Slime",
+		Description: "Hardy and slow to get hungry, but it poops a lot",
+		Rates: ratesWith(func(r *Rates) {
+			r.Hunger = 1
+			r.HappinessLoss = 1.2
+			r.SickChance = 1.0 / 240
+			r.PoopChance = 1.0 / 4
+		}),
+		Lifespan: 30,
+		Characters: map[string]*Character{
+			FormBaby:            character("droplet", "Droplet", "A single wobbly drop", ascii.Droplet, 1, 1, 1),
+			FormChild:           character("slimeling", "Slimeling", "A happy little slime", ascii.Slimeling, 1, 0.9, 1),
+			FormNeglectedChild:  character("goo", "Goo", "A runny slime that can't keep its shape", ascii.Goo, 1.1, 1.2, 1),
+			FormTeen:            character("jelly", "Jelly", "A firm and bouncy teenager", ascii.Jelly, 1, 0.9, 0.9),
+			FormNeglectedTeen:   character("sludge", "Sludge", "A murky teenager", ascii.Sludge, 1.2, 1.1, 1.1),
+			FormExemplaryAdult:  character("crystal", "Crystal Slime", "A slime so well kept it turned to crystal", ascii.Crystal, 0.9, 0.8, 0.9),
+			FormAdult:           character("slime", "Slime", "An ordinary, gooey adult", ascii.Slime, 1, 1, 1),
+			FormUnrulyAdult:     character("ooze", "Ooze", "An adult that leaks everywhere", ascii.Ooze, 1.1, 1.2, 1.1),
+			FormOverweightAdult: character("blob", "Blob", "An adult that absorbed a little too much", ascii.Blob, 1.3, 0.9, 1.2),
+			FormSenior:          character("royalslime", "Royal Slime", "A wise old slime wearing a crown", ascii.RoyalSlime, 0.8, 0.8, 0.8),
+			FormNeglectedSenior: character("muck", "Muck", "An old slime gone all murky", ascii.Muck, 1, 1.2, 1),
+		},
+	})
+
+	registerSpecies(&Species{
+		ID:          SpeciesDragon,
+		Name:        "Dragon",
+		Description: "Eats a lot and lives for ages. Rarely gets sick",
+		Rates: ratesWith(func(r *Rates) {
+			r.Hunger = 2
+			r.HappinessLoss = 1.2
+			r.SickChance = 1.0 / 240
+			r.FatDamage = 0.1
+		}),
+		Lifespan: 40,
+		Characters: map[string]*Character{
+			FormBaby:            character("hatchling", "Hatchling", "Only just out of its shell", ascii.Hatchling, 1, 1, 1),
+			FormChild:           character("whelp", "Whelp", "A playful young dragon", ascii.Whelp, 1, 0.9, 1),
+			FormNeglectedChild:  character("runt", "Runt", "A small dragon with a bent wing", ascii.Runt, 1.1, 1.2, 1),
+			FormTeen:            character("drake", "Drake", "A teenager learning to fly", ascii.Drake, 1, 0.9, 0.9),
+			FormNeglectedTeen:   character("cinder", "Cinder", "A smoky teenager that sets things on fire", ascii.Cinder, 1.2, 1.1, 1.1),
+			FormExemplaryAdult:  character("golddragon", "Gold Dragon", "A majestic dragon raised with great care", ascii.GoldDragon, 0.9, 0.8, 0.9),
+			FormAdult:           character("dragon", "Dragon", "A proper, ordinary dragon", ascii.Dragon, 1, 1, 1),
+			FormUnrulyAdult:     character("wyvern", "Wyvern", "A wild dragon that answers to nobody", ascii.Wyvern, 1.1, 1.2, 1.1),
+			FormOverweightAdult: character("hoarder", "Hoarder", "A dragon sitting on a pile of snacks", ascii.Hoarder, 1.3, 0.9, 1.2),
+			FormSenior:          character("ancient", "Ancient", "A dragon older than the hills", ascii.Ancient, 0.8, 0.8, 0.8),
+			FormNeglectedSenior: character("ashen", "Ashen", "An old dragon whose fire has gone out", ascii.Ashen, 1, 1.2, 1),
+		},
+	})
+
+	foods = mustParseFoodCatalog(defaultFoods)
+}
@@ -0,0 +1,124 @@
+package pet
+
+import (
+	"time"
+)
+
+// StatusVersion is the version of the Status document. It is bumped
+// whenever a field is removed or changes meaning; new fields may be added
+// without a bump.
+const StatusVersion = 1
+
+// Needs a pet can have that the player should take care of
+const (
+	NeedFood     = "food"
+	NeedMedicine = "medicine"
+	NeedCleaning = "cleaning"
+	NeedPlay     = "play"
+	// NeedDiscipline means the pet is misbehaving and should be scolded
+	NeedDiscipline = "discipline"
+)
+
+// Status is a stable, machine readable snapshot of a pet, used for JSON
+// output. It is kept separate from Pet so the internal struct can change
+// without breaking consumers.
+type Status struct {
+	ID           int        `json:"id"`
+	Name         string     `json:"name"`
+	Species      string     `json:"species"`
+	BirthDate    time.Time  `json:"birthDate"`
+	AgeHours     int        `json:"ageHours"`
+	AgeYears     int        `json:"ageYears"`
+	LifeStage    string     `json:"lifeStage"`
+	State        string     `json:"state"`
+	Alive        bool       `json:"alive"`
+	Stats        Stats      `json:"stats"`
+	IsSick       bool       `json:"isSick"`
+	HasPooped    bool       `json:"hasPooped"`
+	LightsOn     bool       `json:"lightsOn"`
+	Misbehavior  string     `json:"misbehavior,omitempty"`
+	Character    string     `json:"character"`
+	Needs        []string   `json:"needs"`
+	DiedAt       *time.Time `json:"diedAt,omitempty"`
+	CauseOfDeath string     `json:"causeOfDeath,omitempty"`
+}
+
+// Stats holds the numeric stats of a pet
+type Stats struct {
+	Hunger     int `json:"hunger"`
+	Happiness  int `json:"happiness"`
+	Health     int `json:"health"`
+	Weight     int `json:"weight"`
+	Discipline int `json:"discipline"`
+}
+
+// PendingNeeds returns what the pet currently needs from its player, most
+// urgent first. Dead pets have no needs.
+func (p *Pet) PendingNeeds() []string {
+	needs := []string{}
+
+	if p.IsDead() {
+		return needs
+	}
+
+	if p.IsSick {
+		needs = append(needs, NeedMedicine)
+	}
+
+	if p.Hunger > 70 {
+		needs = append(needs, NeedFood)
+	}
+
+	if p.HasPooped {
+		needs = append(needs, NeedCleaning)
+	}
+
+	if p.Happiness < 30 {
+		needs = append(needs, NeedPlay)
+	}
+
+	if p.IsMisbehaving() {
+		needs = append(needs, NeedDiscipline)
+	}
+
+	return needs
+}
+
+// StatusAt returns the status document of the pet at the given time
+func (p *Pet) StatusAt(t time.Time) Status {
+	status := Status{
+		ID:        p.ID,
+		Name:      p.Name,
+		Species:   p.Species().ID,
+		BirthDate: p.BirthDate.UTC(),
+		AgeHours:  int(t.Sub(p.BirthDate).Hours()),
+		AgeYears:  p.ageInYearsAt(t),
+		LifeStage: p.LifeStageAt(t),
+		State:     string(p.GetState()),
+		Alive:     !p.IsDead(),
+		Stats: Stats{
+			Hunger:     p.Hunger,
+			Happiness:  p.Happiness,
+			Health:     p.Health,
+			Weight:     p.Weight,
+			Discipline: p.Discipline,
+		},
+		IsSick:      p.IsSick,
+		HasPooped:   p.HasPooped,
+		LightsOn:    p.LightsOn,
+		Misbehavior: p.Misbehavior,
+		Character:   p.Character().ID,
+		Needs:       p.PendingNeeds(),
+	}
+
+	if !p.DiedAt.IsZero() {
+		diedAt := p.DiedAt.UTC()
+		status.DiedAt = &diedAt
+		status.CauseOfDeath = p.CauseOfDeath
+		status.AgeHours = int(p.Lifetime(t).Hours())
+		status.AgeYears = p.ageInYearsAt(p.DiedAt)
+		status.LifeStage = p.FinalLifeStage(t)
+	}
+
+	return status
+}
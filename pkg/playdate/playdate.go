@@ -0,0 +1,324 @@
+package playdate
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/kirkegaard/terminal-pet/pkg/pet"
+)
+
+// The sides of a playdate. The host invited the guest.
+const (
+	Host  = 0
+	Guest = 1
+)
+
+// Rewards are how much happier a playdate game makes a pet
+const (
+	WinReward  = 20
+	PlayReward = 10
+)
+
+// Game is a minigame two pets play together. Cooperative games have both
+// players work towards Goal before Duration runs out, competitive games are
+// won by whoever gets to Goal first.
+type Game struct {
+	ID          string
+	Name        string
+	Description string
+	Cooperative bool
+	Goal        int
+	Duration    time.Duration
+}
+
+var games = []Game{
+	{
+		ID:          "tug",
+		Name:        "Tug of war",
+		Description: "Mash SPACE to pull the rope over to your side",
+		Goal:        10,
+	},
+	{
+		ID:          "tower",
+		Name:        "Tower",
+		Description: "Mash SPACE together to stack 40 blocks in 20 seconds",
+		Cooperative: true,
+		Goal:        40,
+		Duration:    20 * time.Second,
+	},
+}
+
+// Games returns every playdate game in the order they are shown
+func Games() []Game {
+	return games
+}
+
+// State is a snapshot of a playdate, sent to both players whenever it
+// changes
+type State struct {
+	// Version increases with every change, so stale snapshots can be told
+	// apart from new ones
+	Version int
+	Players [2]string
+	// Pets are copies of the pets as they were when the playdate started
+	Pets [2]*pet.Pet
+	// Game is the game being played, or the last one played
+	Game *Game
+	// Round increases with every game started
+	Round    int
+	Playing  bool
+	Presses  [2]int
+	Deadline time.Time
+	// Rope is how far the rope was pulled, negative towards the host
+	Rope int
+	// Winner is the side that won the last competitive game, or -1
+	Winner int
+	// Success tells whether the last cooperative game reached its goal
+	Success bool
+	// Left is the side that went home, or -1 while both are there
+	Left int
+
+	// Consent tells which players agreed to their pets having a baby, and
+	// Full which ones have no room for another pet
+	Consent [2]bool
+	Full    [2]bool
+	// Bred tells whether the pets had a baby during the playdate, and Owner
+	// the side that asked for it first and keeps it, or -1
+	Bred  bool
+	Owner int
+}
+
+// Reward returns how much happier the last game made the pet of a side
+func (s State) Reward(side int) int {
+	if s.Game == nil {
+		return 0
+	}
+
+	if (s.Game.Cooperative && s.Success) || (!s.Game.Cooperative && s.Winner == side) {
+		return WinReward
+	}
+
+	return PlayReward
+}
+
+// CanBreed returns why the pets can't have a baby at the given time, or nil
+// if they can once both players agree
+func (s State) CanBreed(now time.Time) error {
+	if s.Bred {
+		return errors.New("the pets already had a baby on this playdate")
+	}
+
+	if err := s.Pets[Host].CanBreedWith(s.Pets[Guest], now); err != nil {
+		return err
+	}
+
+	if s.Full[Host] && s.Full[Guest] {
+		return errors.New("neither player has room for another pet")
+	}
+
+	return nil
+}
+
+// Blocks returns how many blocks were stacked together in a cooperative
+// game
+func (s State) Blocks() int {
+	return s.Presses[Host] + s.Presses[Guest]
+}
+
+// Playdate is two pets spending time together. Both players' sessions share
+// it, every change is passed to notify so it can be sent to both.
+type Playdate struct {
+	mu     sync.Mutex
+	state  State
+	notify func(State)
+}
+
+// New starts a playdate between the pets of two players. The pets are
+// copied, so the players' sessions keep their own.
+func New(players [2]string, pets [2]*pet.Pet, notify func(State)) *Playdate {
+	return &Playdate{
+		state: State{
+			Players: players,
+			Pets:    [2]*pet.Pet{Snapshot(pets[Host]), Snapshot(pets[Guest])},
+			Winner:  -1,
+			Left:    -1,
+			Owner:   -1,
+		},
+		notify: notify,
+	}
+}
+
+// Snapshot copies a pet so it can be shown in another session
+func Snapshot(p *pet.Pet) *pet.Pet {
+	c := *p
+	c.TakeEvents()
+
+	return &c
+}
+
+// State returns the current state of the playdate
+func (pd *Playdate) State() State {
+	pd.mu.Lock()
+	defer pd.mu.Unlock()
+
+	return pd.state
+}
+
+// change applies a change to the state and notifies both players
+func (pd *Playdate) change(apply func(s *State) bool) {
+	pd.mu.Lock()
+	if !apply(&pd.state) {
+		pd.mu.Unlock()
+		return
+	}
+	pd.state.Version++
+	state := pd.state
+	pd.mu.Unlock()
+
+	pd.notify(state)
+}
+
+// Start starts a game, unless one is being played
+func (pd *Playdate) Start(id string, now time.Time) error {
+	var game *Game
+	for i := range games {
+		if games[i].ID == id {
+			game = &games[i]
+		}
+	}
+	if game == nil {
+		return fmt.Errorf("unknown playdate game %q", id)
+	}
+
+	pd.change(func(s *State) bool {
+		if s.Playing || s.Left >= 0 {
+			return false
+		}
+
+		s.Game = game
+		s.Round++
+		s.Playing = true
+		s.Presses = [2]int{}
+		s.Rope = 0
+		s.Winner = -1
+		s.Success = false
+		s.Deadline = time.Time{}
+		if game.Duration > 0 {
+			s.Deadline = now.Add(game.Duration)
+		}
+
+		return true
+	})
+
+	return nil
+}
+
+// Press is a player mashing the button in a game
+func (pd *Playdate) Press(side int, now time.Time) {
+	pd.change(func(s *State) bool {
+		if !s.Playing || s.Left >= 0 {
+			return false
+		}
+
+		if s.timeUp(now) {
+			s.Playing = false
+			return true
+		}
+
+		s.Presses[side]++
+
+		if s.Game.Cooperative {
+			if s.Blocks() >= s.Game.Goal {
+				s.Success = true
+				s.Playing = false
+			}
+			return true
+		}
+
+		if side == Host {
+			s.Rope--
+		} else {
+			s.Rope++
+		}
+
+		switch {
+		case s.Rope <= -s.Game.Goal:
+			s.Winner = Host
+			s.Playing = false
+		case s.Rope >= s.Game.Goal:
+			s.Winner = Guest
+			s.Playing = false
+		}
+
+		return true
+	})
+}
+
+// Tick ends a timed game once its time is up
+func (pd *Playdate) Tick(now time.Time) {
+	pd.change(func(s *State) bool {
+		if !s.Playing || !s.timeUp(now) {
+			return false
+		}
+
+		s.Playing = false
+		return true
+	})
+}
+
+// SetFull records that a player has no room for another pet
+func (pd *Playdate) SetFull(side int) {
+	pd.change(func(s *State) bool {
+		if s.Full[side] {
+			return false
+		}
+
+		s.Full[side] = true
+		return true
+	})
+}
+
+// Consent records whether a player agrees to the pets having a baby. Once
+// both players agree the pets have one, which the player who asked first
+// keeps. Players without room for another pet can only agree to the other
+// player asking.
+func (pd *Playdate) Consent(side int, agree bool, now time.Time) {
+	pd.change(func(s *State) bool {
+		if s.Playing || s.Left >= 0 || s.Consent[side] == agree || s.CanBreed(now) != nil {
+			return false
+		}
+
+		if agree && !s.Consent[1-side] && s.Full[side] {
+			return false
+		}
+
+		s.Consent[side] = agree
+		if s.Consent[Host] && s.Consent[Guest] {
+			s.Bred = true
+			s.Owner = 1 - side
+			s.Consent = [2]bool{}
+		}
+
+		return true
+	})
+}
+
+// Leave ends the playdate for both players
+func (pd *Playdate) Leave(side int) {
+	pd.change(func(s *State) bool {
+		if s.Left >= 0 {
+			return false
+		}
+
+		s.Left = side
+		s.Playing = false
+		return true
+	})
+}
+
+// timeUp reports whether a timed game ran out of time
+func (s *State) timeUp(now time.Time) bool {
+	return !s.Deadline.IsZero() && !now.Before(s.Deadline)
+}
@@ -0,0 +1,100 @@
+// Package shop lists what players can buy with their coins: every food in
+// the food catalog, medicine and toys.
+package shop
+
+import (
+	"strings"
+
+	"github.com/kirkegaard/terminal-pet/pkg/pet"
+)
+
+// Kinds of items
+const (
+	KindFood     = "food"
+	KindMedicine = "medicine"
+	KindToy      = "toy"
+)
+
+// MedicineID is the item used up by giving medicine
+const MedicineID = "medicine"
+
+// Item is something for sale in the shop
+type Item struct {
+	ID          string
+	Name        string
+	Emoji       string
+	Kind        string
+	Description string
+	Price       int
+
+	// Fun is the extra happiness a toy gives whenever the pet plays. Toys are
+	// never used up and only the best one counts.
+	Fun int
+}
+
+// extras are the items that aren't food
+var extras = []Item{
+	{
+		ID:          MedicineID,
+		Name:        "Medicine",
+		Emoji:       "💊",
+		Kind:        KindMedicine,
+		Description: "Cures sickness, but don't give it to a healthy pet",
+		Price:       30,
+	},
+	{
+		ID:          "ball",
+		Name:        "Ball",
+		Emoji:       "⚽",
+		Kind:        KindToy,
+		Description: "Makes playing a bit more fun",
+		Price:       40,
+		Fun:         3,
+	},
+	{
+		ID:          "robot",
+		Name:        "Robot",
+		Emoji:       "🤖",
+		Kind:        KindToy,
+		Description: "The best toy money can buy",
+		Price:       120,
+		Fun:         8,
+	},
+}
+
+// Items returns everything for sale, foods first in menu order
+func Items() []Item {
+	foods := pet.Foods().All()
+
+	items := make([]Item, 0, len(foods)+len(extras))
+	for _, f := range foods {
+		items = append(items, FoodItem(f))
+	}
+
+	return append(items, extras...)
+}
+
+// Get returns the item with the given ID, ignoring case
+func Get(id string) (Item, bool) {
+	id = strings.ToLower(strings.TrimSpace(id))
+
+	for _, item := range Items() {
+		if item.ID == id {
+			return item, true
+		}
+	}
+
+	return Item{}, false
+}
+
+// FoodItem returns the shop item for a food
+func FoodItem(f *pet.Food) Item {
+	return Item{
+		ID:          f.ID,
+		Name:        f.Name,
+		Emoji:       f.Emoji,
+		Kind:        KindFood,
+		Description: f.Description,
+		Price:       f.Price,
+	}
+}
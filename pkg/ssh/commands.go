@@ -0,0 +1,439 @@
+package ssh
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/charmbracelet/ssh"
+	"github.com/charmbracelet/wish"
+	"github.com/kirkegaard/terminal-pet/pkg/actions"
+	"github.com/kirkegaard/terminal-pet/pkg/api"
+	"github.com/kirkegaard/terminal-pet/pkg/pet"
+	"github.com/kirkegaard/terminal-pet/pkg/shop"
+	petui "github.com/kirkegaard/terminal-pet/pkg/ui"
+	"github.com/kirkegaard/terminal-pet/pkg/ui/games"
+	"github.com/kirkegaard/terminal-pet/pkg/ui/views"
+)
+
+// commandUsages lists every command in the order of the usage text
+func commandUsages() []string {
+	return []string{
+		"status",
+		"feed <" + strings.Join(pet.Foods().IDs(), "|") + ">",
+		"play",
+		"clean",
+		"medicine",
+		"lights",
+		"rename <name>",
+		"scold",
+		"praise",
+		"shop",
+		"buy <item>",
+		"inventory",
+		"scores [" + strings.Join(gameIDs(), "|") + "]",
+		"diary",
+		"visit <name>",
+		"privacy [public|private]",
+		"friends",
+		"friend <name>",
+		"unfriend <name>",
+		"gift <name> <item>",
+		"token [revoke]",
+	}
+}
+
+// jsonFlag switches command output to a commandResult JSON document
+const jsonFlag = "--json"
+
+// commandResult is the JSON document printed by commands run with --json.
+// Its version follows pet.StatusVersion.
+type commandResult struct {
+	Version int         `json:"version"`
+	Command string      `json:"command"`
+	Message string      `json:"message,omitempty"`
+	Error   string      `json:"error,omitempty"`
+	Pet     *pet.Status `json:"pet,omitempty"`
+}
+
+// CommandMiddleware runs exec requests such as `ssh host status` against the
+// caller's pet and prints a short text result, except for `ssh -t host visit
+// <name>` which is left to the session handler. It must come before the
+// bubbletea middleware, which hands over every session it does not start a
+// program for.
+func (srv *SSHServer) CommandMiddleware() wish.Middleware {
+	return func(next ssh.Handler) ssh.Handler {
+		return func(s ssh.Session) {
+			// Interactive visits get the visit screen instead
+			if _, visiting := interactiveVisit(s); len(s.Command()) == 0 || visiting {
+				next(s)
+				return
+			}
+
+			args, asJSON := parseCommandFlags(s.Command())
+
+			publicKey := GetPublicKeyFromContext(s.Context())
+			if publicKey == "" {
+				publicKey = fmt.Sprintf("user-%s", s.User())
+			}
+
+			out, p, err := srv.runCommand(s.Context(), publicKey, args)
+			if err != nil {
+				log.Debug("Command failed", "command", args, "error", err)
+			}
+
+			if asJSON {
+				writeCommandJSON(s, args, out, p, err)
+				return
+			}
+
+			if err != nil {
+				wish.Fatalln(s, err.Error())
+				return
+			}
+
+			wish.Println(s, out)
+		}
+	}
+}
+
+// parseCommandFlags removes the flags from the command arguments
+func parseCommandFlags(command []string) (args []string, asJSON bool) {
+	args = make([]string, 0, len(command))
+	for _, arg := range command {
+		if arg == jsonFlag {
+			asJSON = true
+			continue
+		}
+		args = append(args, arg)
+	}
+
+	return args, asJSON
+}
+
+// writeCommandJSON prints the outcome of a command as a commandResult
+func writeCommandJSON(s ssh.Session, args []string, out string, p *pet.Pet, cmdErr error) {
+	result := commandResult{
+		Version: pet.StatusVersion,
+		Message: out,
+	}
+
+	if len(args) > 0 {
+		result.Command = strings.ToLower(args[0])
+	}
+
+	if p != nil {
+		status := p.StatusAt(time.Now())
+		result.Pet = &status
+	}
+
+	if cmdErr != nil {
+		result.Error = cmdErr.Error()
+	}
+
+	data, err := json.Marshal(result)
+	if err != nil {
+		log.Error("Error encoding command result", "error", err)
+		wish.Fatalln(s, "could not encode result")
+		return
+	}
+
+	if cmdErr != nil {
+		wish.Fatalln(s, string(data))
+		return
+	}
+
+	wish.Println(s, string(data))
+}
+
+// runCommand runs a command on the caller's current pet. The pet is returned
+// whenever it could be loaded, even if the command failed.
+func (srv *SSHServer) runCommand(ctx context.Context, publicKey string, args []string) (string, *pet.Pet, error) {
+	if len(args) == 0 {
+		return "", nil, fmt.Errorf("no command given\n\n%s", commandUsage())
+	}
+
+	name := strings.ToLower(args[0])
+	switch name {
+	case "token":
+		out, err := srv.tokenCommand(ctx, publicKey, args[1:])
+		return out, nil, err
+	case "shop", "buy", "inventory":
+		out, err := srv.shopCommand(ctx, publicKey, name, args[1:])
+		return out, nil, err
+	case "scores":
+		out, err := srv.scoresCommand(ctx, args[1:])
+		return out, nil, err
+	case "visit":
+		out, err := srv.visitCommand(ctx, publicKey, args[1:])
+		return out, nil, err
+	case "privacy":
+		out, err := srv.privacyCommand(ctx, publicKey, args[1:])
+		return out, nil, err
+	case "friends", "friend", "unfriend", "gift":
+		out, err := srv.friendsCommand(ctx, publicKey, name, args[1:])
+		return out, nil, err
+	}
+
+	if name != "status" && name != "diary" && !slices.Contains(actions.Names, name) {
+		return "", nil, fmt.Errorf("unknown command %q\n\n%s", args[0], commandUsage())
+	}
+
+	userID, err := srv.deps.Users.GetByPublicKey(ctx, publicKey)
+	if err != nil {
+		log.Error("Error finding user", "error", err)
+		return "", nil, fmt.Errorf("could not load your pet")
+	}
+
+	var p *pet.Pet
+	if userID != 0 {
+		p, err = srv.deps.Actions.CurrentPet(ctx, userID)
+		if err != nil {
+			log.Error("Error finding pet", "error", err)
+			return "", nil, fmt.Errorf("could not load your pet")
+		}
+	}
+	if p == nil {
+		return "", nil, fmt.Errorf("you don't have a pet yet, connect with ssh to adopt one")
+	}
+
+	if name == "status" {
+		return petStatus(p), p, nil
+	}
+
+	if name == "diary" {
+		out, err := srv.diaryCommand(ctx, p, args[1:])
+		return out, p, err
+	}
+
+	out, err := srv.deps.Actions.Perform(ctx, p, name, strings.Join(args[1:], " "))
+	if err != nil {
+		return "", p, err
+	}
+
+	return out, p, nil
+}
+
+// tokenCommand mints a new API token for the caller, or revokes all of their
+// tokens
+func (srv *SSHServer) tokenCommand(ctx context.Context, publicKey string, args []string) (string, error) {
+	if len(args) > 1 || (len(args) == 1 && args[0] != "revoke") {
+		return "", fmt.Errorf("usage: token [revoke]")
+	}
+
+	userID, err := srv.deps.Users.GetByPublicKey(ctx, publicKey)
+	if err != nil {
+		log.Error("Error finding user", "error", err)
+		return "", fmt.Errorf("could not find your account")
+	}
+	if userID == 0 {
+		return "", fmt.Errorf("you don't have a pet yet, connect with ssh to adopt one")
+	}
+
+	if len(args) == 1 {
+		revoked, err := srv.deps.Tokens.DeleteByUserID(ctx, userID)
+		if err != nil {
+			log.Error("Error revoking tokens", "error", err)
+			return "", fmt.Errorf("could not revoke your tokens")
+		}
+		return fmt.Sprintf("Revoked %d token(s).", revoked), nil
+	}
+
+	token, hash, err := api.NewToken()
+	if err != nil {
+		log.Error("Error generating token", "error", err)
+		return "", fmt.Errorf("could not create a token")
+	}
+
+	if err := srv.deps.Tokens.Create(ctx, userID, hash); err != nil {
+		log.Error("Error storing token", "error", err)
+		return "", fmt.Errorf("could not create a token")
+	}
+
+	log.Info("Minted api token", "user_id", userID)
+
+	return token, nil
+}
+
+// shopCommand lists what's for sale, buys an item or lists what the caller
+// owns
+func (srv *SSHServer) shopCommand(ctx context.Context, publicKey string, name string, args []string) (string, error) {
+	if name == "buy" && len(args) != 1 {
+		return "", fmt.Errorf("usage: buy <item>")
+	}
+	if name != "buy" && len(args) != 0 {
+		return "", fmt.Errorf("usage: %s", name)
+	}
+
+	userID, err := srv.deps.Users.GetByPublicKey(ctx, publicKey)
+	if err != nil {
+		log.Error("Error finding user", "error", err)
+		return "", fmt.Errorf("could not find your account")
+	}
+	if userID == 0 {
+		return "", fmt.Errorf("you don't have a pet yet, connect with ssh to adopt one")
+	}
+
+	inventory := srv.deps.Actions.Inventory()
+
+	if name == "buy" {
+		item, coins, err := actions.Buy(ctx, inventory, userID, args[0])
+		if err != nil {
+			if !errors.Is(err, actions.ErrUnknownItem) && !errors.Is(err, actions.ErrNotEnoughCoins) &&
+				!errors.Is(err, actions.ErrAlreadyOwned) {
+				log.Error("Error buying item", "error", err)
+				return "", fmt.Errorf("could not buy %s", args[0])
+			}
+			return "", err
+		}
+		return fmt.Sprintf("You bought %s %s for %d coins. You have %d coins left.",
+			item.Emoji, strings.ToLower(item.Name), item.Price, coins), nil
+	}
+
+	coins, err := inventory.Coins(ctx, userID)
+	if err != nil {
+		log.Error("Error loading coins", "error", err)
+		return "", fmt.Errorf("could not load your inventory")
+	}
+
+	owned, err := inventory.Items(ctx, userID)
+	if err != nil {
+		log.Error("Error loading inventory", "error", err)
+		return "", fmt.Errorf("could not load your inventory")
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Coins: %d", coins)
+	for _, item := range shop.Items() {
+		if name == "shop" {
+			fmt.Fprintf(&sb, "\n  %-10s %4d coins  %s (owned: %d)", item.ID, item.Price, item.Description, owned[item.ID])
+		} else if owned[item.ID] > 0 {
+			fmt.Fprintf(&sb, "\n  %s %s x%d", item.Emoji, item.Name, owned[item.ID])
+		}
+	}
+	if name == "inventory" && len(owned) == 0 {
+		sb.WriteString("\n  Nothing yet, buy something with `buy <item>`.")
+	}
+
+	return sb.String(), nil
+}
+
+// gameIDs returns the IDs of every minigame
+func gameIDs() []string {
+	var ids []string
+	for _, g := range games.All() {
+		ids = append(ids, g.ID)
+	}
+
+	return ids
+}
+
+// scoresCommand lists the leaderboard of one game, or of every game
+func (srv *SSHServer) scoresCommand(ctx context.Context, args []string) (string, error) {
+	if len(args) > 1 {
+		return "", fmt.Errorf("usage: scores [%s]", strings.Join(gameIDs(), "|"))
+	}
+
+	list := games.All()
+	if len(args) == 1 {
+		g, ok := games.Get(strings.ToLower(args[0]))
+		if !ok {
+			return "", fmt.Errorf("unknown game %q, choose one of %s", args[0], strings.Join(gameIDs(), ", "))
+		}
+		list = []games.Game{g}
+	}
+
+	var sections []string
+	for _, g := range list {
+		top, err := srv.deps.Scores.Top(ctx, g.ID, petui.LeaderboardSize)
+		if err != nil {
+			log.Error("Error loading high scores", "game", g.ID, "error", err)
+			return "", fmt.Errorf("could not load the high scores")
+		}
+
+		var sb strings.Builder
+		sb.WriteString(g.Name)
+		if len(top) == 0 {
+			sb.WriteString("\n  No scores yet.")
+		}
+		for i, score := range top {
+			fmt.Fprintf(&sb, "\n  %2d. %-16s %-16s %4d", i+1, score.UserName, score.PetName, score.Score)
+		}
+		sections = append(sections, sb.String())
+	}
+
+	return strings.Join(sections, "\n\n"), nil
+}
+
+// diaryLength is how many entries of the activity log the diary command
+// prints
+const diaryLength = 20
+
+// diaryCommand lists the latest entries of the activity log of a pet, newest
+// first
+func (srv *SSHServer) diaryCommand(ctx context.Context, p *pet.Pet, args []string) (string, error) {
+	if len(args) != 0 {
+		return "", fmt.Errorf("usage: diary")
+	}
+
+	entries, err := srv.deps.History.ListByPetID(ctx, p.ID, diaryLength)
+	if err != nil {
+		log.Error("Error loading diary", "pet_id", p.ID, "error", err)
+		return "", fmt.Errorf("could not load the diary")
+	}
+
+	var sb strings.Builder
+	sb.WriteString(p.Name + "'s diary")
+	if len(entries) == 0 {
+		sb.WriteString("\n  Nothing has happened yet.")
+	}
+	for _, e := range entries {
+		sb.WriteString("\n  ")
+		sb.WriteString(petui.DiaryLine(e))
+	}
+
+	return sb.String(), nil
+}
+
+// petStatus renders a one-line summary of the pet as it was simulated to
+func petStatus(p *pet.Pet) string {
+	stage := p.Character().Name + ", " + p.FinalLifeStage(p.SimulatedAt)
+
+	status := fmt.Sprintf("%s the %s (%s, %dh old): %s - hunger %d, happiness %d, health %d, weight %d, discipline %d",
+		p.Name, p.Species().Name, stage, p.AgeAt(p.SimulatedAt), views.GetPetState(p),
+		p.Hunger, p.Happiness, p.Health, p.Weight, p.Discipline)
+
+	var flags []string
+	if p.IsSick {
+		flags = append(flags, "sick")
+	}
+	if p.HasPooped {
+		flags = append(flags, "needs cleaning")
+	}
+	if !p.LightsOn {
+		flags = append(flags, "lights off")
+	}
+	if p.IsMisbehaving() {
+		flags = append(flags, "misbehaving")
+	}
+	if len(flags) > 0 {
+		status += " [" + strings.Join(flags, ", ") + "]"
+	}
+
+	return status
+}
+
+func commandUsage() string {
+	var sb strings.Builder
+	sb.WriteString("Commands:")
+	for _, usage := range commandUsages() {
+		sb.WriteString("\n  ")
+		sb.WriteString(usage)
+	}
+	return sb.String()
+}
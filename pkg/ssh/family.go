@@ -0,0 +1,79 @@
+package ssh
+
+import (
+	"context"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/log"
+	"github.com/kirkegaard/terminal-pet/pkg/pet"
+	petui "github.com/kirkegaard/terminal-pet/pkg/ui"
+)
+
+// showFamily switches the UI to the family tree of the current pet
+func (ui *UI) showFamily() {
+	ctx := context.Background()
+	p := ui.ownPet()
+
+	tree := petui.FamilyTree{
+		Pet:      p,
+		Parents:  ui.relatives(ctx, p.ID, ui.lineage.Parents),
+		Children: ui.relatives(ctx, p.ID, ui.lineage.Children),
+	}
+	for _, parent := range tree.Parents {
+		tree.Grandparents = append(tree.Grandparents, ui.relatives(ctx, parent.ID, ui.lineage.Parents))
+	}
+
+	ui.family = petui.NewFamily(tree, ui.width, ui.height)
+}
+
+// relatives loads the pets related to a pet as listed by the lineage
+func (ui *UI) relatives(ctx context.Context, petID int, list func(ctx context.Context, petID int) ([]int, error)) []*pet.Pet {
+	ids, err := list(ctx, petID)
+	if err != nil {
+		log.Error("Error loading family", "pet_id", petID, "error", err)
+		return nil
+	}
+
+	var pets []*pet.Pet
+	for _, id := range ids {
+		p, err := ui.pets.GetByID(ctx, id)
+		if err != nil {
+			log.Error("Error loading relative", "pet_id", id, "error", err)
+			continue
+		}
+		if p != nil {
+			pets = append(pets, p)
+		}
+	}
+
+	return pets
+}
+
+// updateFamily handles messages while the family tree is shown
+func (ui *UI) updateFamily(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+	case timeMsg:
+		ui.time = time.Time(msg)
+
+	case tea.WindowSizeMsg:
+		ui.height = msg.Height
+		ui.width = msg.Width
+		_, cmd = ui.family.Update(msg)
+		ui.petUI.Update(msg)
+
+	case petui.CloseFamilyMsg:
+		ui.family = nil
+
+	case petui.FrameMsg:
+		// Keep the pet's ticker running behind the family tree
+		ui.petUI, cmd = ui.petUI.Update(msg)
+
+	default:
+		_, cmd = ui.family.Update(msg)
+	}
+
+	return ui, cmd
+}
@@ -0,0 +1,388 @@
+package ssh
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/log"
+	"github.com/kirkegaard/terminal-pet/pkg/db/models"
+	"github.com/kirkegaard/terminal-pet/pkg/db/repo"
+	"github.com/kirkegaard/terminal-pet/pkg/shop"
+	petui "github.com/kirkegaard/terminal-pet/pkg/ui"
+	"github.com/kirkegaard/terminal-pet/pkg/ui/views"
+)
+
+// findPlayer returns the player with the given name. Errors are meant to be
+// shown to the player looking them up.
+func findPlayer(ctx context.Context, users repo.UserStore, name string) (*models.User, error) {
+	user, err := users.FindByName(ctx, name)
+	if errors.Is(err, repo.ErrAmbiguousName) {
+		return nil, fmt.Errorf("more than one player is named %s, so they can't be told apart", name)
+	}
+	if err != nil {
+		log.Error("Error finding user", "name", name, "error", err)
+		return nil, fmt.Errorf("could not look up %s", name)
+	}
+	if user == nil {
+		return nil, fmt.Errorf("there is no player named %s", name)
+	}
+
+	return user, nil
+}
+
+// addFriend sends the named player a friend request, or accepts theirs if
+// they already sent one. Errors are meant to be shown to the player.
+func addFriend(ctx context.Context, users repo.UserStore, friendships repo.FriendStore, userID int, name string) (string, error) {
+	other, err := findPlayer(ctx, users, name)
+	if err != nil {
+		return "", err
+	}
+	if other.ID == userID {
+		return "", fmt.Errorf("you can't be your own friend")
+	}
+
+	f, err := friendships.Get(ctx, userID, other.ID)
+	if err != nil {
+		log.Error("Error finding friend", "user_id", userID, "friend_id", other.ID, "error", err)
+		return "", fmt.Errorf("could not add %s as a friend", other.Name)
+	}
+
+	switch {
+	case f == nil:
+		if err := friendships.Request(ctx, userID, other.ID, time.Now()); err != nil {
+			log.Error("Error sending friend request", "user_id", userID, "friend_id", other.ID, "error", err)
+			return "", fmt.Errorf("could not add %s as a friend", other.Name)
+		}
+		log.Info("Sent friend request", "user_id", userID, "friend_id", other.ID)
+		return fmt.Sprintf("Sent a friend request to %s.", other.Name), nil
+
+	case f.AcceptedAt.Valid:
+		return "", fmt.Errorf("you and %s are already friends", other.Name)
+
+	case !f.Incoming:
+		return "", fmt.Errorf("you already asked %s, they have to accept", other.Name)
+	}
+
+	if err := friendships.Accept(ctx, other.ID, userID, time.Now()); err != nil {
+		log.Error("Error accepting friend request", "user_id", userID, "friend_id", other.ID, "error", err)
+		return "", fmt.Errorf("could not add %s as a friend", other.Name)
+	}
+	log.Info("Accepted friend request", "user_id", userID, "friend_id", other.ID)
+
+	return fmt.Sprintf("You and %s are now friends!", other.Name), nil
+}
+
+// removeFriend ends the friendship with the named player, or declines or
+// withdraws a friend request. Errors are meant to be shown to the player.
+func removeFriend(ctx context.Context, users repo.UserStore, friendships repo.FriendStore, userID int, name string) (string, error) {
+	other, err := findPlayer(ctx, users, name)
+	if err != nil {
+		return "", err
+	}
+
+	f, err := friendships.Get(ctx, userID, other.ID)
+	if err != nil {
+		log.Error("Error finding friend", "user_id", userID, "friend_id", other.ID, "error", err)
+		return "", fmt.Errorf("could not remove %s", other.Name)
+	}
+	if f == nil {
+		return "", fmt.Errorf("%s is not on your friends list", other.Name)
+	}
+
+	if _, err := friendships.Remove(ctx, userID, other.ID); err != nil {
+		log.Error("Error removing friend", "user_id", userID, "friend_id", other.ID, "error", err)
+		return "", fmt.Errorf("could not remove %s", other.Name)
+	}
+	log.Info("Removed friend", "user_id", userID, "friend_id", other.ID)
+
+	switch {
+	case f.AcceptedAt.Valid:
+		return fmt.Sprintf("%s is no longer your friend.", other.Name), nil
+	case f.Incoming:
+		return fmt.Sprintf("Declined %s's friend request.", other.Name), nil
+	default:
+		return fmt.Sprintf("Withdrew your friend request to %s.", other.Name), nil
+	}
+}
+
+// sendGift gives one of the player's items to the named friend, who gets it
+// the next time they connect. Like in the shop, a toy is only given to
+// friends who don't have one. Errors are meant to be shown to the player.
+func sendGift(ctx context.Context, users repo.UserStore, friendships repo.FriendStore, gifts repo.GiftStore, inventory repo.InventoryStore, userID int, name string, itemID string) (string, error) {
+	item, ok := shop.Get(itemID)
+	if !ok {
+		return "", fmt.Errorf("unknown item %q, see your inventory for what you have", itemID)
+	}
+
+	other, err := findPlayer(ctx, users, name)
+	if err != nil {
+		return "", err
+	}
+
+	f, err := friendships.Get(ctx, userID, other.ID)
+	if err != nil {
+		log.Error("Error finding friend", "user_id", userID, "friend_id", other.ID, "error", err)
+		return "", fmt.Errorf("could not send the gift")
+	}
+	if f == nil || !f.AcceptedAt.Valid {
+		return "", fmt.Errorf("you can only send gifts to friends, and %s isn't one yet", other.Name)
+	}
+
+	if item.Kind == shop.KindToy {
+		owned, err := inventory.Items(ctx, other.ID)
+		if err != nil {
+			log.Error("Error loading inventory", "user_id", other.ID, "error", err)
+			return "", fmt.Errorf("could not send the gift")
+		}
+		if owned[item.ID] > 0 {
+			return "", fmt.Errorf("%s already has a %s", other.Name, strings.ToLower(item.Name))
+		}
+	}
+
+	gift := &models.Gift{
+		FromUserID: userID,
+		ToUserID:   other.ID,
+		ItemID:     item.ID,
+		CreatedAt:  time.Now(),
+	}
+	if err := gifts.Send(ctx, gift); err != nil {
+		if errors.Is(err, repo.ErrOutOfStock) {
+			return "", fmt.Errorf("you don't have any %s to give", strings.ToLower(item.Name))
+		}
+		log.Error("Error sending gift", "user_id", userID, "friend_id", other.ID, "item", item.ID, "error", err)
+		return "", fmt.Errorf("could not send the gift")
+	}
+	log.Info("Sent gift", "user_id", userID, "friend_id", other.ID, "item", item.ID)
+
+	return fmt.Sprintf("You sent %s %s %s, they'll get it the next time they connect.",
+		other.Name, item.Emoji, strings.ToLower(item.Name)), nil
+}
+
+// loadFriends returns the friends and friend requests of a player, with the
+// living pets of each friend and whether they are online
+func loadFriends(ctx context.Context, friendships repo.FriendStore, pets repo.PetStore, sessions *Registry, userID int) ([]views.FriendEntry, error) {
+	list, err := friendships.List(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	online := make(map[string]bool)
+	for _, player := range sessions.Online() {
+		online[player.PublicKey] = true
+	}
+
+	entries := make([]views.FriendEntry, len(list))
+	for i, f := range list {
+		entries[i] = views.FriendEntry{
+			Name:     f.Name,
+			Pending:  !f.AcceptedAt.Valid,
+			Incoming: f.Incoming,
+		}
+		if !f.AcceptedAt.Valid {
+			continue
+		}
+
+		entries[i].Online = online[f.PublicKey]
+		if f.LastSeenAt.Valid {
+			entries[i].LastSeen = f.LastSeenAt.Time
+		}
+
+		entries[i].Pets, err = pets.ListAliveByParentID(ctx, f.UserID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return entries, nil
+}
+
+// friendsCommand lists the caller's friends, adds or removes one, or sends
+// one of them a gift
+func (srv *SSHServer) friendsCommand(ctx context.Context, publicKey string, name string, args []string) (string, error) {
+	switch {
+	case name == "friends" && len(args) != 0:
+		return "", fmt.Errorf("usage: friends")
+	case (name == "friend" || name == "unfriend") && len(args) != 1:
+		return "", fmt.Errorf("usage: %s <name>", name)
+	case name == "gift" && len(args) != 2:
+		return "", fmt.Errorf("usage: gift <name> <item>")
+	}
+
+	userID, err := srv.deps.Users.GetByPublicKey(ctx, publicKey)
+	if err != nil {
+		log.Error("Error finding user", "error", err)
+		return "", fmt.Errorf("could not find your account")
+	}
+	if userID == 0 {
+		return "", fmt.Errorf("you don't have a pet yet, connect with ssh to adopt one")
+	}
+
+	switch name {
+	case "friend":
+		return addFriend(ctx, srv.deps.Users, srv.deps.Friends, userID, args[0])
+	case "unfriend":
+		return removeFriend(ctx, srv.deps.Users, srv.deps.Friends, userID, args[0])
+	case "gift":
+		return sendGift(ctx, srv.deps.Users, srv.deps.Friends, srv.deps.Gifts, srv.deps.Actions.Inventory(), userID, args[0], args[1])
+	}
+
+	friends, err := loadFriends(ctx, srv.deps.Friends, srv.deps.Pets, srv.deps.Sessions, userID)
+	if err != nil {
+		log.Error("Error loading friends", "user_id", userID, "error", err)
+		return "", fmt.Errorf("could not load your friends")
+	}
+
+	var sb strings.Builder
+	sb.WriteString("Friends")
+	if len(friends) == 0 || friends[0].Pending {
+		sb.WriteString("\n  No friends yet, add someone with `friend <name>`.")
+	}
+	for i, f := range friends {
+		if f.Pending && (i == 0 || !friends[i-1].Pending) {
+			sb.WriteString("\n\nFriend requests")
+		}
+
+		fmt.Fprintf(&sb, "\n  %-16s ", f.Name)
+		switch {
+		case f.Pending && f.Incoming:
+			fmt.Fprintf(&sb, "wants to be your friend, accept with `friend %s`", f.Name)
+		case f.Pending:
+			sb.WriteString("hasn't answered yet")
+		default:
+			fmt.Fprintf(&sb, "%-22s", views.FriendStatus(f))
+			var pets []string
+			for _, p := range f.Pets {
+				pets = append(pets, p.Name+": "+views.GetPetState(p))
+			}
+			if len(pets) == 0 {
+				pets = append(pets, "no living pets")
+			}
+			sb.WriteString(" " + strings.Join(pets, ", "))
+		}
+	}
+
+	return sb.String(), nil
+}
+
+// markSeen records that the player with the given key is around
+func (srv *SSHServer) markSeen(publicKey string) {
+	ctx := context.Background()
+
+	userID, err := srv.deps.Users.GetByPublicKey(ctx, publicKey)
+	if err != nil {
+		log.Error("Error finding user", "error", err)
+		return
+	}
+	if userID == 0 {
+		return
+	}
+
+	if err := srv.deps.Users.SetLastSeen(ctx, userID, time.Now()); err != nil {
+		log.Error("Error updating last seen", "user_id", userID, "error", err)
+	}
+}
+
+// showFriends switches the UI to the player's friends
+func (ui *UI) showFriends() {
+	ui.friends = petui.NewFriends(nil, nil, ui.width, ui.height)
+	ui.refreshFriends("", nil)
+}
+
+// refreshFriends reloads the friends screen and shows how the last change
+// went
+func (ui *UI) refreshFriends(message string, err error) {
+	ctx := context.Background()
+	userID := ui.ownPet().Parent.ID
+
+	if err != nil {
+		message = err.Error()
+	}
+
+	friends, loadErr := loadFriends(ctx, ui.friendships, ui.pets, ui.sessions, userID)
+	if loadErr != nil {
+		log.Error("Error loading friends", "user_id", userID, "error", loadErr)
+	}
+
+	owned, loadErr := ui.inventory.Items(ctx, userID)
+	if loadErr != nil {
+		log.Error("Error loading inventory", "error", loadErr)
+	}
+
+	ui.friends.SetFriends(friends, owned, message, err != nil)
+}
+
+// deliverGifts adds the gifts sent to the player while they were away to
+// their inventory and tells them on the pet screen
+func (ui *UI) deliverGifts(userID int) {
+	if userID == 0 {
+		return
+	}
+
+	delivered, err := ui.gifts.Deliver(context.Background(), userID, time.Now())
+	if err != nil {
+		log.Error("Error delivering gifts", "user_id", userID, "error", err)
+		return
+	}
+
+	var gifts []petui.Gift
+	for _, g := range delivered {
+		item, ok := shop.Get(g.ItemID)
+		if !ok {
+			log.Warn("Delivered unknown item", "gift_id", g.ID, "item", g.ItemID)
+			continue
+		}
+		gifts = append(gifts, petui.Gift{From: g.FromName, Item: item})
+	}
+
+	if len(gifts) == 0 {
+		return
+	}
+
+	log.Info("Delivered gifts", "user_id", userID, "count", len(gifts))
+	ui.petUI.Update(petui.GiftsReceivedMsg{Gifts: gifts})
+}
+
+// updateFriends handles messages while the friends screen is shown
+func (ui *UI) updateFriends(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	ctx := context.Background()
+
+	switch msg := msg.(type) {
+	case timeMsg:
+		ui.time = time.Time(msg)
+
+	case tea.WindowSizeMsg:
+		ui.height = msg.Height
+		ui.width = msg.Width
+		_, cmd = ui.friends.Update(msg)
+		ui.petUI.Update(msg)
+
+	case petui.AddFriendMsg:
+		ui.refreshFriends(addFriend(ctx, ui.users, ui.friendships, ui.ownPet().Parent.ID, msg.Name))
+
+	case petui.RemoveFriendMsg:
+		ui.refreshFriends(removeFriend(ctx, ui.users, ui.friendships, ui.ownPet().Parent.ID, msg.Name))
+
+	case petui.SendGiftMsg:
+		ui.refreshFriends(sendGift(ctx, ui.users, ui.friendships, ui.gifts, ui.inventory, ui.ownPet().Parent.ID, msg.To, msg.Item.ID))
+
+	case petui.CloseFriendsMsg:
+		ui.friends = nil
+		if petUIModel, ok := ui.petUI.(*petui.PetUI); ok {
+			petUIModel.RefreshInventory()
+		}
+
+	case petui.FrameMsg:
+		// Keep the pet's ticker running behind the friends screen
+		ui.petUI, cmd = ui.petUI.Update(msg)
+
+	default:
+		_, cmd = ui.friends.Update(msg)
+	}
+
+	return ui, cmd
+}
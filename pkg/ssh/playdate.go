@@ -0,0 +1,372 @@
+package ssh
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/log"
+	"github.com/kirkegaard/terminal-pet/pkg/actions"
+	"github.com/kirkegaard/terminal-pet/pkg/pet"
+	"github.com/kirkegaard/terminal-pet/pkg/playdate"
+	petui "github.com/kirkegaard/terminal-pet/pkg/ui"
+)
+
+// playdateJoinTimeout is how long an accepted invitation waits for the
+// playdate to start
+const playdateJoinTimeout = 10 * time.Second
+
+// ownPet returns the player's pet as it is on screen
+func (ui *UI) ownPet() *pet.Pet {
+	if petUIModel, ok := ui.petUI.(*petui.PetUI); ok {
+		return petUIModel.GetPet()
+	}
+
+	return ui.currentPet
+}
+
+// showPlaydatePicker switches the UI to inviting another online player
+func (ui *UI) showPlaydatePicker() {
+	ui.playmates = nil
+	var names []string
+	for _, player := range ui.sessions.Online() {
+		if player.PublicKey == ui.publicKey {
+			continue
+		}
+		ui.playmates = append(ui.playmates, player)
+		names = append(names, player.Name)
+	}
+
+	ui.playdatePicker = petui.NewPlaydatePicker(names, ui.width, ui.height)
+}
+
+// invitePlaymate invites an online player to a playdate
+func (ui *UI) invitePlaymate(index int) {
+	if index < 0 || index >= len(ui.playmates) {
+		return
+	}
+	mate := ui.playmates[index]
+
+	invite := petui.PlaydateInviteMsg{
+		From:    ui.parentName,
+		FromKey: ui.publicKey,
+		Pet:     playdate.Snapshot(ui.ownPet()),
+	}
+	if !ui.sessions.Send(mate.PublicKey, invite) {
+		ui.playdatePicker.ShowMessage(mate.Name + " is no longer online")
+		return
+	}
+
+	log.Info("Invited to playdate", "from", ui.parentName, "to", mate.Name)
+
+	ui.waitingFor = mate.PublicKey
+	ui.playdatePicker.SetWaiting(mate.Name)
+}
+
+// busy reports whether the player is in the middle of something an
+// invitation shouldn't interrupt
+func (ui *UI) busy() bool {
+	if petUIModel, ok := ui.petUI.(*petui.PetUI); !ok || petUIModel.InGame() {
+		return true
+	}
+
+	joining := ui.joining != "" && time.Since(ui.joiningAt) < playdateJoinTimeout
+
+	return joining || ui.playdate != nil || ui.playdateInvite != nil || ui.playdatePicker != nil || ui.visit != nil
+}
+
+// handlePlaydateMsg handles the messages other sessions send about
+// playdates, whichever screen is shown
+func (ui *UI) handlePlaydateMsg(msg tea.Msg) (tea.Cmd, bool) {
+	switch msg := msg.(type) {
+	case petui.PlaydateInviteMsg:
+		ui.receiveInvite(msg)
+		return nil, true
+
+	case petui.PlaydateAnswerMsg:
+		return ui.receiveAnswer(msg), true
+
+	case petui.PlaydateStartedMsg:
+		return ui.joinPlaydate(msg), true
+
+	case petui.PlaydateStateMsg:
+		if ui.playdate == nil {
+			return nil, true
+		}
+		_, cmd := ui.playdate.Update(msg)
+		return cmd, true
+	}
+
+	return nil, false
+}
+
+// receiveInvite asks the player whether to accept an invitation, unless
+// they can't right now
+func (ui *UI) receiveInvite(msg petui.PlaydateInviteMsg) {
+	reason := ""
+	if ui.busy() {
+		reason = ui.parentName + " is busy right now"
+	} else if err := actions.Check(ui.ownPet(), actions.Play); err != nil {
+		reason = ui.parentName + "'s pet can't play right now"
+	}
+
+	if reason != "" {
+		ui.sessions.Send(msg.FromKey, petui.PlaydateAnswerMsg{From: ui.parentName, FromKey: ui.publicKey, Reason: reason})
+		return
+	}
+
+	ui.invite = &msg
+	ui.playdateInvite = petui.NewPlaydateInvite(msg.From, msg.Pet.Name, ui.width, ui.height)
+}
+
+// answerInvite sends the player's answer to an invitation
+func (ui *UI) answerInvite(accepted bool) {
+	invite := ui.invite
+	ui.invite = nil
+	ui.playdateInvite = nil
+	if invite == nil {
+		return
+	}
+
+	answer := petui.PlaydateAnswerMsg{
+		From:     ui.parentName,
+		FromKey:  ui.publicKey,
+		Accepted: accepted,
+	}
+	if accepted {
+		answer.Pet = playdate.Snapshot(ui.ownPet())
+	} else {
+		answer.Reason = ui.parentName + " said no"
+	}
+
+	if ui.sessions.Send(invite.FromKey, answer) && accepted {
+		ui.joining = invite.FromKey
+		ui.joiningAt = time.Now()
+	}
+}
+
+// receiveAnswer starts the playdate if the invited player accepted
+func (ui *UI) receiveAnswer(msg petui.PlaydateAnswerMsg) tea.Cmd {
+	// The host gave up waiting before the playdate started
+	if msg.FromKey == ui.joining && !msg.Accepted {
+		ui.joining = ""
+		return nil
+	}
+
+	if ui.waitingFor == "" || msg.FromKey != ui.waitingFor {
+		if msg.Accepted {
+			ui.sessions.Send(msg.FromKey, petui.PlaydateAnswerMsg{From: ui.parentName, FromKey: ui.publicKey, Reason: ui.parentName + " stopped waiting"})
+		}
+		return nil
+	}
+
+	ui.waitingFor = ""
+	if !msg.Accepted {
+		if ui.playdatePicker != nil {
+			ui.playdatePicker.ShowMessage(msg.Reason)
+		}
+		return nil
+	}
+
+	host, guest := ui.publicKey, msg.FromKey
+	pd := playdate.New(
+		[2]string{ui.parentName, msg.From},
+		[2]*pet.Pet{ui.ownPet(), msg.Pet},
+		func(s playdate.State) {
+			ui.sessions.Send(host, petui.PlaydateStateMsg{State: s})
+			ui.sessions.Send(guest, petui.PlaydateStateMsg{State: s})
+		},
+	)
+
+	if !ui.sessions.Send(guest, petui.PlaydateStartedMsg{Playdate: pd, Side: playdate.Guest}) {
+		if ui.playdatePicker != nil {
+			ui.playdatePicker.ShowMessage(msg.From + " is no longer online")
+		}
+		return nil
+	}
+
+	log.Info("Playdate started", "host", ui.parentName, "guest", msg.From)
+
+	ui.playdatePicker = nil
+
+	return ui.openPlaydate(pd, playdate.Host)
+}
+
+// joinPlaydate joins the playdate of an accepted invitation
+func (ui *UI) joinPlaydate(msg petui.PlaydateStartedMsg) tea.Cmd {
+	if ui.joining == "" || time.Since(ui.joiningAt) >= playdateJoinTimeout || ui.playdate != nil {
+		msg.Playdate.Leave(msg.Side)
+		return nil
+	}
+
+	ui.joining = ""
+
+	return ui.openPlaydate(msg.Playdate, msg.Side)
+}
+
+// openPlaydate switches the UI to one side of a playdate
+func (ui *UI) openPlaydate(pd *playdate.Playdate, side int) tea.Cmd {
+	ui.playdate = petui.NewPlaydate(pd, side, ui.width, ui.height)
+
+	if !ui.hasRoom() {
+		pd.SetFull(side)
+	}
+
+	// Disconnecting sends the pet home
+	go func() {
+		<-ui.ctx.Done()
+		pd.Leave(side)
+	}()
+
+	return ui.playdate.Init()
+}
+
+// hasRoom reports whether the player can have another pet
+func (ui *UI) hasRoom() bool {
+	own := ui.ownPet()
+
+	living, err := ui.pets.ListAliveByParentID(context.Background(), own.Parent.ID)
+	if err != nil {
+		log.Error("Error counting pets", "user_id", own.Parent.ID, "error", err)
+		return false
+	}
+
+	return len(living) < ui.maxPets
+}
+
+// haveBaby has the player's pet have a baby with the pet of the other
+// player of the playdate. The baby joins the pets of the player who asked
+// for it first, the other player's session only records that their pet
+// became a parent.
+func (ui *UI) haveBaby(mate *pet.Pet, keep bool) {
+	own := ui.ownPet()
+
+	// The player's own pet may have changed since the playdate started
+	if err := own.CanBreedWith(mate, ui.sim.Now()); err != nil {
+		ui.playdate.ShowMessage(err.Error())
+		return
+	}
+
+	if !keep {
+		name := pet.BabyName(mate.Name, own.Name)
+		own.RecordBaby(mate, name, ui.sim.Now())
+		ui.syncPetState()
+
+		ui.playdate.ShowMessage(fmt.Sprintf("%s and %s had a baby! %s is staying with %s.", own.Name, mate.Name, name, mate.Parent.Name))
+		return
+	}
+
+	ctx := context.Background()
+	baby := ui.sim.Breed(own, mate, pet.BabyName(own.Name, mate.Name), own.Parent)
+
+	if err := ui.pets.Create(ctx, baby); err != nil {
+		log.Error("Error saving baby", "error", err)
+		ui.playdate.ShowMessage("Something went wrong, try again later")
+		return
+	}
+
+	if err := ui.lineage.AddParents(ctx, baby.ID, own.ID, mate.ID); err != nil {
+		log.Error("Error saving lineage", "pet_id", baby.ID, "error", err)
+	}
+
+	ui.syncPetState()
+
+	log.Info("Pets had a baby", "baby", baby.Name, "parents", []string{own.Name, mate.Name}, "owner", ui.parentName)
+
+	ui.playdate.ShowMessage(fmt.Sprintf("%s and %s had a baby! Say hello to %s, who's waiting for you next time you pick a pet.", own.Name, mate.Name, baby.Name))
+}
+
+// updatePlaydatePicker handles messages while inviting someone
+func (ui *UI) updatePlaydatePicker(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+	case timeMsg:
+		ui.time = time.Time(msg)
+
+	case tea.WindowSizeMsg:
+		ui.height = msg.Height
+		ui.width = msg.Width
+		_, cmd = ui.playdatePicker.Update(msg)
+		ui.petUI.Update(msg)
+
+	case petui.InvitePlaymateMsg:
+		ui.invitePlaymate(msg.Index)
+
+	case petui.ClosePlaydateMsg:
+		ui.playdatePicker = nil
+		ui.waitingFor = ""
+
+	case petui.FrameMsg:
+		// Keep the pet's ticker running while inviting
+		ui.petUI, cmd = ui.petUI.Update(msg)
+
+	default:
+		_, cmd = ui.playdatePicker.Update(msg)
+	}
+
+	return ui, cmd
+}
+
+// updatePlaydateInvite handles messages while an invitation is shown
+func (ui *UI) updatePlaydateInvite(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+	case timeMsg:
+		ui.time = time.Time(msg)
+
+	case tea.WindowSizeMsg:
+		ui.height = msg.Height
+		ui.width = msg.Width
+		_, cmd = ui.playdateInvite.Update(msg)
+		ui.petUI.Update(msg)
+
+	case petui.AnswerInviteMsg:
+		ui.answerInvite(msg.Accepted)
+
+	case petui.FrameMsg:
+		// Keep the pet's ticker running behind the invitation
+		ui.petUI, cmd = ui.petUI.Update(msg)
+
+	default:
+		_, cmd = ui.playdateInvite.Update(msg)
+	}
+
+	return ui, cmd
+}
+
+// updatePlaydate handles messages during a playdate
+func (ui *UI) updatePlaydate(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+	case timeMsg:
+		ui.time = time.Time(msg)
+
+	case tea.WindowSizeMsg:
+		ui.height = msg.Height
+		ui.width = msg.Width
+		_, cmd = ui.playdate.Update(msg)
+		ui.petUI.Update(msg)
+
+	case petui.ClosePlaydateMsg:
+		ui.playdate = nil
+
+	case petui.PlaydateFinishedMsg:
+		ui.petUI, cmd = ui.petUI.Update(msg)
+
+	case petui.PlaydateBabyMsg:
+		ui.haveBaby(msg.Mate, msg.Keep)
+
+	case petui.FrameMsg:
+		// Keep the pet's ticker running during the playdate
+		ui.petUI, cmd = ui.petUI.Update(msg)
+
+	default:
+		_, cmd = ui.playdate.Update(msg)
+	}
+
+	return ui, cmd
+}
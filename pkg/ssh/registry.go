@@ -0,0 +1,209 @@
+package ssh
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kirkegaard/terminal-pet/pkg/actions"
+	"github.com/kirkegaard/terminal-pet/pkg/pet"
+	"github.com/kirkegaard/terminal-pet/pkg/world"
+)
+
+var (
+	_ actions.Sessions = (*Registry)(nil)
+	_ world.Sessions   = (*Registry)(nil)
+)
+
+// performTimeout is how long an action handed to a session waits for the
+// session to perform it
+const performTimeout = 5 * time.Second
+
+// OnlinePlayer is a player with an interactive session
+type OnlinePlayer struct {
+	PublicKey string
+	Name      string
+}
+
+// registeredSession is the interactive session of a player and the ID of
+// the pet they are playing, or 0
+type registeredSession struct {
+	player OnlinePlayer
+	send   func(tea.Msg)
+	petID  int
+}
+
+// performMsg asks a session to perform an action on the pet it is playing
+// and send the result on reply
+type performMsg struct {
+	petID  int
+	action string
+	arg    string
+	reply  chan performResult
+}
+
+// performResult is the outcome of a performMsg. ok is false if the session
+// wasn't playing the pet after all.
+type performResult struct {
+	out string
+	pet *pet.Pet
+	ok  bool
+	err error
+}
+
+// Registry keeps track of the interactive sessions of connected players, so
+// their programs can send each other messages. Every connection is a session
+// of its own, and a pet can only be played in one session at a time. A
+// player connected more than once is reached through their newest session.
+type Registry struct {
+	mu       sync.Mutex
+	lastID   int
+	sessions map[int]*registeredSession
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		sessions: make(map[int]*registeredSession),
+	}
+}
+
+// Register adds a session of a player, whose program receives messages
+// through send, and returns its ID. The returned function removes it again.
+func (r *Registry) Register(publicKey string, name string, send func(tea.Msg)) (id int, unregister func()) {
+	session := &registeredSession{
+		player: OnlinePlayer{PublicKey: publicKey, Name: name},
+		send:   send,
+	}
+
+	r.mu.Lock()
+	r.lastID++
+	id = r.lastID
+	r.sessions[id] = session
+	r.mu.Unlock()
+
+	return id, func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+
+		delete(r.sessions, id)
+	}
+}
+
+// SetPet records which pet is played in the session with the given ID, 0
+// for none. It reports false and leaves the session as it was if another
+// session is playing the pet already.
+func (r *Registry) SetPet(id int, petID int) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for otherID, other := range r.sessions {
+		if otherID != id && petID != 0 && other.petID == petID {
+			return false
+		}
+	}
+
+	if session, ok := r.sessions[id]; ok {
+		session.petID = petID
+	}
+
+	return true
+}
+
+// playing returns the session playing the pet with the given ID, or nil
+func (r *Registry) playing(petID int) *registeredSession {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, session := range r.sessions {
+		if petID != 0 && session.petID == petID {
+			return session
+		}
+	}
+
+	return nil
+}
+
+// Playing reports whether the pet with the given ID is being played in a
+// session. It implements world.Sessions.
+func (r *Registry) Playing(petID int) bool {
+	return r.playing(petID) != nil
+}
+
+// Perform hands an action to the session playing the pet, so it isn't lost
+// when the session saves its own copy of the pet. It implements
+// actions.Sessions.
+func (r *Registry) Perform(ctx context.Context, petID int, action string, arg string) (string, *pet.Pet, bool, error) {
+	session := r.playing(petID)
+	if session == nil {
+		return "", nil, false, nil
+	}
+
+	reply := make(chan performResult, 1)
+	go session.send(performMsg{petID: petID, action: action, arg: arg, reply: reply})
+
+	select {
+	case result := <-reply:
+		return result.out, result.pet, result.ok, result.err
+	case <-ctx.Done():
+		return "", nil, true, ctx.Err()
+	case <-time.After(performTimeout):
+		return "", nil, true, errors.New("the session playing the pet didn't answer, try again")
+	}
+}
+
+// Online returns the players with an interactive session, by name
+func (r *Registry) Online() []OnlinePlayer {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	seen := make(map[string]bool, len(r.sessions))
+	players := make([]OnlinePlayer, 0, len(r.sessions))
+	for _, s := range r.sessions {
+		if seen[s.player.PublicKey] {
+			continue
+		}
+		seen[s.player.PublicKey] = true
+		players = append(players, s.player)
+	}
+
+	sort.Slice(players, func(i, j int) bool {
+		return strings.ToLower(players[i].Name) < strings.ToLower(players[j].Name)
+	})
+
+	return players
+}
+
+// Send passes a message to the program of a player and reports whether they
+// are online. The message is delivered in the background, as a program
+// sending to itself would otherwise wait on itself.
+func (r *Registry) Send(publicKey string, msg tea.Msg) bool {
+	r.mu.Lock()
+	session := r.newest(publicKey)
+	r.mu.Unlock()
+
+	if session == nil {
+		return false
+	}
+
+	go session.send(msg)
+
+	return true
+}
+
+// newest returns the newest session of the player with the given public key,
+// or nil if they aren't online. r.mu must be held.
+func (r *Registry) newest(publicKey string) *registeredSession {
+	var newest *registeredSession
+	newestID := 0
+	for id, session := range r.sessions {
+		if session.player.PublicKey == publicKey && id > newestID {
+			newest, newestID = session, id
+		}
+	}
+
+	return newest
+}
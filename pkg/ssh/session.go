@@ -3,9 +3,7 @@ package ssh
 import (
 	"context"
 	"fmt"
-	"math"
 	"math/rand"
-	"reflect"
 	"strings"
 	"sync"
 	"time"
@@ -14,8 +12,6 @@ import (
 	"github.com/charmbracelet/log"
 	"github.com/charmbracelet/ssh"
 	bm "github.com/charmbracelet/wish/bubbletea"
-	"github.com/kirkegaard/terminal-pet/pkg/db"
-	"github.com/kirkegaard/terminal-pet/pkg/db/repo"
 	"github.com/kirkegaard/terminal-pet/pkg/pet"
 	petui "github.com/kirkegaard/terminal-pet/pkg/ui"
 )
@@ -25,9 +21,11 @@ type PetSaveMsg struct {
 	PublicKey string
 }
 
-func SessionHandler(s ssh.Session) *tea.Program {
+func (srv *SSHServer) SessionHandler(s ssh.Session) *tea.Program {
+	// Exec requests are handled by CommandMiddleware, except for visits
 	pty, _, active := s.Pty()
-	if !active {
+	visiting, isVisit := interactiveVisit(s)
+	if !active || (len(s.Command()) > 0 && !isVisit) {
 		return nil
 	}
 
@@ -37,18 +35,6 @@ func SessionHandler(s ssh.Session) *tea.Program {
 
 	log.Debug("Session context type", "type", fmt.Sprintf("%T", sessionCtx))
 
-	var dbx *db.DB = db.GetInstance()
-	if dbx == nil {
-		log.Error("Global database instance not available")
-		fmt.Fprintln(s, "Error: Database connection is not available")
-		return nil
-	}
-
-	log.Info("Using global database instance")
-
-	petRepo := repo.NewPetRepository(dbx)
-	log.Debug("Created pet repository", "repo", petRepo != nil)
-
 	publicKey, ok := sessionCtx.Value(string(PublicKeyKey)).(string)
 	if !ok || publicKey == "" {
 		log.Warn("No public key found in session context", "user", s.User())
@@ -57,55 +43,19 @@ func SessionHandler(s ssh.Session) *tea.Program {
 
 	log.Debug("Using public key", "key", publicKey)
 
-	existingPet, err := petRepo.FindByParentPublicKey(context.Background(), publicKey)
-	if err != nil {
-		log.Error("Error finding pet", "error", err)
-	}
+	srv.markSeen(publicKey)
 
 	renderer := bm.MakeRenderer(s)
 
-	var ui *UI
-	if existingPet != nil {
-		log.Info("Found existing pet", "name", existingPet.Name, "user", s.User())
-
-		log.Info("Pet health status",
-			"health", existingPet.Health,
-			"is_dead", existingPet.IsDead(),
-			"is_dead_check", existingPet.Health <= 0)
+	sim := pet.NewSimulator(pet.SystemClock, rand.New(rand.NewSource(time.Now().UnixNano())))
 
-		timePassed := time.Since(existingPet.LastVisit)
-		log.Info("Time since last visit", "duration", timePassed.String())
+	ui := NewUI(sessionCtx, renderer, pty.Window.Width, pty.Window.Height, sim, srv.deps, publicKey, s.User())
 
-		if !existingPet.IsDead() {
-			simulateTimePassed(existingPet, timePassed)
-		}
-
-		ui = NewUI(context.Background(), renderer, pty.Window.Width, pty.Window.Height, existingPet, publicKey)
-
-		if existingPet.Health <= 0 {
-			log.Info("Pet is dead on connection, showing game over screen", "name", existingPet.Name)
-
-			if petUIModel, ok := ui.petUI.(*petui.PetUI); ok {
-				petUIModel.SetGameOver(true)
-			} else {
-				petUIValue := reflect.ValueOf(ui.petUI)
-				setGameOverMethod := petUIValue.MethodByName("SetGameOver")
-				if setGameOverMethod.IsValid() {
-					setGameOverMethod.Call([]reflect.Value{reflect.ValueOf(true)})
-				} else {
-					log.Error("Could not find SetGameOver method")
-				}
-			}
-		}
+	if isVisit {
+		log.Info("Visiting player", "user", s.User(), "name", visiting)
+		ui.ShowVisit(visiting)
 	} else {
-		log.Info("Creating new pet for user", "user", s.User())
-
-		parent := pet.NewParent(0, s.User())
-		newPet := pet.NewPet(fmt.Sprintf("%s's pet", s.User()), time.Now(), parent)
-		newPet.Happiness = 80
-		newPet.Health = 100
-
-		ui = NewUI(context.Background(), renderer, pty.Window.Width, pty.Window.Height, newPet, publicKey)
+		srv.showOwnPets(ui, sim, publicKey, s.User())
 	}
 
 	ctx, cancel := context.WithCancel(context.Background())
@@ -122,12 +72,24 @@ func SessionHandler(s ssh.Session) *tea.Program {
 
 	p := tea.NewProgram(ui, opts...)
 
+	// Other sessions can invite the player to playdates, visitors have no
+	// pet to bring
+	unregister := func() {}
+	if !isVisit {
+		ui.sessionID, unregister = srv.deps.Sessions.Register(publicKey, s.User(), p.Send)
+		if ui.currentPet != nil && !srv.deps.Sessions.SetPet(ui.sessionID, ui.currentPet.ID) {
+			log.Info("Pet is being played in another session", "id", ui.currentPet.ID)
+		}
+	}
+
 	// Add a finalizer to handle shutdown cleanly
 	shutdownOnce := &sync.Once{}
 	shutdown := func() {
 		shutdownOnce.Do(func() {
 			log.Debug("Running clean shutdown")
 
+			unregister()
+
 			// Cancel the context first to signal all goroutines to stop
 			cancel()
 
@@ -137,13 +99,10 @@ func SessionHandler(s ssh.Session) *tea.Program {
 			// Final state save
 			if ui.currentPet != nil {
 				ui.syncPetState()
-				err := petRepo.Save(context.Background(), ui.currentPet, ui.publicKey)
-				if err != nil {
-					log.Error("Error saving final pet state", "error", err)
-				} else {
-					log.Info("Final pet state saved", "name", ui.currentPet.Name)
-				}
 			}
+
+			// New players only have an account once their pet was saved
+			srv.markSeen(publicKey)
 		})
 	}
 
@@ -182,15 +141,8 @@ func SessionHandler(s ssh.Session) *tea.Program {
 		for {
 			select {
 			case <-ticker.C:
-				if ui.currentPet != nil {
-					ui.syncPetState()
-					err := petRepo.Save(context.Background(), ui.currentPet, ui.publicKey)
-					if err != nil {
-						log.Error("Error saving pet", "error", err)
-					} else {
-						log.Debug("Pet state auto-saved", "name", ui.currentPet.Name)
-					}
-				}
+				// The UI saves the pet on its own goroutine
+				p.Send(saveMsg{})
 			case <-ctx.Done():
 				log.Debug("Auto-save routine stopped")
 				return
@@ -219,114 +171,87 @@ func SessionHandler(s ssh.Session) *tea.Program {
 	return p
 }
 
-func getContextKeys(ctx context.Context) []string {
-	keys := []string{}
+// showOwnPets shows the player's living pets to pick from, the game over
+// screen if their newest pet died while they were away, or the adoption
+// screen for new players
+func (srv *SSHServer) showOwnPets(ui *UI, sim *pet.Simulator, publicKey string, user string) {
+	petRepo := srv.deps.Pets
 
-	if ctx.Value(db.ContextKeyDB) != nil {
-		keys = append(keys, "db.ContextKeyDB")
+	livingPets, err := srv.findLivingPets(publicKey)
+	if err != nil {
+		log.Error("Error finding pets", "error", err)
 	}
 
-	if ctx.Value(string(PublicKeyKey)) != nil {
-		keys = append(keys, "PublicKeyKey")
+	for _, p := range livingPets {
+		steps := sim.Update(p)
+		log.Info("Time simulation results",
+			"id", p.ID,
+			"name", p.Name,
+			"steps", steps,
+			"hunger", p.Hunger,
+			"happiness", p.Happiness,
+			"health", p.Health,
+			"is_sick", p.IsSick,
+			"has_pooped", p.HasPooped)
 	}
 
-	for _, key := range []string{"user", "remote_addr", "session_id", "client_version", "server_version"} {
-		if ctx.Value(key) != nil {
-			keys = append(keys, key)
-		}
-	}
+	if len(livingPets) > 0 {
+		log.Info("Found existing pets", "count", len(livingPets), "user", user)
+		ui.ShowPicker(livingPets, srv.maxPets)
+	} else if existingPet, err := petRepo.FindByParentPublicKey(context.Background(), publicKey); err == nil && existingPet != nil {
+		// The newest pet died while the player was away
+		log.Info("Pet is dead on connection, showing game over screen", "name", existingPet.Name)
 
-	return keys
-}
+		ui.ShowPet(existingPet)
 
-func simulateTimePassed(p *pet.Pet, duration time.Duration) {
-	hours := duration.Hours()
+		if petUIModel, ok := ui.petUI.(*petui.PetUI); ok {
+			petUIModel.SetGameOver(true)
+		}
+	} else {
+		if err != nil {
+			log.Error("Error finding pet", "error", err)
+		}
 
-	log.Info("Simulating time passage", "hours", hours)
+		// New players pick a name and species for their first pet
+		log.Info("No pets found, showing adoption", "user", user)
 
-	// Cap at 7 days for simulation
-	simulatedHours := hours
-	if simulatedHours > 168 {
-		log.Info("Capping simulation at 7 days", "actual_hours", hours)
-		simulatedHours = 168
+		ui.ShowPicker(nil, srv.maxPets)
 	}
+}
 
-	// Calculate days for easier reasoning
-	days := simulatedHours / 24
-
-	hungerPerDay := 35.0
-	hungerIncrease := int(days * hungerPerDay)
-	p.Hunger += hungerIncrease
-	if p.Hunger > 100 {
-		p.Hunger = 100
+// findLivingPets returns the living pets of the player with the given key
+func (srv *SSHServer) findLivingPets(publicKey string) ([]*pet.Pet, error) {
+	userID, err := srv.deps.Users.GetByPublicKey(context.Background(), publicKey)
+	if err != nil || userID == 0 {
+		return nil, err
 	}
 
-	happinessLossPerDay := 35.0
-	happinessDecrease := int(days * happinessLossPerDay)
-	p.Happiness -= happinessDecrease
-	if p.Happiness < 0 {
-		p.Happiness = 0
-	}
+	return srv.deps.Pets.ListAliveByParentID(context.Background(), userID)
+}
 
-	healthDecrease := 0
-	if days < 2 {
-		healthDecrease = int(days * 5)
-	} else if days < 4 {
-		healthDecrease = 10 + int((days-2)*10)
-	} else if days < 5 {
-		healthDecrease = 30 + int((days-4)*20)
-	} else if days < 6 {
-		healthDecrease = 50 + int((days-5)*30)
-	} else {
-		healthDecrease = 80 + int((days-6)*40)
-	}
+// newPlayerPet creates a fresh pet of the given species for a player, born
+// now according to the simulator
+func newPlayerPet(sim *pet.Simulator, name string, species string, parentName string) *pet.Pet {
+	parent := pet.NewParent(0, parentName)
+	newPet := sim.Adopt(name, species, parent)
+	newPet.Happiness = 80
+	newPet.Health = 100
 
-	if p.Hunger > 80 {
-		// No penalty for first 3 days
-		hungerDays := math.Max(0, days-3)
-		healthDecrease += int(hungerDays * 5)
-	}
+	return newPet
+}
 
-	// Poop guaranteed after 2 days
-	if !p.HasPooped {
-		if days > 2 || rand.Float64() < (days/2) {
-			p.HasPooped = true
-		}
-	}
+func getContextKeys(ctx context.Context) []string {
+	keys := []string{}
 
-	if p.HasPooped {
-		poopDays := math.Max(0, days-2)
-		healthDecrease += int(poopDays * 3)
+	if ctx.Value(string(PublicKeyKey)) != nil {
+		keys = append(keys, "PublicKeyKey")
 	}
 
-	if !p.IsSick {
-		sickChance := days / 3
-		if days > 3 || rand.Float64() < sickChance {
-			p.IsSick = true
+	for _, key := range []string{"user", "remote_addr", "session_id", "client_version", "server_version"} {
+		if ctx.Value(key) != nil {
+			keys = append(keys, key)
 		}
 	}
 
-	if p.IsSick {
-		sickDays := math.Max(0, days-3)
-		healthDecrease += int(sickDays * 7)
-	}
-
-	// Kill after 7 days absent
-	if hours > 168 {
-		healthDecrease = 100
-	}
-
-	p.Health -= healthDecrease
-	if p.Health < 0 {
-		p.Health = 0
-	}
-
-	log.Info("Time simulation results",
-		"hunger", p.Hunger,
-		"happiness", p.Happiness,
-		"health", p.Health,
-		"health_decrease", healthDecrease,
-		"days_absent", days,
-		"is_sick", p.IsSick,
-		"has_pooped", p.HasPooped)
+	return keys
 }
@@ -8,11 +8,11 @@ import (
 	"path/filepath"
 	// "strings"
 
+	"github.com/kirkegaard/terminal-pet/pkg/achievements"
+	"github.com/kirkegaard/terminal-pet/pkg/actions"
 	"github.com/kirkegaard/terminal-pet/pkg/config"
-	"github.com/kirkegaard/terminal-pet/pkg/db"
 	"github.com/kirkegaard/terminal-pet/pkg/db/repo"
 
-	"github.com/charmbracelet/log"
 	"github.com/charmbracelet/ssh"
 	"github.com/charmbracelet/wish"
 	bm "github.com/charmbracelet/wish/bubbletea"
@@ -25,28 +25,41 @@ var (
 	hostKeyPath = ".ssh/id_ed25519"
 )
 
+// Deps are the stores and services the SSH server and its sessions use
+type Deps struct {
+	Pets         repo.PetStore
+	Users        repo.UserStore
+	Tokens       repo.TokenStore
+	Scores       repo.ScoreStore
+	History      repo.PetEventStore
+	Stats        repo.StatStore
+	Lineage      repo.LineageStore
+	Friends      repo.FriendStore
+	Gifts        repo.GiftStore
+	Achievements *achievements.Engine
+	Actions      *actions.Service
+	Sessions     *Registry
+}
+
 type SSHServer struct {
-	server        *ssh.Server
-	petRepository *repo.PetRepository
-	db            *db.DB
-	serverCtx     context.Context
+	server    *ssh.Server
+	deps      Deps
+	maxPets   int
+	serverCtx context.Context
 }
 
-func NewSSHServer(ctx context.Context) (*SSHServer, error) {
+func NewSSHServer(ctx context.Context, deps Deps) (*SSHServer, error) {
 	var err error
 
 	cfg := config.FromContext(ctx)
-	dbx := db.FromContext(ctx)
-	if dbx == nil {
-		return nil, fmt.Errorf("database not found in context")
+	if cfg == nil {
+		return nil, fmt.Errorf("config not found in context")
 	}
 
-	petRepository := repo.NewPetRepository(dbx)
-
 	s := &SSHServer{
-		petRepository: petRepository,
-		db:            dbx,
-		serverCtx:     ctx,
+		deps:      deps,
+		maxPets:   cfg.Game.MaxPets,
+		serverCtx: ctx,
 	}
 
 	hostKeyDir := filepath.Dir(hostKeyPath)
@@ -55,9 +68,9 @@ func NewSSHServer(ctx context.Context) (*SSHServer, error) {
 	}
 
 	mw := []wish.Middleware{
-		s.withDatabaseMiddleware(),
+		s.CommandMiddleware(),
 		WithPublicKeyMiddleware(),
-		bm.MiddlewareWithProgramHandler(SessionHandler, termenv.TrueColor),
+		bm.MiddlewareWithProgramHandler(s.SessionHandler, termenv.TrueColor),
 	}
 
 	// mw = append(mw, func(h ssh.Handler) ssh.Handler {
@@ -93,24 +106,9 @@ func NewSSHServer(ctx context.Context) (*SSHServer, error) {
 	return s, nil
 }
 
-func (s *SSHServer) withDatabaseMiddleware() wish.Middleware {
-	return func(handler ssh.Handler) ssh.Handler {
-		return func(session ssh.Session) {
-			sshCtx := session.Context()
-			sshCtx.SetValue(db.ContextKeyDB, s.db)
-
-			log.Debug("Adding database to context",
-				"db_present", s.db != nil)
-
-			handler(session)
-		}
-	}
-}
-
 func (s *SSHServer) publicKeyHandler(ctx ssh.Context, key ssh.PublicKey) bool {
 	pubKeyStr := fmt.Sprintf("%s %s", key.Type(), gossh.FingerprintSHA256(key))
 	ctx.SetValue(string(PublicKeyKey), pubKeyStr)
-	ctx.SetValue(db.ContextKeyDB, s.db)
 	// log.Info("Public key auth", "user", ctx.User(), "key_fingerprint", gossh.FingerprintSHA256(key))
 	return true
 }
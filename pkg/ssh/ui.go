@@ -2,6 +2,7 @@ package ssh
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -9,46 +10,477 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/charmbracelet/log"
+	"github.com/kirkegaard/terminal-pet/pkg/achievements"
+	"github.com/kirkegaard/terminal-pet/pkg/actions"
+	"github.com/kirkegaard/terminal-pet/pkg/db/models"
 	"github.com/kirkegaard/terminal-pet/pkg/db/repo"
+	"github.com/kirkegaard/terminal-pet/pkg/events"
 	"github.com/kirkegaard/terminal-pet/pkg/pet"
+	"github.com/kirkegaard/terminal-pet/pkg/playdate"
 	petui "github.com/kirkegaard/terminal-pet/pkg/ui"
+	"github.com/kirkegaard/terminal-pet/pkg/ui/games"
 )
 
 type timeMsg time.Time
 
+// saveMsg asks the UI to save the pet being played
+type saveMsg struct{}
+
+// unlockedMsg carries an achievement unlocked by anyone on the server
+type unlockedMsg achievements.Unlocked
+
+// unlockBuffer is how many unlocks can wait for the UI before more are
+// dropped
+const unlockBuffer = 16
+
 type UI struct {
-	Renderer   *lipgloss.Renderer
-	time       time.Time
-	width      int
-	height     int
-	petUI      tea.Model
-	currentPet *pet.Pet
-	publicKey  string
-}
+	Renderer     *lipgloss.Renderer
+	time         time.Time
+	width        int
+	height       int
+	petUI        tea.Model
+	picker       *petui.PetPicker
+	graveyard    *petui.Graveyard
+	shop         *petui.Shop
+	scoreboard   *petui.Leaderboard
+	diary        *petui.Diary
+	statHistory  *petui.StatHistory
+	family       *petui.Family
+	friends      *petui.Friends
+	visit        *petui.Visit
+	currentPet   *pet.Pet
+	publicKey    string
+	parentName   string
+	pets         repo.PetStore
+	users        repo.UserStore
+	actions      *actions.Service
+	inventory    repo.InventoryStore
+	scores       repo.ScoreStore
+	history      repo.PetEventStore
+	stats        repo.StatStore
+	lineage      repo.LineageStore
+	friendships  repo.FriendStore
+	gifts        repo.GiftStore
+	achievements *achievements.Engine
+	events       *events.Bus
+	sessions     *Registry
+	unlocks      chan achievements.Unlocked
+	ctx          context.Context
+	sim          *pet.Simulator
+
+	// maxPets is how many living pets the player can have, set when they
+	// pick one
+	maxPets int
 
-func NewUI(ctx context.Context, renderer *lipgloss.Renderer, width int, height int, p *pet.Pet, publicKey string) *UI {
-	petUIModel := petui.NewPetUI(p, width, height)
+	// sessionID is the ID of the session in the registry, 0 until it is
+	// registered
+	sessionID int
 
+	// Playdates
+	playdatePicker *petui.PlaydatePicker
+	playdateInvite *petui.PlaydateInvite
+	playdate       *petui.Playdate
+	playmates      []OnlinePlayer
+	invite         *petui.PlaydateInviteMsg
+	waitingFor     string
+	joining        string
+	joiningAt      time.Time
+}
+
+// NewUI creates the session UI. Either ShowPet or ShowPicker must be called
+// before the UI is started. The UI listens for unlocked achievements on the
+// bus until the context is done.
+func NewUI(ctx context.Context, renderer *lipgloss.Renderer, width int, height int, sim *pet.Simulator, deps Deps, publicKey string, parentName string) *UI {
 	ui := &UI{
-		Renderer:   renderer,
-		width:      width,
-		height:     height,
-		time:       time.Now(),
-		petUI:      petUIModel,
-		currentPet: p,
-		publicKey:  publicKey,
+		Renderer:     renderer,
+		width:        width,
+		height:       height,
+		time:         time.Now(),
+		publicKey:    publicKey,
+		parentName:   parentName,
+		pets:         deps.Pets,
+		users:        deps.Users,
+		actions:      deps.Actions,
+		inventory:    deps.Actions.Inventory(),
+		scores:       deps.Scores,
+		history:      deps.History,
+		stats:        deps.Stats,
+		lineage:      deps.Lineage,
+		friendships:  deps.Friends,
+		gifts:        deps.Gifts,
+		achievements: deps.Achievements,
+		events:       deps.Actions.Events(),
+		sessions:     deps.Sessions,
+		unlocks:      make(chan achievements.Unlocked, unlockBuffer),
+		ctx:          ctx,
+		sim:          sim,
 	}
 
+	ui.events.SubscribeContext(ctx, ui.handleEvent)
+
 	return ui
 }
 
+// handleEvent passes unlocked achievements on to the UI. It runs on the
+// publisher's goroutine, so it never blocks and leaves it to Update to pick
+// out the player's own unlocks.
+func (ui *UI) handleEvent(ctx context.Context, e pet.Event) {
+	unlocked, ok := e.(achievements.Unlocked)
+	if !ok {
+		return
+	}
+
+	select {
+	case ui.unlocks <- unlocked:
+	default:
+		log.Warn("Dropped achievement toast", "achievement", unlocked.Achievement.ID)
+	}
+}
+
+// waitForUnlock waits for the next unlocked achievement
+func (ui *UI) waitForUnlock() tea.Cmd {
+	return func() tea.Msg {
+		select {
+		case unlocked := <-ui.unlocks:
+			return unlockedMsg(unlocked)
+		case <-ui.ctx.Done():
+			return nil
+		}
+	}
+}
+
+// showUnlock shows a toast if the player unlocked the achievement
+func (ui *UI) showUnlock(msg unlockedMsg) {
+	if ui.currentPet == nil || ui.currentPet.Parent == nil || msg.Pet.Parent == nil {
+		return
+	}
+
+	if msg.Pet.Parent.ID != ui.currentPet.Parent.ID {
+		return
+	}
+
+	log.Info("Achievement unlocked", "user_id", msg.Pet.Parent.ID, "achievement", msg.Achievement.ID)
+
+	if petUIModel, ok := ui.petUI.(*petui.PetUI); ok {
+		petUIModel.Update(petui.AchievementUnlockedMsg{Achievement: msg.Achievement})
+	}
+}
+
+// ShowPet switches the UI to the main screen for the given pet, along with
+// the gifts that arrived while the player was away. A pet can only be played
+// in one session at a time, the picker stays up if another session is
+// playing it.
+func (ui *UI) ShowPet(p *pet.Pet) tea.Cmd {
+	if !ui.sessions.SetPet(ui.sessionID, p.ID) {
+		log.Info("Pet is being played in another session", "id", p.ID, "name", p.Name)
+		if ui.picker != nil {
+			ui.picker.ShowMessage(fmt.Sprintf("%s is being played in another session", p.Name))
+		}
+		return nil
+	}
+
+	// The pet may have been played in another session since it was loaded
+	if p.ID != 0 {
+		fresh, err := ui.actions.Pet(context.Background(), p.ID)
+		if err != nil {
+			log.Error("Error reloading pet", "id", p.ID, "error", err)
+		} else if fresh != nil {
+			p = fresh
+		}
+	}
+
+	ui.picker = nil
+	ui.currentPet = p
+	ui.petUI = petui.NewPetUI(p, ui.sim, ui.pets, ui.actions, ui.scores, ui.achievements, ui.events, ui.width, ui.height)
+
+	cmd := ui.petUI.Init()
+
+	if p.Parent != nil {
+		ui.deliverGifts(p.Parent.ID)
+	}
+
+	return cmd
+}
+
+// perform performs an action handed over by the registry on the pet being
+// played and replies with a copy of the pet afterwards
+func (ui *UI) perform(msg performMsg) {
+	petUIModel, ok := ui.petUI.(*petui.PetUI)
+	if !ok || petUIModel.GetPet().ID != msg.petID {
+		msg.reply <- performResult{}
+		return
+	}
+
+	out, err := petUIModel.Perform(msg.action, msg.arg)
+	log.Info("Performed action in session", "pet_id", msg.petID, "action", msg.action, "error", err)
+	msg.reply <- performResult{out: out, pet: playdate.Snapshot(petUIModel.GetPet()), ok: true, err: err}
+}
+
+// ShowPicker switches the UI to the pet selection screen
+func (ui *UI) ShowPicker(pets []*pet.Pet, maxPets int) {
+	ui.petUI = nil
+	ui.currentPet = nil
+	ui.maxPets = maxPets
+	ui.sessions.SetPet(ui.sessionID, 0)
+	ui.picker = petui.NewPetPicker(pets, maxPets, ui.width, ui.height)
+}
+
+// adoptPet creates and stores a new pet for the player
+func (ui *UI) adoptPet(name string, species string) *pet.Pet {
+	newPet := newPlayerPet(ui.sim, name, species, ui.parentName)
+
+	err := ui.pets.Save(context.Background(), newPet, ui.publicKey)
+	if err != nil {
+		log.Error("Error saving adopted pet", "error", err)
+	} else {
+		log.Info("Adopted new pet", "id", newPet.ID, "name", newPet.Name, "species", newPet.SpeciesID)
+	}
+
+	return newPet
+}
+
+// showGraveyard switches the UI to the list of the player's deceased pets
+func (ui *UI) showGraveyard() {
+	var dead []*pet.Pet
+
+	userID, err := ui.users.GetByPublicKey(context.Background(), ui.publicKey)
+	if err != nil {
+		log.Error("Error finding user", "error", err)
+	} else if userID != 0 {
+		dead, err = ui.pets.ListDeadByParentID(context.Background(), userID)
+		if err != nil {
+			log.Error("Error listing dead pets", "error", err)
+		}
+	}
+
+	ui.graveyard = petui.NewGraveyard(dead, ui.width, ui.height)
+}
+
+// showShop switches the UI to the shop
+func (ui *UI) showShop() {
+	ctx := context.Background()
+	userID := ui.currentPet.Parent.ID
+
+	coins, err := ui.inventory.Coins(ctx, userID)
+	if err != nil {
+		log.Error("Error loading coins", "error", err)
+	}
+
+	owned, err := ui.inventory.Items(ctx, userID)
+	if err != nil {
+		log.Error("Error loading inventory", "error", err)
+	}
+
+	ui.shop = petui.NewShop(coins, owned, ui.width, ui.height)
+}
+
+// buyItem buys an item in the shop and shows how it went
+func (ui *UI) buyItem(id string) {
+	ctx := context.Background()
+	userID := ui.currentPet.Parent.ID
+
+	message := ""
+	item, _, err := actions.Buy(ctx, ui.inventory, userID, id)
+	if err != nil {
+		if !errors.Is(err, actions.ErrNotEnoughCoins) && !errors.Is(err, actions.ErrAlreadyOwned) {
+			log.Error("Error buying item", "item", id, "error", err)
+		}
+		message = err.Error()
+	} else {
+		log.Info("Bought item", "user_id", userID, "item", item.ID, "price", item.Price)
+		message = fmt.Sprintf("You bought %s %s for %d coins.", item.Emoji, strings.ToLower(item.Name), item.Price)
+	}
+
+	coins, err := ui.inventory.Coins(ctx, userID)
+	if err != nil {
+		log.Error("Error loading coins", "error", err)
+	}
+
+	owned, err := ui.inventory.Items(ctx, userID)
+	if err != nil {
+		log.Error("Error loading inventory", "error", err)
+	}
+
+	ui.shop.SetBalance(coins, owned, message)
+}
+
+// showLeaderboard switches the UI to the high scores of every game
+func (ui *UI) showLeaderboard() {
+	scores := make(map[string][]models.GameScore)
+
+	for _, g := range games.All() {
+		top, err := ui.scores.Top(context.Background(), g.ID, petui.LeaderboardSize)
+		if err != nil {
+			log.Error("Error loading high scores", "game", g.ID, "error", err)
+			continue
+		}
+		scores[g.ID] = top
+	}
+
+	ui.scoreboard = petui.NewLeaderboard(scores, ui.currentPet.Parent.ID, ui.width, ui.height)
+}
+
+// showDiary switches the UI to the activity log of the current pet
+func (ui *UI) showDiary() {
+	entries, err := ui.history.ListByPetID(context.Background(), ui.currentPet.ID, petui.DiarySize)
+	if err != nil {
+		log.Error("Error loading diary", "pet_id", ui.currentPet.ID, "error", err)
+	}
+
+	ui.diary = petui.NewDiary(ui.currentPet.Name, entries, ui.width, ui.height)
+}
+
+// showVisit switches the UI to looking up another player to visit
+func (ui *UI) showVisit() tea.Cmd {
+	private := false
+	user, err := ui.users.FindByPublicKey(context.Background(), ui.publicKey)
+	if err != nil {
+		log.Error("Error finding user", "error", err)
+	} else if user != nil {
+		private = user.Private
+	}
+
+	ui.visit = petui.NewVisit(private, ui.width, ui.height)
+
+	return ui.visit.Init()
+}
+
+// ShowVisit switches the UI to visiting the named player. Leaving the visit
+// ends the session, as there is no pet of the player's own to go back to.
+func (ui *UI) ShowVisit(name string) {
+	ui.showVisit()
+	ui.visitPlayer(name)
+}
+
+// visitPlayer shows the living pets of the named player, if they may be
+// visited
+func (ui *UI) visitPlayer(name string) {
+	owner, living, err := findVisitedPets(context.Background(), ui.users, ui.pets, ui.publicKey, name)
+	if err != nil {
+		ui.visit.ShowError(err.Error())
+		return
+	}
+
+	ui.visit.Show(owner.Name, living)
+}
+
+// setPrivacy changes whether other players may visit the player's pets
+func (ui *UI) setPrivacy(private bool) {
+	userID, err := ui.users.GetByPublicKey(context.Background(), ui.publicKey)
+	if err != nil || userID == 0 {
+		log.Error("Error finding user", "error", err)
+		return
+	}
+
+	if err := ui.users.SetPrivate(context.Background(), userID, private); err != nil {
+		log.Error("Error changing privacy", "user_id", userID, "error", err)
+		return
+	}
+
+	log.Info("Changed privacy", "user_id", userID, "private", private)
+	ui.visit.SetPrivate(private)
+}
+
+// showStatHistory switches the UI to the stat graphs of the current pet
+func (ui *UI) showStatHistory() {
+	p := ui.ownPet()
+
+	snapshots, err := ui.stats.ListByPetID(context.Background(), p.ID, p.BirthDate)
+	if err != nil {
+		log.Error("Error loading stat history", "pet_id", p.ID, "error", err)
+	}
+
+	ui.statHistory = petui.NewStatHistory(p, snapshots, time.Now(), ui.width, ui.height)
+}
+
 func (ui *UI) Init() tea.Cmd {
-	return ui.petUI.Init()
+	if ui.visit != nil && ui.petUI == nil {
+		return tea.Batch(ui.visit.Init(), ui.waitForUnlock())
+	}
+
+	if ui.picker != nil {
+		return tea.Batch(ui.picker.Init(), ui.waitForUnlock())
+	}
+
+	return tea.Batch(ui.petUI.Init(), ui.waitForUnlock())
 }
 
 func (ui *UI) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 
+	// Unlocks pop up whichever screen is shown
+	if msg, ok := msg.(unlockedMsg); ok {
+		ui.showUnlock(msg)
+		return ui, ui.waitForUnlock()
+	}
+
+	// Actions from SSH commands and the HTTP API are performed on the pet
+	// being played, whichever screen is shown
+	if msg, ok := msg.(performMsg); ok {
+		ui.perform(msg)
+		return ui, nil
+	}
+
+	// The pet is auto-saved whichever screen is shown
+	if _, ok := msg.(saveMsg); ok {
+		ui.syncPetState()
+		return ui, nil
+	}
+
+	// Playdates are arranged by other sessions, whichever screen is shown
+	if cmd, ok := ui.handlePlaydateMsg(msg); ok {
+		return ui, cmd
+	}
+
+	// Invitations pop up over the other screens
+	if ui.playdateInvite != nil {
+		return ui.updatePlaydateInvite(msg)
+	}
+
+	if ui.graveyard != nil {
+		return ui.updateGraveyard(msg)
+	}
+
+	if ui.shop != nil {
+		return ui.updateShop(msg)
+	}
+
+	if ui.scoreboard != nil {
+		return ui.updateLeaderboard(msg)
+	}
+
+	if ui.diary != nil {
+		return ui.updateDiary(msg)
+	}
+
+	if ui.statHistory != nil {
+		return ui.updateStatHistory(msg)
+	}
+
+	if ui.family != nil {
+		return ui.updateFamily(msg)
+	}
+
+	if ui.friends != nil {
+		return ui.updateFriends(msg)
+	}
+
+	if ui.visit != nil {
+		return ui.updateVisit(msg)
+	}
+
+	if ui.playdatePicker != nil {
+		return ui.updatePlaydatePicker(msg)
+	}
+
+	if ui.playdate != nil {
+		return ui.updatePlaydate(msg)
+	}
+
+	if ui.picker != nil {
+		return ui.updatePicker(msg)
+	}
+
 	switch msg := msg.(type) {
 	case timeMsg:
 		ui.time = time.Time(msg)
@@ -80,6 +512,30 @@ func (ui *UI) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		}
 
+	case petui.ShowShopMsg:
+		ui.showShop()
+
+	case petui.ShowLeaderboardMsg:
+		ui.showLeaderboard()
+
+	case petui.ShowDiaryMsg:
+		ui.showDiary()
+
+	case petui.ShowStatHistoryMsg:
+		ui.showStatHistory()
+
+	case petui.ShowFamilyMsg:
+		ui.showFamily()
+
+	case petui.ShowVisitMsg:
+		cmd = ui.showVisit()
+
+	case petui.ShowFriendsMsg:
+		ui.showFriends()
+
+	case petui.ShowPlaydateMsg:
+		ui.showPlaydatePicker()
+
 	case petui.QuitMsg:
 		// Handle the custom quit message from the pet UI
 		log.Info("Received quit request from menu")
@@ -97,7 +553,238 @@ func (ui *UI) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return ui, cmd
 }
 
+// updatePicker handles messages while the pet selection screen is shown
+func (ui *UI) updatePicker(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+	case timeMsg:
+		ui.time = time.Time(msg)
+
+	case tea.WindowSizeMsg:
+		ui.height = msg.Height
+		ui.width = msg.Width
+		_, cmd = ui.picker.Update(msg)
+
+	case petui.PetSelectedMsg:
+		log.Info("Pet selected", "id", msg.Pet.ID, "name", msg.Pet.Name)
+		return ui, ui.ShowPet(msg.Pet)
+
+	case petui.AdoptPetMsg:
+		if !ui.picker.CanAdopt() {
+			return ui, nil
+		}
+		return ui, ui.ShowPet(ui.adoptPet(msg.Name, msg.Species))
+
+	case petui.ShowGraveyardMsg:
+		ui.showGraveyard()
+
+	case petui.QuitMsg:
+		log.Info("Received quit request from pet picker")
+		return ui, tea.Quit
+
+	default:
+		_, cmd = ui.picker.Update(msg)
+	}
+
+	return ui, cmd
+}
+
+// updateGraveyard handles messages while the graveyard is shown
+func (ui *UI) updateGraveyard(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+	case timeMsg:
+		ui.time = time.Time(msg)
+
+	case tea.WindowSizeMsg:
+		ui.height = msg.Height
+		ui.width = msg.Width
+		_, cmd = ui.graveyard.Update(msg)
+		ui.picker.Update(msg)
+
+	case petui.EpitaphWrittenMsg:
+		err := ui.pets.SetEpitaph(context.Background(), msg.Pet.ID, msg.Epitaph)
+		if err != nil {
+			log.Error("Error saving epitaph", "error", err)
+		}
+
+	case petui.CloseGraveyardMsg:
+		ui.graveyard = nil
+
+	default:
+		_, cmd = ui.graveyard.Update(msg)
+	}
+
+	return ui, cmd
+}
+
+// updateShop handles messages while the shop is shown
+func (ui *UI) updateShop(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+	case timeMsg:
+		ui.time = time.Time(msg)
+
+	case tea.WindowSizeMsg:
+		ui.height = msg.Height
+		ui.width = msg.Width
+		_, cmd = ui.shop.Update(msg)
+		ui.petUI.Update(msg)
+
+	case petui.BuyItemMsg:
+		ui.buyItem(msg.Item.ID)
+
+	case petui.CloseShopMsg:
+		ui.shop = nil
+		if petUIModel, ok := ui.petUI.(*petui.PetUI); ok {
+			petUIModel.RefreshInventory()
+		}
+
+	case petui.FrameMsg:
+		// Keep the pet's ticker running behind the shop
+		ui.petUI, cmd = ui.petUI.Update(msg)
+
+	default:
+		_, cmd = ui.shop.Update(msg)
+	}
+
+	return ui, cmd
+}
+
+// updateLeaderboard handles messages while the high scores are shown
+func (ui *UI) updateLeaderboard(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+	case timeMsg:
+		ui.time = time.Time(msg)
+
+	case tea.WindowSizeMsg:
+		ui.height = msg.Height
+		ui.width = msg.Width
+		_, cmd = ui.scoreboard.Update(msg)
+		ui.petUI.Update(msg)
+
+	case petui.CloseLeaderboardMsg:
+		ui.scoreboard = nil
+
+	case petui.FrameMsg:
+		// Keep the pet's ticker running behind the high scores
+		ui.petUI, cmd = ui.petUI.Update(msg)
+
+	default:
+		_, cmd = ui.scoreboard.Update(msg)
+	}
+
+	return ui, cmd
+}
+
+// updateDiary handles messages while the diary is shown
+func (ui *UI) updateDiary(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+	case timeMsg:
+		ui.time = time.Time(msg)
+
+	case tea.WindowSizeMsg:
+		ui.height = msg.Height
+		ui.width = msg.Width
+		_, cmd = ui.diary.Update(msg)
+		ui.petUI.Update(msg)
+
+	case petui.CloseDiaryMsg:
+		ui.diary = nil
+
+	case petui.FrameMsg:
+		// Keep the pet's ticker running behind the diary
+		ui.petUI, cmd = ui.petUI.Update(msg)
+
+	default:
+		_, cmd = ui.diary.Update(msg)
+	}
+
+	return ui, cmd
+}
+
+// updateVisit handles messages while visiting another player
+func (ui *UI) updateVisit(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+	case timeMsg:
+		ui.time = time.Time(msg)
+
+	case tea.WindowSizeMsg:
+		ui.height = msg.Height
+		ui.width = msg.Width
+		_, cmd = ui.visit.Update(msg)
+		if ui.petUI != nil {
+			ui.petUI.Update(msg)
+		}
+
+	case petui.VisitPlayerMsg:
+		ui.visitPlayer(msg.Name)
+
+	case petui.SetPrivacyMsg:
+		ui.setPrivacy(msg.Private)
+
+	case petui.CloseVisitMsg:
+		// Without a pet of their own there is nothing to go back to
+		if ui.petUI == nil {
+			return ui, tea.Quit
+		}
+		ui.visit = nil
+
+	case petui.FrameMsg:
+		// Keep the pet's ticker running while visiting
+		if ui.petUI != nil {
+			ui.petUI, cmd = ui.petUI.Update(msg)
+		}
+
+	default:
+		_, cmd = ui.visit.Update(msg)
+	}
+
+	return ui, cmd
+}
+
+// updateStatHistory handles messages while the stats screen is shown
+func (ui *UI) updateStatHistory(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+
+	switch msg := msg.(type) {
+	case timeMsg:
+		ui.time = time.Time(msg)
+
+	case tea.WindowSizeMsg:
+		ui.height = msg.Height
+		ui.width = msg.Width
+		_, cmd = ui.statHistory.Update(msg)
+		ui.petUI.Update(msg)
+
+	case petui.CloseStatHistoryMsg:
+		ui.statHistory = nil
+
+	case petui.FrameMsg:
+		// Keep the pet's ticker running behind the stats screen
+		ui.petUI, cmd = ui.petUI.Update(msg)
+
+	default:
+		_, cmd = ui.statHistory.Update(msg)
+	}
+
+	return ui, cmd
+}
+
 func (ui *UI) syncPetState() {
+	if ui.petUI == nil {
+		return
+	}
+
 	// Always get the current pet state from the UI model first
 	if petUIModel, ok := ui.petUI.(*petui.PetUI); ok {
 		ui.currentPet = petUIModel.GetPet()
@@ -113,13 +800,84 @@ func (ui *UI) syncPetState() {
 		"lights_on", ui.currentPet.LightsOn,
 		"is_dead", ui.currentPet.Health <= 0)
 
-	petRepo := repo.NewPetRepository(nil)
-	err := petRepo.Save(context.Background(), ui.currentPet, ui.publicKey)
+	err := ui.savePet(context.Background(), ui.currentPet)
 	if err != nil {
 		log.Error("Error saving pet state", "error", err)
 	}
 }
 
+// savePet saves the pet played in the session. Actions on it are handed to
+// the session, so its copy is the one that counts: if the pet was saved
+// elsewhere since it was loaded, it is loaded again and the session's copy
+// saved over it at its new revision.
+func (ui *UI) savePet(ctx context.Context, p *pet.Pet) error {
+	err := ui.pets.Save(ctx, p, ui.publicKey)
+	if !errors.Is(err, repo.ErrPetConflict) {
+		return err
+	}
+
+	stored, err := ui.pets.GetByID(ctx, p.ID)
+	if err != nil {
+		return fmt.Errorf("reload pet: %w", err)
+	}
+	if stored == nil {
+		return repo.ErrPetConflict
+	}
+
+	log.Warn("Pet was saved elsewhere, saving the session's copy over it", "id", p.ID, "revision", stored.Revision)
+
+	p.Revision = stored.Revision
+	return ui.pets.Update(ctx, p)
+}
+
 func (ui *UI) View() string {
+	if ui.playdateInvite != nil {
+		return ui.playdateInvite.View()
+	}
+
+	if ui.graveyard != nil {
+		return ui.graveyard.View()
+	}
+
+	if ui.shop != nil {
+		return ui.shop.View()
+	}
+
+	if ui.scoreboard != nil {
+		return ui.scoreboard.View()
+	}
+
+	if ui.diary != nil {
+		return ui.diary.View()
+	}
+
+	if ui.statHistory != nil {
+		return ui.statHistory.View()
+	}
+
+	if ui.family != nil {
+		return ui.family.View()
+	}
+
+	if ui.friends != nil {
+		return ui.friends.View()
+	}
+
+	if ui.visit != nil {
+		return ui.visit.View()
+	}
+
+	if ui.playdatePicker != nil {
+		return ui.playdatePicker.View()
+	}
+
+	if ui.playdate != nil {
+		return ui.playdate.View()
+	}
+
+	if ui.picker != nil {
+		return ui.picker.View()
+	}
+
 	return ui.petUI.View()
 }
@@ -0,0 +1,437 @@
+package ssh
+
+import (
+	"context"
+	"io"
+	"math/rand"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/kirkegaard/terminal-pet/pkg/achievements"
+	"github.com/kirkegaard/terminal-pet/pkg/actions"
+	"github.com/kirkegaard/terminal-pet/pkg/db/repo"
+	"github.com/kirkegaard/terminal-pet/pkg/events"
+	"github.com/kirkegaard/terminal-pet/pkg/pet"
+	"github.com/kirkegaard/terminal-pet/pkg/shop"
+	petui "github.com/kirkegaard/terminal-pet/pkg/ui"
+	"github.com/kirkegaard/terminal-pet/pkg/world"
+)
+
+// testMaxPets is how many living pets the players of the tests can have
+const testMaxPets = 3
+
+// menuPresses is more key presses than there are choices in the menu
+const menuPresses = 32
+
+// newTestDeps returns the dependencies of a server backed by the memory
+// stores
+func newTestDeps() Deps {
+	users := repo.NewMemoryUserRepository()
+	pets := repo.NewMemoryPetRepository(users)
+	inventory := repo.NewMemoryInventoryRepository()
+	bus := events.NewBus()
+	sessions := NewRegistry()
+
+	service := actions.NewService(pets, inventory, bus, sessions, pet.SystemClock)
+
+	return Deps{
+		Pets:         pets,
+		Users:        users,
+		Tokens:       repo.NewMemoryTokenRepository(),
+		Scores:       repo.NewMemoryScoreRepository(),
+		History:      repo.NewMemoryPetEventRepository(),
+		Stats:        repo.NewMemoryStatRepository(),
+		Lineage:      repo.NewMemoryLineageRepository(),
+		Friends:      repo.NewMemoryFriendRepository(users),
+		Gifts:        repo.NewMemoryGiftRepository(users, inventory),
+		Achievements: achievements.NewEngine(repo.NewMemoryAchievementRepository(), bus, pet.SystemClock),
+		Actions:      service,
+		Sessions:     sessions,
+	}
+}
+
+// laterClock is a clock running an hour ahead
+type laterClock struct{}
+
+func (laterClock) Now() time.Time {
+	return time.Now().Add(time.Hour)
+}
+
+// testSession is the session of a player. The messages other sessions send
+// it wait on msgs until the test passes them to the UI.
+type testSession struct {
+	ui         *UI
+	deps       Deps
+	msgs       chan tea.Msg
+	unregister func()
+}
+
+// newTestSession opens a session for a player, registered like an
+// interactive one and showing their pets
+func newTestSession(t *testing.T, deps Deps, publicKey string, name string) *testSession {
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+
+	s := &testSession{deps: deps, msgs: make(chan tea.Msg, 16)}
+
+	sim := pet.NewSimulator(pet.SystemClock, rand.New(rand.NewSource(1)))
+	s.ui = NewUI(ctx, lipgloss.NewRenderer(io.Discard), 80, 24, sim, deps, publicKey, name)
+	s.ui.sessionID, s.unregister = deps.Sessions.Register(publicKey, name, func(msg tea.Msg) { s.msgs <- msg })
+	t.Cleanup(s.unregister)
+
+	userID, err := deps.Users.GetByPublicKey(ctx, publicKey)
+	if err != nil {
+		t.Fatalf("find user: %v", err)
+	}
+	living, err := deps.Pets.ListAliveByParentID(ctx, userID)
+	if err != nil {
+		t.Fatalf("list pets: %v", err)
+	}
+	s.ui.ShowPicker(living, testMaxPets)
+
+	return s
+}
+
+// adopt adopts a pet of the species in the picker and returns it
+func (s *testSession) adopt(t *testing.T, name string, species string) *pet.Pet {
+	s.ui.Update(petui.AdoptPetMsg{Name: name, Species: species})
+
+	p := s.ui.ownPet()
+	if p == nil || p.ID == 0 {
+		t.Fatalf("adopted pet %+v wasn't saved", p)
+	}
+	if p.SpeciesID != species {
+		t.Fatalf("adopted a %s, want a %s", p.SpeciesID, species)
+	}
+
+	return p
+}
+
+// perform performs an action on a pet from outside the session, like an SSH
+// command does, passing what the session is sent on to its UI
+func (s *testSession) perform(t *testing.T, p *pet.Pet, action string, arg string) (string, error) {
+	type result struct {
+		out string
+		err error
+	}
+	done := make(chan result, 1)
+
+	go func() {
+		out, err := s.deps.Actions.Perform(context.Background(), p, action, arg)
+		done <- result{out, err}
+	}()
+
+	select {
+	case msg := <-s.msgs:
+		s.ui.Update(msg)
+	case <-time.After(performTimeout):
+		t.Fatalf("the session wasn't asked to perform %s", action)
+	}
+
+	r := <-done
+	return r.out, r.err
+}
+
+// press sends the UI the keys one after another
+func (s *testSession) press(keys ...tea.KeyType) {
+	for _, k := range keys {
+		s.ui.Update(tea.KeyMsg{Type: k})
+	}
+}
+
+// quit quits the session with q and fails the test if it didn't end
+func (s *testSession) quit(t *testing.T) {
+	_, cmd := s.ui.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("q")})
+	if cmd == nil {
+		t.Fatalf("quitting didn't end the session")
+	}
+	if _, ok := cmd().(tea.QuitMsg); !ok {
+		t.Fatalf("quitting didn't end the session")
+	}
+}
+
+func TestSessionAdoptAndPickPet(t *testing.T) {
+	deps := newTestDeps()
+	ctx := context.Background()
+
+	first := newTestSession(t, deps, "key-alice", "alice")
+	adopted := first.adopt(t, "Rex", pet.SpeciesDog)
+
+	if !deps.Sessions.Playing(adopted.ID) {
+		t.Errorf("the adopted pet isn't being played")
+	}
+
+	userID, err := deps.Users.GetByPublicKey(ctx, "key-alice")
+	if err != nil || userID == 0 || adopted.Parent.ID != userID {
+		t.Fatalf("adopted pet of user %d, found user %d: %v", adopted.Parent.ID, userID, err)
+	}
+
+	// Connecting again opens a session of its own, which can't play the pet
+	// while the first one is
+	second := newTestSession(t, deps, "key-alice", "alice")
+	if second.ui.picker == nil || !deps.Sessions.Playing(adopted.ID) {
+		t.Fatalf("the second session doesn't start in the picker")
+	}
+
+	second.ui.Update(petui.PetSelectedMsg{Pet: adopted})
+
+	if second.ui.picker == nil || second.ui.ownPet() != nil {
+		t.Fatalf("the second session plays the pet of the first")
+	}
+	if online := deps.Sessions.Online(); len(online) != 1 {
+		t.Errorf("%d players online, want 1", len(online))
+	}
+
+	// Once the first session ends the pet can be picked, as saved by it
+	first.ui.ownPet().Hunger = 7
+	first.quit(t)
+	first.unregister()
+
+	second.ui.Update(petui.PetSelectedMsg{Pet: adopted})
+
+	got := second.ui.ownPet()
+	if got == nil || got.ID != adopted.ID {
+		t.Fatalf("playing %+v, want pet %d", got, adopted.ID)
+	}
+	if got.Hunger != 7 {
+		t.Errorf("hunger %d, want 7 as saved by the first session", got.Hunger)
+	}
+	if !deps.Sessions.Playing(adopted.ID) {
+		t.Errorf("the picked pet isn't being played")
+	}
+}
+
+func TestWorldTickLeavesPlayedPets(t *testing.T) {
+	deps := newTestDeps()
+	ctx := context.Background()
+
+	// The player plays one pet in each of two connections, then goes back
+	// to the picker in the second
+	first := newTestSession(t, deps, "key-alice", "alice")
+	played := first.adopt(t, "Rex", pet.DefaultSpecies)
+	second := newTestSession(t, deps, "key-alice", "alice")
+	left := second.adopt(t, "Tom", pet.DefaultSpecies)
+	second.ui.ShowPicker([]*pet.Pet{played, left}, testMaxPets)
+
+	sim := pet.NewSimulator(laterClock{}, rand.New(rand.NewSource(1)))
+	ticker := world.NewTicker(deps.Pets, deps.Actions.Events(), sim, deps.Sessions, time.Minute)
+	if err := ticker.Tick(ctx); err != nil {
+		t.Fatalf("tick: %v", err)
+	}
+
+	if got, err := deps.Pets.GetByID(ctx, played.ID); err != nil || got.Revision != played.Revision {
+		t.Errorf("the pet played in the first session was simulated: %+v, %v", got, err)
+	}
+	if got, err := deps.Pets.GetByID(ctx, left.ID); err != nil || got.Revision == left.Revision {
+		t.Errorf("the pet nobody plays wasn't simulated: %+v, %v", got, err)
+	}
+}
+
+func TestSessionSavesOnQuit(t *testing.T) {
+	deps := newTestDeps()
+	ctx := context.Background()
+
+	s := newTestSession(t, deps, "key-alice", "alice")
+	p := s.adopt(t, "Rex", pet.DefaultSpecies)
+
+	// Turn the lights off from the menu, the choice right above Quit
+	for range menuPresses {
+		s.press(tea.KeyDown)
+	}
+	s.press(tea.KeyUp, tea.KeyEnter)
+
+	// Auto-saving happens on the UI's goroutine, whichever screen is shown
+	s.ui.ownPet().Hunger = 7
+	s.ui.Update(petui.ShowShopMsg{})
+	s.ui.Update(saveMsg{})
+	s.ui.Update(petui.CloseShopMsg{})
+
+	saved, err := deps.Pets.GetByID(ctx, p.ID)
+	if err != nil || saved.Hunger != 7 {
+		t.Errorf("auto-saved pet %+v: %v, want hunger 7", saved, err)
+	}
+
+	s.ui.ownPet().Hunger = 9
+	s.quit(t)
+
+	living, err := deps.Pets.ListAliveByParentID(ctx, p.Parent.ID)
+	if err != nil || len(living) != 1 || living[0].ID != p.ID {
+		t.Fatalf("living pets %+v: %v, want pet %d", living, err, p.ID)
+	}
+	if living[0].LightsOn || living[0].Hunger != 9 {
+		t.Errorf("saved pet %+v, want the lights off and hunger 9", living[0])
+	}
+}
+
+func TestSessionShopAndFeed(t *testing.T) {
+	deps := newTestDeps()
+	ctx := context.Background()
+
+	alice := newTestSession(t, deps, "key-alice", "alice")
+	p := alice.adopt(t, "Rex", pet.DefaultSpecies)
+
+	food := pet.Foods().All()[0]
+	item, ok := shop.Get(food.ID)
+	if !ok {
+		t.Fatalf("food %s isn't sold", food.ID)
+	}
+
+	alice.ui.Update(petui.ShowShopMsg{})
+	if alice.ui.shop == nil {
+		t.Fatalf("the shop isn't shown")
+	}
+	alice.ui.Update(petui.BuyItemMsg{Item: item})
+	alice.ui.Update(petui.CloseShopMsg{})
+
+	if alice.ui.shop != nil {
+		t.Errorf("the shop is still shown")
+	}
+
+	coins, err := deps.Actions.Inventory().Coins(ctx, p.Parent.ID)
+	if err != nil || coins != repo.StartingCoins-item.Price {
+		t.Errorf("coins %d: %v, want %d", coins, err, repo.StartingCoins-item.Price)
+	}
+
+	// A feed command from outside is performed on the pet on screen
+	live := alice.ui.ownPet()
+	live.Hunger, live.Discipline = 50, 100
+
+	loaded, err := deps.Actions.Pet(ctx, p.ID)
+	if err != nil || loaded == nil {
+		t.Fatalf("load pet: %v", err)
+	}
+
+	if _, err := alice.perform(t, loaded, actions.Feed, food.ID); err != nil {
+		t.Fatalf("feed: %v", err)
+	}
+
+	if live.Hunger >= 50 {
+		t.Errorf("hunger of the pet on screen is %d, it wasn't fed", live.Hunger)
+	}
+	if loaded.Hunger != live.Hunger {
+		t.Errorf("the command's copy has hunger %d, want %d", loaded.Hunger, live.Hunger)
+	}
+
+	items, err := deps.Actions.Inventory().Items(ctx, p.Parent.ID)
+	if err != nil || items[food.ID] != 0 {
+		t.Errorf("%d %s left: %v, want 0", items[food.ID], food.ID, err)
+	}
+
+	saved, err := deps.Pets.GetByID(ctx, p.ID)
+	if err != nil || saved.Hunger != live.Hunger {
+		t.Errorf("saved pet %+v: %v, want hunger %d", saved, err, live.Hunger)
+	}
+
+	// Once the player goes back to the picker actions are performed on the
+	// stored pet
+	alice.ui.ShowPicker([]*pet.Pet{live}, testMaxPets)
+
+	stale, err := deps.Actions.Pet(ctx, p.ID)
+	if err != nil || stale == nil {
+		t.Fatalf("load pet: %v", err)
+	}
+
+	if _, err := deps.Actions.Perform(ctx, loaded, actions.Lights, ""); err != nil {
+		t.Fatalf("lights: %v", err)
+	}
+	if len(alice.msgs) != 0 {
+		t.Errorf("the session was asked to perform an action on a pet it isn't playing")
+	}
+
+	// An action on a copy loaded before the last save is performed again on
+	// the saved pet
+	if _, err := deps.Actions.Perform(ctx, stale, actions.Lights, ""); err != nil {
+		t.Fatalf("lights on a stale copy: %v", err)
+	}
+	saved, err = deps.Pets.GetByID(ctx, p.ID)
+	if err != nil || !saved.LightsOn || saved.Revision != stale.Revision {
+		t.Errorf("saved pet %+v: %v, want the lights back on at revision %d", saved, err, stale.Revision)
+	}
+}
+
+func TestFinishGame(t *testing.T) {
+	deps := newTestDeps()
+	ctx := context.Background()
+
+	s := newTestSession(t, deps, "key-alice", "alice")
+	adopted := s.adopt(t, "Rex", pet.DefaultSpecies)
+	s.ui.ShowPicker(nil, testMaxPets)
+
+	userID := adopted.Parent.ID
+	if err := deps.Actions.Inventory().AddItem(ctx, userID, "ball", 1); err != nil {
+		t.Fatalf("add item: %v", err)
+	}
+
+	p, err := deps.Actions.Pet(ctx, adopted.ID)
+	if err != nil || p == nil {
+		t.Fatalf("load pet: %v", err)
+	}
+	happiness := p.Happiness
+
+	reward, err := deps.Actions.FinishGame(ctx, p, pet.GameResult{Game: "catch", Score: 2, MaxScore: 10, Happiness: 5})
+	if err != nil {
+		t.Fatalf("finish game: %v", err)
+	}
+
+	want := actions.GameReward{Coins: 2 * actions.CoinsPerPoint, CareCoins: actions.DailyCareReward, Toy: "Ball"}
+	if reward != want {
+		t.Errorf("reward %+v, want %+v", reward, want)
+	}
+
+	saved, err := deps.Pets.GetByID(ctx, p.ID)
+	if err != nil || saved.Happiness != happiness+5+3 {
+		t.Errorf("saved pet %+v: %v, want happiness %d", saved, err, happiness+8)
+	}
+
+	coins, err := deps.Actions.Inventory().Coins(ctx, userID)
+	if want := repo.StartingCoins + reward.Coins + reward.CareCoins; err != nil || coins != want {
+		t.Errorf("coins %d: %v, want %d", coins, err, want)
+	}
+}
+
+func TestSessionFriendsAndGifts(t *testing.T) {
+	deps := newTestDeps()
+	ctx := context.Background()
+
+	alice := newTestSession(t, deps, "key-alice", "alice")
+	alicesPet := alice.adopt(t, "Rex", pet.DefaultSpecies)
+	bob := newTestSession(t, deps, "key-bob", "bob")
+	bobsPet := bob.adopt(t, "Tom", pet.DefaultSpecies)
+
+	alice.ui.Update(petui.ShowFriendsMsg{})
+	alice.ui.Update(petui.AddFriendMsg{Name: "bob"})
+	bob.ui.Update(petui.ShowFriendsMsg{})
+	bob.ui.Update(petui.AddFriendMsg{Name: "alice"})
+	bob.ui.Update(petui.CloseFriendsMsg{})
+
+	aliceID := alicesPet.Parent.ID
+	bobID := bobsPet.Parent.ID
+
+	f, err := deps.Friends.Get(ctx, aliceID, bobID)
+	if err != nil || f == nil || !f.AcceptedAt.Valid {
+		t.Fatalf("friendship %+v: %v, want an accepted one", f, err)
+	}
+
+	item, _ := shop.Get(shop.MedicineID)
+	if err := deps.Actions.Inventory().AddItem(ctx, aliceID, item.ID, 1); err != nil {
+		t.Fatalf("add item: %v", err)
+	}
+	alice.ui.Update(petui.SendGiftMsg{To: "bob", Item: item})
+	alice.ui.Update(petui.CloseFriendsMsg{})
+
+	owned, err := deps.Actions.Inventory().Items(ctx, aliceID)
+	if err != nil || owned[item.ID] != 0 {
+		t.Errorf("alice still has %d %s: %v", owned[item.ID], item.ID, err)
+	}
+
+	// Gifts are delivered when the player picks a pet
+	bob.ui.ShowPicker([]*pet.Pet{bobsPet}, testMaxPets)
+	bob.ui.Update(petui.PetSelectedMsg{Pet: bobsPet})
+
+	owned, err = deps.Actions.Inventory().Items(ctx, bobID)
+	if err != nil || owned[item.ID] != 1 {
+		t.Errorf("bob has %d %s: %v, want 1", owned[item.ID], item.ID, err)
+	}
+}
@@ -0,0 +1,111 @@
+package ssh
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/log"
+	"github.com/charmbracelet/ssh"
+	"github.com/kirkegaard/terminal-pet/pkg/db/models"
+	"github.com/kirkegaard/terminal-pet/pkg/db/repo"
+	"github.com/kirkegaard/terminal-pet/pkg/pet"
+)
+
+// interactiveVisit returns who to visit if the session is `ssh -t host
+// visit <name>`, which opens the visit screen instead of printing the pets
+func interactiveVisit(s ssh.Session) (string, bool) {
+	if _, _, active := s.Pty(); !active {
+		return "", false
+	}
+
+	args := s.Command()
+	if len(args) != 2 || !strings.EqualFold(args[0], "visit") {
+		return "", false
+	}
+
+	return args[1], true
+}
+
+// findVisitedPets returns the player with the given name and their living
+// pets, unless they keep them private from the visitor. Errors are meant to
+// be shown to the visitor.
+func findVisitedPets(ctx context.Context, users repo.UserStore, pets repo.PetStore, visitorKey string, name string) (*models.User, []*pet.Pet, error) {
+	owner, err := findPlayer(ctx, users, name)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Players can always look at their own pets
+	if owner.Private && owner.PublicKey != visitorKey {
+		return nil, nil, fmt.Errorf("%s keeps their pets private", owner.Name)
+	}
+
+	living, err := pets.ListAliveByParentID(ctx, owner.ID)
+	if err != nil {
+		log.Error("Error listing pets", "user_id", owner.ID, "error", err)
+		return nil, nil, fmt.Errorf("could not load %s's pets", owner.Name)
+	}
+
+	return owner, living, nil
+}
+
+// visitCommand prints the living pets of another player
+func (srv *SSHServer) visitCommand(ctx context.Context, publicKey string, args []string) (string, error) {
+	if len(args) != 1 {
+		return "", fmt.Errorf("usage: visit <name>")
+	}
+
+	owner, living, err := findVisitedPets(ctx, srv.deps.Users, srv.deps.Pets, publicKey, args[0])
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	sb.WriteString("Visiting " + owner.Name)
+	if len(living) == 0 {
+		sb.WriteString("\n  " + owner.Name + " has no living pets.")
+	}
+	for _, p := range living {
+		sb.WriteString("\n  ")
+		sb.WriteString(petStatus(p))
+	}
+
+	return sb.String(), nil
+}
+
+// privacyCommand shows or changes whether other players may visit the
+// caller's pets
+func (srv *SSHServer) privacyCommand(ctx context.Context, publicKey string, args []string) (string, error) {
+	if len(args) > 1 || (len(args) == 1 && args[0] != "public" && args[0] != "private") {
+		return "", fmt.Errorf("usage: privacy [public|private]")
+	}
+
+	user, err := srv.deps.Users.FindByPublicKey(ctx, publicKey)
+	if err != nil {
+		log.Error("Error finding user", "error", err)
+		return "", fmt.Errorf("could not find your account")
+	}
+	if user == nil {
+		return "", fmt.Errorf("you don't have a pet yet, connect with ssh to adopt one")
+	}
+
+	if len(args) == 1 {
+		user.Private = args[0] == "private"
+		if err := srv.deps.Users.SetPrivate(ctx, user.ID, user.Private); err != nil {
+			log.Error("Error changing privacy", "user_id", user.ID, "error", err)
+			return "", fmt.Errorf("could not change your privacy setting")
+		}
+	}
+
+	return privacyDescription(user.Private), nil
+}
+
+// privacyDescription explains a privacy setting to the player
+func privacyDescription(private bool) string {
+	if private {
+		return "Your pets are private, nobody else can visit them."
+	}
+
+	return "Your pets are public, anyone can visit them."
+}
@@ -0,0 +1,133 @@
+package stats
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/kirkegaard/terminal-pet/pkg/db/models"
+	"github.com/kirkegaard/terminal-pet/pkg/db/repo"
+	"github.com/kirkegaard/terminal-pet/pkg/pet"
+)
+
+const (
+	// Hourly is the bucket snapshots are compacted into after a day
+	Hourly = time.Hour
+	// Daily is the bucket hourly snapshots are compacted into after a week
+	Daily = 24 * time.Hour
+
+	// HourlyAfter is how old snapshots get before they are compacted into
+	// hourly averages
+	HourlyAfter = 24 * time.Hour
+	// DailyAfter is how old snapshots get before they are compacted into
+	// daily averages
+	DailyAfter = 7 * 24 * time.Hour
+)
+
+// Recorder periodically snapshots the stats of every living pet and
+// compacts old snapshots, so the history stays small while still covering
+// the whole life of a pet.
+type Recorder struct {
+	petRepo  repo.PetStore
+	store    repo.StatStore
+	interval time.Duration
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewRecorder creates a recorder that snapshots every interval
+func NewRecorder(petRepo repo.PetStore, store repo.StatStore, interval time.Duration) *Recorder {
+	return &Recorder{
+		petRepo:  petRepo,
+		store:    store,
+		interval: interval,
+	}
+}
+
+// Start runs the recorder in the background until Stop is called or the
+// context is cancelled
+func (r *Recorder) Start(ctx context.Context) {
+	ctx, r.cancel = context.WithCancel(ctx)
+
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case t := <-ticker.C:
+				if err := r.Record(ctx, t); err != nil {
+					log.Error("Recording stats failed", "error", err)
+				}
+			case <-ctx.Done():
+				log.Debug("Stats recorder stopped")
+				return
+			}
+		}
+	}()
+
+	log.Info("Stats recorder started", "interval", r.interval)
+}
+
+// Stop stops the recorder and waits for a running snapshot to finish
+func (r *Recorder) Stop() {
+	if r.cancel != nil {
+		r.cancel()
+	}
+
+	r.wg.Wait()
+}
+
+// Record snapshots all living pets and compacts the snapshots that have
+// grown old
+func (r *Recorder) Record(ctx context.Context, now time.Time) error {
+	pets, err := r.petRepo.ListAlive(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, p := range pets {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		s := Snapshot(p, now.Truncate(r.interval), r.interval)
+		if err := r.store.Add(ctx, &s); err != nil {
+			log.Error("Could not save stat snapshot", "id", p.ID, "error", err)
+		}
+	}
+
+	hourly, err := r.store.Compact(ctx, Hourly, now.Add(-HourlyAfter).Truncate(Hourly))
+	if err != nil {
+		return err
+	}
+
+	daily, err := r.store.Compact(ctx, Daily, now.Add(-DailyAfter).Truncate(Daily))
+	if err != nil {
+		return err
+	}
+
+	log.Debug("Stats recorded", "pets", len(pets), "compacted_hourly", hourly, "compacted_daily", daily)
+
+	return nil
+}
+
+// Snapshot returns the current stats of a pet as a snapshot of the bucket
+// starting at the given time
+func Snapshot(p *pet.Pet, at time.Time, bucket time.Duration) models.StatSnapshot {
+	return models.StatSnapshot{
+		PetID:         p.ID,
+		BucketSeconds: int(bucket / time.Second),
+		At:            at.UTC(),
+		Hunger:        p.Hunger,
+		Happiness:     p.Happiness,
+		Health:        p.Health,
+		Weight:        p.Weight,
+		Samples:       1,
+	}
+}
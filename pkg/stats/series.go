@@ -0,0 +1,67 @@
+package stats
+
+import (
+	"math"
+	"time"
+
+	"github.com/kirkegaard/terminal-pet/pkg/db/models"
+)
+
+// Stat picks one value out of a snapshot
+type Stat func(models.StatSnapshot) int
+
+// The stats that are graphed
+var (
+	Hunger    Stat = func(s models.StatSnapshot) int { return s.Hunger }
+	Happiness Stat = func(s models.StatSnapshot) int { return s.Happiness }
+	Health    Stat = func(s models.StatSnapshot) int { return s.Health }
+	Weight    Stat = func(s models.StatSnapshot) int { return s.Weight }
+)
+
+// Series resamples snapshots into the given number of equally wide columns
+// between from and to. Every column holds the average of the snapshots
+// whose bucket overlaps it, weighted by their samples, or NaN when no
+// snapshot covers it.
+func Series(snapshots []models.StatSnapshot, stat Stat, from, to time.Time, columns int) []float64 {
+	sums := make([]float64, columns)
+	weights := make([]float64, columns)
+
+	span := to.Sub(from)
+	if columns <= 0 || span <= 0 {
+		return nil
+	}
+
+	column := func(t time.Time) int {
+		i := int(float64(t.Sub(from)) / float64(span) * float64(columns))
+		return min(max(i, 0), columns-1)
+	}
+
+	for _, s := range snapshots {
+		end := s.At.Add(time.Duration(s.BucketSeconds) * time.Second)
+		if end.Before(from) || s.At.After(to) {
+			continue
+		}
+
+		last := column(s.At)
+		if end.After(s.At) {
+			last = column(end.Add(-time.Nanosecond))
+		}
+
+		samples := float64(max(s.Samples, 1))
+		for i := column(s.At); i <= last; i++ {
+			sums[i] += float64(stat(s)) * samples
+			weights[i] += samples
+		}
+	}
+
+	values := make([]float64, columns)
+	for i := range values {
+		if weights[i] == 0 {
+			values[i] = math.NaN()
+			continue
+		}
+		values[i] = sums[i] / weights[i]
+	}
+
+	return values
+}
@@ -0,0 +1,176 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kirkegaard/terminal-pet/pkg/db/models"
+	"github.com/kirkegaard/terminal-pet/pkg/events"
+	"github.com/kirkegaard/terminal-pet/pkg/history"
+	"github.com/kirkegaard/terminal-pet/pkg/ui/games"
+	"github.com/kirkegaard/terminal-pet/pkg/ui/keymap"
+	"github.com/kirkegaard/terminal-pet/pkg/ui/views"
+)
+
+// DiarySize is how many of the latest entries of the activity log are shown
+const DiarySize = 200
+
+// ShowDiaryMsg is sent when the player wants to read their pet's diary
+type ShowDiaryMsg struct{}
+
+// CloseDiaryMsg is sent when the player leaves the diary
+type CloseDiaryMsg struct{}
+
+// Diary shows the activity log of a pet, newest first
+type Diary struct {
+	name   string
+	lines  []string
+	offset int
+	keys   keymap.KeyMap
+	width  int
+	height int
+}
+
+// NewDiary creates a diary screen from the latest entries of the activity
+// log of the named pet, newest first
+func NewDiary(name string, entries []models.PetEvent, width, height int) *Diary {
+	lines := make([]string, len(entries))
+	for i, e := range entries {
+		lines[i] = DiaryLine(e)
+	}
+
+	return &Diary{
+		name:   name,
+		lines:  lines,
+		keys:   keymap.Keys,
+		width:  width,
+		height: height,
+	}
+}
+
+// rows returns how many lines fit on the screen
+func (m *Diary) rows() int {
+	return max(m.height-9, 5)
+}
+
+// scroll moves the diary by the given number of lines
+func (m *Diary) scroll(lines int) {
+	m.offset = max(min(m.offset+lines, len(m.lines)-m.rows()), 0)
+}
+
+func (m *Diary) Init() tea.Cmd {
+	return nil
+}
+
+func (m *Diary) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case msg.String() == "esc", key.Matches(msg, m.keys.Quit), key.Matches(msg, m.keys.Action):
+			return m, func() tea.Msg { return CloseDiaryMsg{} }
+
+		case key.Matches(msg, m.keys.Up):
+			m.scroll(-1)
+
+		case key.Matches(msg, m.keys.Down):
+			m.scroll(1)
+
+		case msg.String() == "pgup", key.Matches(msg, m.keys.Left):
+			m.scroll(-m.rows())
+
+		case msg.String() == "pgdown", key.Matches(msg, m.keys.Right):
+			m.scroll(m.rows())
+		}
+
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.scroll(0)
+	}
+
+	return m, nil
+}
+
+func (m *Diary) View() string {
+	end := min(m.offset+m.rows(), len(m.lines))
+
+	return views.RenderDiary(
+		m.width,
+		m.name,
+		m.lines[m.offset:end],
+		m.offset,
+		len(m.lines),
+	)
+}
+
+// DiaryLine describes an entry of a pet's activity log, e.g.
+// "Oct 12 09:14 — fed Burger"
+func DiaryLine(e models.PetEvent) string {
+	return e.CreatedAt.Local().Format("Jan 2 15:04") + " — " + diaryText(e)
+}
+
+// diaryText describes what happened in an entry of the activity log
+func diaryText(e models.PetEvent) string {
+	// Things the pet did on its own may have happened without anyone there
+	away := ""
+	if e.Actor == events.ActorWorld {
+		away = " while you were away"
+	}
+
+	switch e.Kind {
+	case history.KindFed:
+		return strings.TrimSpace("fed " + e.Detail)
+
+	case history.KindPlayed:
+		id, score, ok := strings.Cut(e.Detail, " ")
+		if !ok {
+			return "played"
+		}
+		name := id
+		if g, ok := games.Get(id); ok {
+			name = g.Name
+		}
+		return "played " + name + ", scored " + score
+
+	case history.KindCleaned:
+		return "cleaned up the poop"
+
+	case history.KindMedicated:
+		if e.Detail == "cured" {
+			return "gave medicine, cured the sickness"
+		}
+		return "gave medicine"
+
+	case history.KindSick:
+		return "got sick" + away
+
+	case history.KindPooped:
+		return "pooped" + away
+
+	case history.KindGrew:
+		stage := strings.ToLower(e.Detail)
+		article := "a"
+		if strings.ContainsAny(stage[:min(1, len(stage))], "aeiou") {
+			article = "an"
+		}
+		return "grew up into " + article + " " + stage + away
+
+	case history.KindDied:
+		return "died of " + e.Detail + away
+
+	case history.KindRenamed:
+		return "renamed " + e.Detail
+
+	case history.KindAchievement:
+		return "unlocked " + e.Detail
+
+	case history.KindPlaydate:
+		return "had a playdate with " + e.Detail
+
+	case history.KindBaby:
+		return "had a baby named " + e.Detail
+	}
+
+	return strings.TrimSpace(e.Kind + " " + e.Detail)
+}
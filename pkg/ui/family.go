@@ -0,0 +1,73 @@
+package ui
+
+import (
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kirkegaard/terminal-pet/pkg/pet"
+	"github.com/kirkegaard/terminal-pet/pkg/ui/keymap"
+	"github.com/kirkegaard/terminal-pet/pkg/ui/views"
+)
+
+// ShowFamilyMsg is sent when the player wants to see their pet's family
+// tree
+type ShowFamilyMsg struct{}
+
+// CloseFamilyMsg is sent when the player leaves the family screen
+type CloseFamilyMsg struct{}
+
+// FamilyTree is a pet with its parents, the parents of each of them and
+// its babies
+type FamilyTree struct {
+	Pet          *pet.Pet
+	Parents      []*pet.Pet
+	Grandparents [][]*pet.Pet
+	Children     []*pet.Pet
+}
+
+// Family shows the genes and family tree of a pet
+type Family struct {
+	tree   FamilyTree
+	keys   keymap.KeyMap
+	width  int
+	height int
+}
+
+// NewFamily creates a family screen for a pet
+func NewFamily(tree FamilyTree, width, height int) *Family {
+	return &Family{
+		tree:   tree,
+		keys:   keymap.Keys,
+		width:  width,
+		height: height,
+	}
+}
+
+func (m *Family) Init() tea.Cmd {
+	return nil
+}
+
+func (m *Family) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case msg.String() == "esc", key.Matches(msg, m.keys.Quit), key.Matches(msg, m.keys.Action):
+			return m, func() tea.Msg { return CloseFamilyMsg{} }
+		}
+
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+	}
+
+	return m, nil
+}
+
+func (m *Family) View() string {
+	return views.RenderFamily(
+		m.width,
+		m.tree.Pet,
+		m.tree.Parents,
+		m.tree.Grandparents,
+		m.tree.Children,
+	)
+}
@@ -0,0 +1,272 @@
+package ui
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kirkegaard/terminal-pet/pkg/shop"
+	"github.com/kirkegaard/terminal-pet/pkg/ui/keymap"
+	"github.com/kirkegaard/terminal-pet/pkg/ui/views"
+)
+
+// ShowFriendsMsg is sent when the player wants to see their friends
+type ShowFriendsMsg struct{}
+
+// CloseFriendsMsg is sent when the player leaves the friends screen
+type CloseFriendsMsg struct{}
+
+// AddFriendMsg is sent when the player asks someone to be their friend or
+// accepts their request
+type AddFriendMsg struct {
+	Name string
+}
+
+// RemoveFriendMsg is sent when the player removes a friend, or declines or
+// withdraws a friend request
+type RemoveFriendMsg struct {
+	Name string
+}
+
+// SendGiftMsg is sent when the player gives one of their items to a friend
+type SendGiftMsg struct {
+	To   string
+	Item shop.Item
+}
+
+// Gift is an item another player sent
+type Gift struct {
+	From string
+	Item shop.Item
+}
+
+// GiftsReceivedMsg is sent to the pet screen with the gifts that arrived
+// while the player was away
+type GiftsReceivedMsg struct {
+	Gifts []Gift
+}
+
+// giftNotice describes the gifts the player received, e.g.
+// "🎁 alice sent you 🍎 Apple!"
+func giftNotice(gifts []Gift) string {
+	parts := make([]string, len(gifts))
+	for i, g := range gifts {
+		parts[i] = g.From + " sent you " + g.Item.Emoji + " " + g.Item.Name
+	}
+
+	return "🎁 " + strings.Join(parts, ", ") + "!"
+}
+
+// Friends lists the player's friends with the state of their pets, along
+// with friend requests. Friends can be added by name and sent items from
+// the player's inventory.
+type Friends struct {
+	friends []views.FriendEntry
+	owned   map[string]int
+	cursor  int
+	message string
+	failed  bool
+
+	// Typing the name of a new friend
+	adding bool
+	name   string
+
+	// Picking an item to give the selected friend
+	gifting    bool
+	giftCursor int
+
+	keys   keymap.KeyMap
+	width  int
+	height int
+}
+
+// NewFriends creates a friends screen. owned is how many of each item the
+// player has to give away.
+func NewFriends(friends []views.FriendEntry, owned map[string]int, width, height int) *Friends {
+	return &Friends{
+		friends: friends,
+		owned:   owned,
+		keys:    keymap.Keys,
+		width:   width,
+		height:  height,
+	}
+}
+
+// SetFriends updates the friends and items shown after a change, along with
+// a message about how it went
+func (m *Friends) SetFriends(friends []views.FriendEntry, owned map[string]int, message string, failed bool) {
+	m.friends = friends
+	m.owned = owned
+	m.message = message
+	m.failed = failed
+	m.cursor = min(m.cursor, max(len(friends)-1, 0))
+
+	// Stay in the prompt to fix a typo
+	if !failed {
+		m.adding = false
+		m.name = ""
+	}
+	m.gifting = false
+}
+
+// gifts returns the items the player can give away, in shop order
+func (m *Friends) gifts() []shop.Item {
+	var items []shop.Item
+	for _, item := range shop.Items() {
+		if m.owned[item.ID] > 0 {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
+
+func (m *Friends) Init() tea.Cmd {
+	return nil
+}
+
+func (m *Friends) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if m.adding {
+			return m.updatePrompt(msg)
+		}
+
+		if m.gifting {
+			return m.updateGift(msg)
+		}
+
+		switch {
+		case msg.String() == "esc", key.Matches(msg, m.keys.Quit):
+			return m, func() tea.Msg { return CloseFriendsMsg{} }
+
+		case key.Matches(msg, m.keys.Up):
+			if m.cursor > 0 {
+				m.cursor--
+			}
+			m.message = ""
+
+		case key.Matches(msg, m.keys.Down):
+			if m.cursor < len(m.friends)-1 {
+				m.cursor++
+			}
+			m.message = ""
+
+		case msg.String() == "a":
+			m.adding = true
+			m.message = ""
+
+		case msg.String() == "x":
+			if len(m.friends) > 0 {
+				name := m.friends[m.cursor].Name
+				return m, func() tea.Msg { return RemoveFriendMsg{Name: name} }
+			}
+
+		case key.Matches(msg, m.keys.Action):
+			if len(m.friends) == 0 {
+				return m, nil
+			}
+
+			selected := m.friends[m.cursor]
+			switch {
+			case selected.Pending && selected.Incoming:
+				return m, func() tea.Msg { return AddFriendMsg{Name: selected.Name} }
+			case selected.Pending:
+				m.message = selected.Name + " hasn't answered yet."
+				m.failed = true
+			case len(m.gifts()) == 0:
+				m.message = "You have nothing to give, buy something in the shop first."
+				m.failed = true
+			default:
+				m.gifting = true
+				m.giftCursor = 0
+				m.message = ""
+			}
+		}
+
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+	}
+
+	return m, nil
+}
+
+// updatePrompt handles typing the name of a new friend
+func (m *Friends) updatePrompt(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.adding = false
+		m.name = ""
+		m.message = ""
+	case "enter":
+		name := strings.TrimSpace(m.name)
+		if name == "" {
+			return m, nil
+		}
+		return m, func() tea.Msg { return AddFriendMsg{Name: name} }
+	case "backspace":
+		if len(m.name) > 0 {
+			runes := []rune(m.name)
+			m.name = string(runes[:len(runes)-1])
+		}
+	default:
+		if len(msg.Runes) == 1 && len([]rune(m.name)) < maxPlayerNameLength {
+			if unicode.IsPrint(msg.Runes[0]) {
+				m.name += string(msg.Runes)
+			}
+		}
+	}
+
+	return m, nil
+}
+
+// updateGift handles picking an item to give the selected friend
+func (m *Friends) updateGift(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	items := m.gifts()
+
+	switch {
+	case msg.String() == "esc", key.Matches(msg, m.keys.Quit):
+		m.gifting = false
+
+	case key.Matches(msg, m.keys.Left), key.Matches(msg, m.keys.Up):
+		if m.giftCursor > 0 {
+			m.giftCursor--
+		}
+
+	case key.Matches(msg, m.keys.Right), key.Matches(msg, m.keys.Down):
+		if m.giftCursor < len(items)-1 {
+			m.giftCursor++
+		}
+
+	case key.Matches(msg, m.keys.Action):
+		if m.giftCursor < len(items) {
+			to := m.friends[m.cursor].Name
+			item := items[m.giftCursor]
+			return m, func() tea.Msg { return SendGiftMsg{To: to, Item: item} }
+		}
+	}
+
+	return m, nil
+}
+
+func (m *Friends) View() string {
+	var items []shop.Item
+	if m.gifting {
+		items = m.gifts()
+	}
+
+	return views.RenderFriends(
+		m.width,
+		m.friends,
+		m.cursor,
+		m.adding,
+		m.name,
+		items,
+		m.owned,
+		m.giftCursor,
+		m.message,
+		m.failed,
+	)
+}
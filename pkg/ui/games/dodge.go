@@ -0,0 +1,193 @@
+package games
+
+import (
+	"math/rand"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kirkegaard/terminal-pet/pkg/pet"
+	"github.com/kirkegaard/terminal-pet/pkg/pet/ascii"
+	"github.com/kirkegaard/terminal-pet/pkg/ui/views"
+)
+
+// Dodge is a reaction game: jump as soon as a ball is thrown at the pet
+var Dodge = Game{
+	ID:          "dodge",
+	Name:        "Dodge",
+	Description: "Jump over the balls thrown at your pet, but not too early",
+	New: func(p *pet.Pet, width int) Minigame {
+		m := &dodge{
+			animations: p.Animations(),
+			width:      width,
+			lives:      dodgeLives,
+			result:     Result{MaxScore: dodgeRounds},
+		}
+		m.wait()
+		return m
+	},
+}
+
+const (
+	dodgeRounds = 8
+	dodgeLives  = 3
+
+	// dodgeTickRate is how often the ball moves
+	dodgeTickRate = time.Second / 10
+)
+
+// Phases of a dodge round
+const (
+	dodgeWaiting = iota
+	dodgeIncoming
+	dodgeOutcome
+)
+
+// dodgeTickMsg moves the ball of the game it was started by
+type dodgeTickMsg struct {
+	game *dodge
+}
+
+type dodge struct {
+	animations ascii.AnimationSet
+	width      int
+	tick       int
+
+	round  int
+	lives  int
+	result Result
+	over   bool
+
+	phase      int
+	launchAt   time.Time
+	launchedAt time.Time
+	nextAt     time.Time
+	dodged     bool
+	message    string
+}
+
+func (m *dodge) Init() tea.Cmd {
+	return m.tickCmd()
+}
+
+func (m *dodge) Result() Result {
+	return m.result
+}
+
+func (m *dodge) tickCmd() tea.Cmd {
+	return tea.Tick(dodgeTickRate, func(time.Time) tea.Msg {
+		return dodgeTickMsg{game: m}
+	})
+}
+
+// window is how long the pet has to jump, shrinking every round
+func (m *dodge) window() time.Duration {
+	return max(1200*time.Millisecond-time.Duration(m.round)*100*time.Millisecond, 500*time.Millisecond)
+}
+
+// wait starts a round, the ball is thrown after a random delay
+func (m *dodge) wait() {
+	m.phase = dodgeWaiting
+	m.launchAt = time.Now().Add(800*time.Millisecond + time.Duration(rand.Intn(2000))*time.Millisecond)
+	m.message = "Get ready..."
+}
+
+// end shows the outcome of the round
+func (m *dodge) end(dodged bool, message string) {
+	m.phase = dodgeOutcome
+	m.nextAt = time.Now().Add(ResultDisplayTime)
+	m.dodged = dodged
+	m.message = message
+
+	if dodged {
+		m.result.Score++
+		m.result.Happiness += 2
+	} else {
+		m.lives--
+	}
+}
+
+func (m *dodge) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case dodgeTickMsg:
+		if msg.game != m || m.over {
+			return m, nil
+		}
+
+		now := time.Now()
+		switch m.phase {
+		case dodgeWaiting:
+			if now.After(m.launchAt) {
+				m.phase = dodgeIncoming
+				m.launchedAt = now
+				m.message = "Jump!"
+			}
+		case dodgeIncoming:
+			if now.Sub(m.launchedAt) > m.window() {
+				m.end(false, "Ouch! Too slow")
+			}
+		case dodgeOutcome:
+			if now.After(m.nextAt) {
+				m.round++
+				if m.round >= dodgeRounds || m.lives <= 0 {
+					m.over = true
+					m.result.Weight = -2
+					if m.result.Score == dodgeRounds {
+						m.result.Happiness += 10
+					}
+					return m, finishNow(m)
+				}
+				m.wait()
+			}
+		}
+
+		return m, m.tickCmd()
+
+	case TickMsg:
+		m.tick++
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case " ", "up", "k":
+			switch m.phase {
+			case dodgeWaiting:
+				m.end(false, "Too early!")
+			case dodgeIncoming:
+				m.end(true, "Nice jump!")
+			}
+		}
+
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+	}
+
+	return m, nil
+}
+
+func (m *dodge) View() string {
+	// How far the ball has come, from 0 when thrown to 1 when it hits
+	ball := -1.0
+	if m.phase == dodgeIncoming {
+		ball = float64(time.Since(m.launchedAt)) / float64(m.window())
+	}
+
+	animation := m.animations.Playing
+	if m.phase == dodgeOutcome {
+		if m.dodged {
+			animation = m.animations.Happy
+		} else {
+			animation = m.animations.Sad
+		}
+	}
+
+	return views.RenderDodgeView(
+		m.width,
+		petFrame(animation, m.tick),
+		m.phase == dodgeOutcome && m.dodged,
+		ball,
+		m.round,
+		dodgeRounds,
+		m.result.Score,
+		m.lives,
+		m.message,
+	)
+}
@@ -0,0 +1,99 @@
+// Package games holds the minigames a player can play with their pet. Every
+// game is its own Bubble Tea model; the pet UI starts one from the game
+// picker, forwards it messages and applies its Result once it sends a
+// FinishedMsg.
+package games
+
+import (
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kirkegaard/terminal-pet/pkg/pet"
+	"github.com/kirkegaard/terminal-pet/pkg/pet/ascii"
+)
+
+// ResultDisplayTime is how long games show the outcome of a round, and of
+// the final round before they finish
+const ResultDisplayTime = time.Second
+
+// Result is the outcome of a game and its effect on the pet
+type Result struct {
+	Score    int
+	MaxScore int
+
+	// Happiness and Weight are added to the pet's stats
+	Happiness int
+	Weight    int
+}
+
+// Minigame is a game in progress
+type Minigame interface {
+	tea.Model
+
+	// Result returns the outcome of the game so far
+	Result() Result
+}
+
+// TickMsg is forwarded to the running game on every animation frame of the
+// pet UI
+type TickMsg time.Time
+
+// FinishedMsg is sent by a game when it is over
+type FinishedMsg struct {
+	Game Minigame
+}
+
+// Game describes a minigame in the game picker
+type Game struct {
+	ID          string
+	Name        string
+	Description string
+
+	// New starts a game with the pet. The pet is only read, the pet UI
+	// applies the result when the game is over.
+	New func(p *pet.Pet, width int) Minigame
+}
+
+// All returns every game in the order they are offered in the game picker
+func All() []Game {
+	return []Game{
+		HigherLower,
+		Dodge,
+		Memory,
+		RockPaperScissors,
+	}
+}
+
+// Get returns the game with the given ID
+func Get(id string) (Game, bool) {
+	for _, g := range All() {
+		if g.ID == id {
+			return g, true
+		}
+	}
+
+	return Game{}, false
+}
+
+// finish sends a FinishedMsg for the game after the last round was shown
+func finish(game Minigame) tea.Cmd {
+	return tea.Tick(ResultDisplayTime, func(time.Time) tea.Msg {
+		return FinishedMsg{Game: game}
+	})
+}
+
+// finishNow sends a FinishedMsg for the game right away
+func finishNow(game Minigame) tea.Cmd {
+	return func() tea.Msg {
+		return FinishedMsg{Game: game}
+	}
+}
+
+// petFrame returns the frame of the animation to show on the given tick
+func petFrame(animation ascii.Animation, tick int) string {
+	if len(animation.Frames) == 0 {
+		return ""
+	}
+
+	return animation.Frames[tick%len(animation.Frames)]
+}
@@ -0,0 +1,139 @@
+package games
+
+import (
+	"math/rand"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kirkegaard/terminal-pet/pkg/pet"
+	"github.com/kirkegaard/terminal-pet/pkg/pet/ascii"
+	"github.com/kirkegaard/terminal-pet/pkg/ui/views"
+)
+
+// HigherLower is the classic guessing game: will the next number be higher
+// or lower?
+var HigherLower = Game{
+	ID:          "higherlower",
+	Name:        "Higher or Lower",
+	Description: "Guess whether the next number is higher or lower",
+	New: func(p *pet.Pet, width int) Minigame {
+		return &higherLower{
+			animations:  p.Animations(),
+			width:       width,
+			number:      rand.Intn(9) + 1,
+			guessesLeft: higherLowerGuesses,
+			result:      Result{MaxScore: higherLowerMaxScore},
+		}
+	},
+}
+
+const (
+	higherLowerGuesses  = 5
+	higherLowerMaxScore = 5
+)
+
+type higherLower struct {
+	animations ascii.AnimationSet
+	width      int
+	tick       int
+
+	number      int
+	guessesLeft int
+	result      Result
+	over        bool
+
+	// The outcome of the last guess, shown for ResultDisplayTime
+	showResult  bool
+	lastCorrect bool
+	lastNumber  int
+	guessedAt   time.Time
+}
+
+func (m *higherLower) Init() tea.Cmd {
+	return nil
+}
+
+func (m *higherLower) Result() Result {
+	return m.result
+}
+
+func (m *higherLower) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case TickMsg:
+		m.tick++
+		if m.showResult && !m.over && time.Since(m.guessedAt) >= ResultDisplayTime {
+			m.showResult = false
+		}
+
+	case tea.KeyMsg:
+		if m.over {
+			return m, nil
+		}
+
+		switch msg.String() {
+		case "h", "left":
+			return m, m.guess(false)
+		case "l", "right":
+			return m, m.guess(true)
+		}
+
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+	}
+
+	return m, nil
+}
+
+// guess checks the player's guess against the next number
+func (m *higherLower) guess(higher bool) tea.Cmd {
+	next := m.number
+	for next == m.number {
+		next = rand.Intn(9) + 1
+	}
+
+	// Nothing is higher than 9 or lower than 1
+	correct := (m.number == 1 && higher) || (m.number == 9 && !higher) ||
+		(higher && next > m.number) || (!higher && next < m.number)
+
+	m.guessesLeft--
+	m.lastNumber = m.number
+	m.lastCorrect = correct
+	m.showResult = true
+	m.guessedAt = time.Now()
+	m.number = next
+
+	m.result.Weight--
+	if correct {
+		m.result.Score++
+		m.result.Happiness += 2
+	} else {
+		m.result.Happiness--
+	}
+
+	if m.guessesLeft > 0 && m.result.Score < higherLowerMaxScore {
+		return nil
+	}
+
+	m.over = true
+	m.result.Happiness += m.result.Score * 5
+
+	return finish(m)
+}
+
+func (m *higherLower) View() string {
+	return views.RenderGameView(
+		"",
+		m.width,
+		m.animations,
+		m.tick,
+		"playing",
+		0,
+		m.showResult,
+		m.lastCorrect,
+		m.number,
+		m.lastNumber,
+		m.guessesLeft,
+		m.result.Score,
+		!m.over,
+	)
+}
@@ -0,0 +1,200 @@
+package games
+
+import (
+	"math/rand"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kirkegaard/terminal-pet/pkg/pet"
+	"github.com/kirkegaard/terminal-pet/pkg/pet/ascii"
+	"github.com/kirkegaard/terminal-pet/pkg/ui/views"
+)
+
+// Memory is a Simon-style game: repeat the pet's ever longer sequence of
+// arrows
+var Memory = Game{
+	ID:          "memory",
+	Name:        "Memory",
+	Description: "Repeat the sequence of arrows your pet shows you",
+	New: func(p *pet.Pet, width int) Minigame {
+		m := &memory{
+			animations: p.Animations(),
+			width:      width,
+			result:     Result{MaxScore: memoryMaxLength},
+		}
+		m.next()
+		return m
+	},
+}
+
+const (
+	memoryStartLength = 2
+	memoryMaxLength   = 8
+
+	// memoryTickRate is how long each arrow, and each gap between arrows,
+	// is shown
+	memoryTickRate = 400 * time.Millisecond
+)
+
+// memoryArrows are the symbols of a sequence along with the keys that enter
+// them
+var memoryArrows = []struct {
+	symbol string
+	keys   []string
+}{
+	{"←", []string{"left", "h"}},
+	{"↑", []string{"up", "k"}},
+	{"→", []string{"right", "l"}},
+	{"↓", []string{"down", "j"}},
+}
+
+// memoryTickMsg advances the sequence shown by the game it was started by
+type memoryTickMsg struct {
+	game *memory
+}
+
+type memory struct {
+	animations ascii.AnimationSet
+	width      int
+	tick       int
+
+	sequence []int
+	entered  []int
+	result   Result
+	over     bool
+
+	// showing is true while the sequence is played back, step counts the
+	// arrows and gaps shown so far
+	showing bool
+	step    int
+	message string
+}
+
+func (m *memory) Init() tea.Cmd {
+	return m.tickCmd()
+}
+
+func (m *memory) Result() Result {
+	return m.result
+}
+
+func (m *memory) tickCmd() tea.Cmd {
+	return tea.Tick(memoryTickRate, func(time.Time) tea.Msg {
+		return memoryTickMsg{game: m}
+	})
+}
+
+// next grows the sequence and plays it back
+func (m *memory) next() {
+	for len(m.sequence) < memoryStartLength || len(m.sequence) == m.result.Score {
+		m.sequence = append(m.sequence, rand.Intn(len(memoryArrows)))
+	}
+
+	m.entered = nil
+	m.showing = true
+	m.step = -2 // A short pause before the first arrow
+	m.message = "Watch closely..."
+}
+
+func (m *memory) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case memoryTickMsg:
+		if msg.game != m || m.over || !m.showing {
+			return m, nil
+		}
+
+		m.step++
+		if m.step >= len(m.sequence)*2 {
+			m.showing = false
+			m.message = "Your turn!"
+			return m, nil
+		}
+
+		return m, m.tickCmd()
+
+	case TickMsg:
+		m.tick++
+
+	case tea.KeyMsg:
+		if m.over || m.showing {
+			return m, nil
+		}
+
+		for i, arrow := range memoryArrows {
+			for _, k := range arrow.keys {
+				if msg.String() == k {
+					return m, m.enter(i)
+				}
+			}
+		}
+
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+	}
+
+	return m, nil
+}
+
+// enter checks the next arrow entered by the player
+func (m *memory) enter(arrow int) tea.Cmd {
+	expected := m.sequence[len(m.entered)]
+	m.entered = append(m.entered, arrow)
+
+	if arrow != expected {
+		m.over = true
+		m.message = "Wrong! It was " + memoryArrows[expected].symbol
+		return finish(m)
+	}
+
+	if len(m.entered) < len(m.sequence) {
+		return nil
+	}
+
+	m.result.Score = len(m.sequence)
+	m.result.Happiness += 3
+
+	if m.result.Score >= memoryMaxLength {
+		m.over = true
+		m.result.Happiness += 10
+		m.message = "Perfect memory!"
+		return finish(m)
+	}
+
+	m.next()
+	m.message = "Well remembered! Watch closely..."
+
+	return m.tickCmd()
+}
+
+func (m *memory) View() string {
+	// Every even step shows an arrow, odd steps are gaps between them
+	shown := ""
+	if m.showing && m.step >= 0 && m.step%2 == 0 {
+		shown = memoryArrows[m.sequence[m.step/2]].symbol
+	}
+
+	entered := make([]string, len(m.entered))
+	for i, arrow := range m.entered {
+		entered[i] = memoryArrows[arrow].symbol
+	}
+
+	animation := m.animations.Playing
+	if m.over {
+		if m.result.Score >= memoryMaxLength {
+			animation = m.animations.Happy
+		} else {
+			animation = m.animations.Sad
+		}
+	}
+
+	return views.RenderMemoryView(
+		m.width,
+		petFrame(animation, m.tick),
+		shown,
+		entered,
+		len(m.sequence),
+		m.result.Score,
+		memoryMaxLength,
+		m.message,
+	)
+}
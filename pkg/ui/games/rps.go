@@ -0,0 +1,150 @@
+package games
+
+import (
+	"math/rand"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kirkegaard/terminal-pet/pkg/pet"
+	"github.com/kirkegaard/terminal-pet/pkg/pet/ascii"
+	"github.com/kirkegaard/terminal-pet/pkg/ui/views"
+)
+
+// RockPaperScissors is played against the pet over a few rounds
+var RockPaperScissors = Game{
+	ID:          "rps",
+	Name:        "Rock Paper Scissors",
+	Description: "Beat your pet at rock, paper, scissors",
+	New: func(p *pet.Pet, width int) Minigame {
+		return &rockPaperScissors{
+			name:       p.Name,
+			animations: p.Animations(),
+			width:      width,
+			player:     -1,
+			result:     Result{MaxScore: rpsRounds},
+		}
+	},
+}
+
+const rpsRounds = 5
+
+// rpsHands are the hands that can be played along with the keys that play
+// them. Every hand beats the one before it.
+var rpsHands = []struct {
+	name string
+	keys []string
+}{
+	{"🪨 Rock", []string{"r", "1"}},
+	{"📄 Paper", []string{"p", "2"}},
+	{"✂️ Scissors", []string{"s", "3"}},
+}
+
+type rockPaperScissors struct {
+	name       string
+	animations ascii.AnimationSet
+	width      int
+	tick       int
+
+	round  int
+	draws  int
+	result Result
+	over   bool
+
+	// The hands of the last round, -1 before the first one
+	player  int
+	petHand int
+	outcome int
+}
+
+func (m *rockPaperScissors) Init() tea.Cmd {
+	return nil
+}
+
+func (m *rockPaperScissors) Result() Result {
+	return m.result
+}
+
+func (m *rockPaperScissors) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case TickMsg:
+		m.tick++
+
+	case tea.KeyMsg:
+		if m.over {
+			return m, nil
+		}
+
+		for i, hand := range rpsHands {
+			for _, k := range hand.keys {
+				if msg.String() == k {
+					return m, m.play(i)
+				}
+			}
+		}
+
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+	}
+
+	return m, nil
+}
+
+// play plays a round with the player's hand against a random one
+func (m *rockPaperScissors) play(hand int) tea.Cmd {
+	m.player = hand
+	m.petHand = rand.Intn(len(rpsHands))
+	m.round++
+
+	// 1 if the player won, -1 if the pet won and 0 for a draw
+	m.outcome = (hand - m.petHand + len(rpsHands)) % len(rpsHands)
+	if m.outcome == 2 {
+		m.outcome = -1
+	}
+
+	switch m.outcome {
+	case 1:
+		m.result.Score++
+		m.result.Happiness += 4
+	case 0:
+		m.draws++
+		m.result.Happiness++
+	}
+
+	if m.round < rpsRounds {
+		return nil
+	}
+
+	m.over = true
+
+	return finish(m)
+}
+
+func (m *rockPaperScissors) View() string {
+	animation := m.animations.Playing
+	player, petHand := "", ""
+
+	if m.player >= 0 {
+		player = rpsHands[m.player].name
+		petHand = rpsHands[m.petHand].name
+
+		// The pet is happy when it wins
+		switch m.outcome {
+		case 1:
+			animation = m.animations.Sad
+		case -1:
+			animation = m.animations.Happy
+		}
+	}
+
+	return views.RenderRockPaperScissorsView(
+		m.width,
+		m.name,
+		petFrame(animation, m.tick),
+		player,
+		petHand,
+		m.outcome,
+		m.round,
+		rpsRounds,
+		m.result.Score,
+		m.draws,
+	)
+}
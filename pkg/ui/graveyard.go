@@ -0,0 +1,127 @@
+package ui
+
+import (
+	"unicode"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kirkegaard/terminal-pet/pkg/pet"
+	"github.com/kirkegaard/terminal-pet/pkg/ui/keymap"
+	"github.com/kirkegaard/terminal-pet/pkg/ui/views"
+)
+
+// MaxEpitaphLength is the longest epitaph a player can write
+const MaxEpitaphLength = 60
+
+// ShowGraveyardMsg is sent when the player wants to visit the graveyard
+type ShowGraveyardMsg struct{}
+
+// CloseGraveyardMsg is sent when the player leaves the graveyard
+type CloseGraveyardMsg struct{}
+
+// EpitaphWrittenMsg is sent when the player writes an epitaph for a pet
+type EpitaphWrittenMsg struct {
+	Pet     *pet.Pet
+	Epitaph string
+}
+
+// Graveyard lists a player's deceased pets and lets them write epitaphs
+type Graveyard struct {
+	pets   []*pet.Pet
+	cursor int
+	keys   keymap.KeyMap
+	width  int
+	height int
+
+	// Writing an epitaph
+	inEpitaphMode bool
+	epitaph       string
+}
+
+// NewGraveyard creates a new graveyard screen
+func NewGraveyard(pets []*pet.Pet, width, height int) *Graveyard {
+	return &Graveyard{
+		pets:   pets,
+		keys:   keymap.Keys,
+		width:  width,
+		height: height,
+	}
+}
+
+func (m *Graveyard) Init() tea.Cmd {
+	return nil
+}
+
+func (m *Graveyard) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		if m.inEpitaphMode {
+			return m.updateEpitaphMode(msg)
+		}
+
+		switch {
+		case msg.String() == "esc", key.Matches(msg, m.keys.Quit):
+			return m, func() tea.Msg { return CloseGraveyardMsg{} }
+
+		case key.Matches(msg, m.keys.Up):
+			if m.cursor > 0 {
+				m.cursor--
+			}
+
+		case key.Matches(msg, m.keys.Down):
+			if m.cursor < len(m.pets)-1 {
+				m.cursor++
+			}
+
+		case key.Matches(msg, m.keys.Action):
+			if len(m.pets) > 0 {
+				m.inEpitaphMode = true
+				m.epitaph = m.pets[m.cursor].Epitaph
+			}
+		}
+
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+	}
+
+	return m, nil
+}
+
+// updateEpitaphMode handles typing an epitaph for the selected pet
+func (m *Graveyard) updateEpitaphMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.inEpitaphMode = false
+		m.epitaph = ""
+	case "enter":
+		selected := m.pets[m.cursor]
+		selected.Epitaph = m.epitaph
+		m.inEpitaphMode = false
+		m.epitaph = ""
+		return m, func() tea.Msg { return EpitaphWrittenMsg{Pet: selected, Epitaph: selected.Epitaph} }
+	case "backspace":
+		if len(m.epitaph) > 0 {
+			runes := []rune(m.epitaph)
+			m.epitaph = string(runes[:len(runes)-1])
+		}
+	default:
+		if len(msg.Runes) == 1 && len([]rune(m.epitaph)) < MaxEpitaphLength {
+			if unicode.IsPrint(msg.Runes[0]) {
+				m.epitaph += string(msg.Runes)
+			}
+		}
+	}
+
+	return m, nil
+}
+
+func (m *Graveyard) View() string {
+	return views.RenderGraveyard(
+		m.width,
+		m.pets,
+		m.cursor,
+		m.inEpitaphMode,
+		m.epitaph,
+	)
+}
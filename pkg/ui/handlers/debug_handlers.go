@@ -44,7 +44,7 @@ func ExecuteDebugAction(
 		p.HasPooped = !p.HasPooped
 	case 2: // Toggle Dead
 		if p.Health <= 0 {
-			p.Health = 50 // Revive
+			p.Revive(50)
 		} else {
 			p.Health = 0 // Kill
 			inGameOver = true
@@ -85,7 +85,7 @@ func ExecuteDebugAction(
 	}
 
 	// Get the updated animation based on the new pet state
-	newAnim := ascii.GetAnimationForState(p.GetState())
+	newAnim := p.Animations().ForState(p.GetState())
 
 	return p, debugMode, inDebugMenu, inGameOver, gameOverCursor, newAnim
 }
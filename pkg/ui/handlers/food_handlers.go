@@ -1,9 +1,5 @@
 package handlers
 
-import (
-	"github.com/kirkegaard/terminal-pet/pkg/pet"
-)
-
 func HandleFoodSubmenu(key string, cursor int, optionCount int) (keepSubmenu bool, newCursor int) {
 	newCursor = cursor
 
@@ -45,23 +41,3 @@ func HandleFoodSelection(key string, cursor int, optionCount int) (stayInFoodMod
 
 	return true, newCursor, false
 }
-
-func FeedPet(foodIndex int, petObj *pet.Pet) (string, *pet.Pet) {
-	switch foodIndex {
-	case 0: // Burger
-		if petObj.Hunger <= 0 {
-			return "idle", petObj
-		}
-		burger := pet.Burger()
-		petObj.Feed(burger)
-		petObj.Weight += 2
-		return "eating", petObj
-	case 1: // Cake
-		cake := pet.Cake()
-		petObj.Feed(cake)
-		petObj.Weight += 5
-		return "cakeEating", petObj
-	}
-
-	return "idle", petObj
-}
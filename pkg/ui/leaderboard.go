@@ -0,0 +1,82 @@
+package ui
+
+import (
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kirkegaard/terminal-pet/pkg/db/models"
+	"github.com/kirkegaard/terminal-pet/pkg/ui/games"
+	"github.com/kirkegaard/terminal-pet/pkg/ui/keymap"
+	"github.com/kirkegaard/terminal-pet/pkg/ui/views"
+)
+
+// LeaderboardSize is how many players are listed for each game
+const LeaderboardSize = 10
+
+// ShowLeaderboardMsg is sent when the player wants to see the high scores
+type ShowLeaderboardMsg struct{}
+
+// CloseLeaderboardMsg is sent when the player leaves the leaderboard
+type CloseLeaderboardMsg struct{}
+
+// Leaderboard lists the best players of each minigame, one game at a time
+type Leaderboard struct {
+	games  []games.Game
+	scores map[string][]models.GameScore
+	userID int
+	cursor int
+	keys   keymap.KeyMap
+	width  int
+	height int
+}
+
+// NewLeaderboard creates a new leaderboard screen from the top scores of
+// each game by game ID. The scores of the given user are highlighted.
+func NewLeaderboard(scores map[string][]models.GameScore, userID int, width, height int) *Leaderboard {
+	return &Leaderboard{
+		games:  games.All(),
+		scores: scores,
+		userID: userID,
+		keys:   keymap.Keys,
+		width:  width,
+		height: height,
+	}
+}
+
+func (m *Leaderboard) Init() tea.Cmd {
+	return nil
+}
+
+func (m *Leaderboard) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case msg.String() == "esc", key.Matches(msg, m.keys.Quit), key.Matches(msg, m.keys.Action):
+			return m, func() tea.Msg { return CloseLeaderboardMsg{} }
+
+		case key.Matches(msg, m.keys.Left), key.Matches(msg, m.keys.Up):
+			m.cursor = (m.cursor + len(m.games) - 1) % len(m.games)
+
+		case key.Matches(msg, m.keys.Right), key.Matches(msg, m.keys.Down):
+			m.cursor = (m.cursor + 1) % len(m.games)
+		}
+
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+	}
+
+	return m, nil
+}
+
+func (m *Leaderboard) View() string {
+	game := m.games[m.cursor]
+
+	return views.RenderLeaderboard(
+		m.width,
+		game.Name,
+		m.cursor,
+		len(m.games),
+		m.scores[game.ID],
+		m.userID,
+	)
+}
@@ -0,0 +1,201 @@
+package ui
+
+import (
+	"unicode"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kirkegaard/terminal-pet/pkg/pet"
+	"github.com/kirkegaard/terminal-pet/pkg/ui/keymap"
+	"github.com/kirkegaard/terminal-pet/pkg/ui/views"
+)
+
+// PetSelectedMsg is sent when the player picks one of their pets
+type PetSelectedMsg struct {
+	Pet *pet.Pet
+}
+
+// AdoptPetMsg is sent when the player wants to adopt a new pet
+type AdoptPetMsg struct {
+	Name    string
+	Species string
+}
+
+// PetPicker lets a player choose which of their pets to play with, or adopt
+// a new one
+type PetPicker struct {
+	pets    []*pet.Pet
+	maxPets int
+	cursor  int
+	keys    keymap.KeyMap
+	width   int
+	height  int
+
+	// message explains why the last pick didn't go through
+	message string
+
+	// Naming a new pet and choosing its species
+	inNameMode    bool
+	newName       string
+	inSpeciesMode bool
+	speciesCursor int
+}
+
+// NewPetPicker creates a new pet picker
+func NewPetPicker(pets []*pet.Pet, maxPets int, width, height int) *PetPicker {
+	return &PetPicker{
+		pets:    pets,
+		maxPets: maxPets,
+		keys:    keymap.Keys,
+		width:   width,
+		height:  height,
+	}
+}
+
+// CanAdopt returns whether the player has room for another pet
+func (m *PetPicker) CanAdopt() bool {
+	return len(m.pets) < m.maxPets
+}
+
+// optionCount returns the number of selectable rows: one per pet, adopt,
+// graveyard and quit
+func (m *PetPicker) optionCount() int {
+	return len(m.pets) + 3
+}
+
+// ShowMessage shows a message below the pets until the next key press
+func (m *PetPicker) ShowMessage(message string) {
+	m.message = message
+}
+
+func (m *PetPicker) Init() tea.Cmd {
+	return nil
+}
+
+func (m *PetPicker) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		m.message = ""
+
+		if m.inSpeciesMode {
+			return m.updateSpeciesMode(msg)
+		}
+
+		if m.inNameMode {
+			return m.updateNameMode(msg)
+		}
+
+		switch {
+		case key.Matches(msg, m.keys.Quit):
+			return m, func() tea.Msg { return QuitMsg{} }
+
+		case key.Matches(msg, m.keys.Up):
+			if m.cursor > 0 {
+				m.cursor--
+			}
+
+		case key.Matches(msg, m.keys.Down):
+			if m.cursor < m.optionCount()-1 {
+				m.cursor++
+			}
+
+		case key.Matches(msg, m.keys.Action):
+			switch {
+			case m.cursor < len(m.pets):
+				selected := m.pets[m.cursor]
+				return m, func() tea.Msg { return PetSelectedMsg{Pet: selected} }
+			case m.cursor == len(m.pets):
+				if m.CanAdopt() {
+					m.inNameMode = true
+					m.newName = ""
+				}
+			case m.cursor == len(m.pets)+1:
+				return m, func() tea.Msg { return ShowGraveyardMsg{} }
+			default:
+				return m, func() tea.Msg { return QuitMsg{} }
+			}
+		}
+
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+	}
+
+	return m, nil
+}
+
+// updateNameMode handles typing the name of a new pet
+func (m *PetPicker) updateNameMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		m.inNameMode = false
+		m.newName = ""
+	case "enter":
+		if len(m.newName) > 0 {
+			m.inNameMode = false
+			m.inSpeciesMode = true
+			m.speciesCursor = 0
+		}
+	case "backspace":
+		if len(m.newName) > 0 {
+			m.newName = m.newName[:len(m.newName)-1]
+		}
+	default:
+		if len(msg.String()) == 1 && len(m.newName) < 20 {
+			r := []rune(msg.String())[0]
+			if unicode.IsPrint(r) {
+				m.newName += msg.String()
+			}
+		}
+	}
+
+	return m, nil
+}
+
+// updateSpeciesMode handles choosing the species of a new pet
+func (m *PetPicker) updateSpeciesMode(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	species := pet.AllSpecies()
+
+	switch {
+	case msg.String() == "esc":
+		// Go back to naming the pet
+		m.inSpeciesMode = false
+		m.inNameMode = true
+
+	case key.Matches(msg, m.keys.Up):
+		if m.speciesCursor > 0 {
+			m.speciesCursor--
+		}
+
+	case key.Matches(msg, m.keys.Down):
+		if m.speciesCursor < len(species)-1 {
+			m.speciesCursor++
+		}
+
+	case key.Matches(msg, m.keys.Action):
+		name := m.newName
+		chosen := species[m.speciesCursor].ID
+		m.inSpeciesMode = false
+		m.newName = ""
+		return m, func() tea.Msg { return AdoptPetMsg{Name: name, Species: chosen} }
+	}
+
+	return m, nil
+}
+
+func (m *PetPicker) View() string {
+	if m.inSpeciesMode {
+		return views.RenderSpeciesPicker(m.width, m.newName, pet.AllSpecies(), m.speciesCursor)
+	}
+
+	return views.RenderPetPicker(
+		m.width,
+		m.pets,
+		m.cursor,
+		m.maxPets,
+		m.CanAdopt(),
+		m.inNameMode,
+		m.newName,
+		m.message,
+	)
+}
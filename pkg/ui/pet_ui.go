@@ -1,6 +1,8 @@
 package ui
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"math/rand"
 	"strings"
@@ -11,9 +13,15 @@ import (
 	"github.com/charmbracelet/bubbles/key"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
-	// "github.com/charmbracelet/log"
+	"github.com/charmbracelet/log"
+	"github.com/kirkegaard/terminal-pet/pkg/achievements"
+	"github.com/kirkegaard/terminal-pet/pkg/actions"
+	"github.com/kirkegaard/terminal-pet/pkg/db/models"
+	"github.com/kirkegaard/terminal-pet/pkg/db/repo"
+	"github.com/kirkegaard/terminal-pet/pkg/events"
 	"github.com/kirkegaard/terminal-pet/pkg/pet"
 	"github.com/kirkegaard/terminal-pet/pkg/pet/ascii"
+	"github.com/kirkegaard/terminal-pet/pkg/ui/games"
 	"github.com/kirkegaard/terminal-pet/pkg/ui/handlers"
 	"github.com/kirkegaard/terminal-pet/pkg/ui/keymap"
 	"github.com/kirkegaard/terminal-pet/pkg/ui/views"
@@ -24,14 +32,60 @@ type QuitMsg struct{}
 
 type FrameMsg time.Time
 
+// AchievementUnlockedMsg shows a toast for an achievement the owner unlocked
+type AchievementUnlockedMsg struct {
+	Achievement achievements.Achievement
+}
+
+const AnimationTickRate = time.Second / 2
+
+var choices = []string{"Feed", "Clean", "Play", "Medicine", "Scold", "Praise", "Rename", "Shop", "Scores", "Badges", "Diary", "Stats", "Family", "Visit", "Friends", "Playdate", "Toggle Lights", "Quit"}
+
+// Menu choice indexes
 const (
-	AnimationTickRate = time.Second / 2
-	ResultDisplayTime = 1000
+	menuFeed = iota
+	menuClean
+	menuPlay
+	menuMedicine
+	menuScold
+	menuPraise
+	menuRename
+	menuShop
+	menuScores
+	menuBadges
+	menuDiary
+	menuStats
+	menuFamily
+	menuVisit
+	menuFriends
+	menuPlaydate
+	menuLights
+	menuQuit
 )
 
-type GameResultTimeoutMsg struct{}
+// menuActions maps the menu choices to their action. The other choices have
+// none and can always be picked. Playdates need a pet that can play.
+var menuActions = map[int]string{
+	menuFeed:     actions.Feed,
+	menuClean:    actions.Clean,
+	menuPlay:     actions.Play,
+	menuMedicine: actions.Medicine,
+	menuScold:    actions.Scold,
+	menuPraise:   actions.Praise,
+	menuRename:   actions.Rename,
+	menuPlaydate: actions.Play,
+	menuLights:   actions.Lights,
+}
+
+// NoticeDisplayTime is how long a notice stays below the menu
+const NoticeDisplayTime = 3 * time.Second
 
-var choices = []string{"Feed", "Clean", "Play", "Medicine", "Rename", "Toggle Lights", "Quit"}
+// GiftNoticeDisplayTime is how long the notice about received gifts stays
+// below the menu
+const GiftNoticeDisplayTime = 10 * time.Second
+
+// ToastDisplayTime is how long an unlocked achievement is shown
+const ToastDisplayTime = 4 * time.Second
 
 var infoStyle = lipgloss.NewStyle().
 	Foreground(lipgloss.Color("#888888")).
@@ -40,6 +94,13 @@ var infoStyle = lipgloss.NewStyle().
 
 type PetUI struct {
 	pet                *pet.Pet
+	sim                *pet.Simulator
+	pets               repo.PetStore
+	actions            *actions.Service
+	inventory          repo.InventoryStore
+	scores             repo.ScoreStore
+	achievements       *achievements.Engine
+	events             *events.Bus
 	currentAnim        ascii.Animation
 	currentFrame       int
 	lastUpdateTime     time.Time
@@ -62,16 +123,20 @@ type PetUI struct {
 	targetPosition int
 	moveDirection  int
 
-	// Game state
-	inGame          bool
-	gameNumber      int
-	gameGuessesLeft int
-	gameScore       int
-
-	// Game result display
-	lastGuessWasCorrect bool
-	lastNumber          int
-	showResult          bool
+	// Minigames: the game picker, the game being played and the outcome of
+	// the last one
+	inGamePicker bool
+	gameCursor   int
+	game         games.Minigame
+	gameInfo     games.Game
+	outcome      *gameOutcome
+
+	// Achievements: the badges screen and the unlocks waiting to be shown,
+	// the first one is shown since toastTime
+	inBadges  bool
+	badges    []achievements.Status
+	toasts    []achievements.Achievement
+	toastTime time.Time
 
 	// Game over state
 	inGameOver       bool
@@ -96,6 +161,15 @@ type PetUI struct {
 	// Inline food submenu
 	showFoodSubmenu   bool
 	foodSubmenuCursor int
+
+	// Coins and items of the owner
+	coins int
+	owned map[string]int
+
+	// Notice shown below the menu, e.g. when running out of food
+	notice     string
+	noticeTime time.Time
+	noticeFor  time.Duration
 }
 
 // GetPet returns the pet reference
@@ -108,6 +182,11 @@ func (m *PetUI) ResetRestartFlag() {
 	m.justRestarted = false
 }
 
+// InGame reports whether the player is in the middle of a minigame
+func (m *PetUI) InGame() bool {
+	return m.game != nil
+}
+
 // SetGameOver sets the game over state to the specified value
 func (m *PetUI) SetGameOver(isGameOver bool) {
 	m.inGameOver = isGameOver
@@ -117,14 +196,14 @@ func (m *PetUI) SetGameOver(isGameOver bool) {
 }
 
 // NewPetUI creates a new pet UI
-func NewPetUI(p *pet.Pet, width, height int) *PetUI {
-	anim := ascii.GetAnimationForState(p.GetState())
+func NewPetUI(p *pet.Pet, sim *pet.Simulator, pets repo.PetStore, service *actions.Service, scores repo.ScoreStore, engine *achievements.Engine, bus *events.Bus, width, height int) *PetUI {
+	anim := p.Animations().ForState(p.GetState())
 
 	// Check if pet is already dead when loading and set initial game over state
 	inGameOver := p.IsDead()
 	initialCursor := 0
 	if inGameOver {
-		anim = ascii.GetAnimationForState(ascii.StateDead)
+		anim = p.Animations().Dead
 	}
 
 	now := time.Now()
@@ -133,8 +212,15 @@ func NewPetUI(p *pet.Pet, width, height int) *PetUI {
 	helpModel := help.New()
 	helpModel.ShowAll = true
 
-	return &PetUI{
+	m := &PetUI{
 		pet:                p,
+		sim:                sim,
+		pets:               pets,
+		actions:            service,
+		inventory:          service.Inventory(),
+		scores:             scores,
+		achievements:       engine,
+		events:             bus,
 		currentAnim:        anim,
 		currentFrame:       0,
 		keys:               keymap.Keys,
@@ -157,17 +243,9 @@ func NewPetUI(p *pet.Pet, width, height int) *PetUI {
 		targetPosition: 0,
 		moveDirection:  0,
 
-		// Game state
-		inGame:              false,
-		gameNumber:          0,
-		gameGuessesLeft:     0,
-		gameScore:           0,
-		lastGuessWasCorrect: false,
-		lastNumber:          0,
-		showResult:          false,
-		debugMode:           false,
-		inDebugMenu:         false,
-		debugCursor:         0,
+		debugMode:   false,
+		inDebugMenu: false,
+		debugCursor: 0,
 
 		// Game over state - initialize from pet status
 		inGameOver:       inGameOver,
@@ -181,12 +259,224 @@ func NewPetUI(p *pet.Pet, width, height int) *PetUI {
 		// Food selection mode
 		inFoodSelectMode: false,
 		foodCursor:       0,
-		foodOptions:      []string{"Burger", "Cake"},
 
 		// Inline food submenu
 		showFoodSubmenu:   false,
 		foodSubmenuCursor: 0,
 	}
+
+	m.RefreshInventory()
+
+	// The pet was brought up to date while nobody was there
+	m.events.Publish(context.Background(), p.TakeEvents()...)
+
+	return m
+}
+
+// RefreshInventory reloads the owner's coins and items, e.g. after a visit
+// to the shop
+func (m *PetUI) RefreshInventory() {
+	ctx := context.Background()
+	userID := m.pet.Parent.ID
+
+	coins, err := m.inventory.Coins(ctx, userID)
+	if err != nil {
+		log.Error("Error loading coins", "error", err)
+	}
+
+	owned, err := m.inventory.Items(ctx, userID)
+	if err != nil {
+		log.Error("Error loading inventory", "error", err)
+		owned = map[string]int{}
+	}
+
+	m.coins = coins
+	m.owned = owned
+	m.foodOptions = m.foodLabels()
+}
+
+// foodLabels returns the food menu entries along with how many of each food
+// the owner has
+func (m *PetUI) foodLabels() []string {
+	foods := pet.Foods().All()
+
+	labels := make([]string, len(foods))
+	for i, f := range foods {
+		labels[i] = fmt.Sprintf("%s x%d", f.Name, m.owned[f.ID])
+	}
+
+	return labels
+}
+
+// showNotice shows a short message below the menu
+func (m *PetUI) showNotice(notice string) {
+	m.showNoticeFor(notice, NoticeDisplayTime)
+}
+
+// showNoticeFor shows a message below the menu for as long as given
+func (m *PetUI) showNoticeFor(notice string, d time.Duration) {
+	m.notice = notice
+	m.noticeTime = time.Now()
+	m.noticeFor = d
+}
+
+// showToast queues a toast for an unlocked achievement
+func (m *PetUI) showToast(a achievements.Achievement) {
+	if len(m.toasts) == 0 {
+		m.toastTime = time.Now()
+	}
+	m.toasts = append(m.toasts, a)
+}
+
+// showBadges opens the achievements screen
+func (m *PetUI) showBadges() {
+	badges, err := m.achievements.Statuses(context.Background(), m.pet.Parent.ID)
+	if err != nil {
+		log.Error("Error loading achievements", "error", err)
+	}
+
+	m.badges = badges
+	m.inBadges = true
+}
+
+// Perform runs an action that came from outside the session, e.g. an SSH
+// command or the HTTP API, on the pet on screen and shows what happened
+func (m *PetUI) Perform(action string, arg string) (string, error) {
+	out, err := m.actions.Perform(actions.WithinSession(context.Background()), m.pet, action, arg)
+	m.RefreshInventory()
+
+	if err == nil {
+		m.showNotice(out)
+	}
+
+	return out, err
+}
+
+// perform runs an action through the service, which takes the items it
+// uses up from the owner's inventory, pays the daily care reward and saves
+// the pet, and shows what went wrong or the coins earned
+func (m *PetUI) perform(action string, arg string) error {
+	coins := m.coins
+
+	_, err := m.actions.Perform(actions.WithinSession(context.Background()), m.pet, action, arg)
+	m.RefreshInventory()
+
+	switch {
+	case err == nil:
+		if m.coins > coins {
+			m.showNotice(fmt.Sprintf("You earned %d coins for today's care!", m.coins-coins))
+		}
+	case errors.Is(err, actions.ErrRefused):
+	default:
+		if !errors.Is(err, actions.ErrOutOfStock) {
+			log.Error("Error performing action", "action", action, "error", err)
+		}
+		m.showNotice(err.Error())
+	}
+
+	return err
+}
+
+// feed feeds the food at the given menu index from the owner's inventory
+func (m *PetUI) feed(foodIndex int) {
+	foods := pet.Foods().All()
+	if foodIndex < 0 || foodIndex >= len(foods) {
+		return
+	}
+
+	err := m.perform(actions.Feed, foods[foodIndex].ID)
+
+	switch {
+	case err == nil:
+		m.showFeeding("eating", foodIndex)
+	case errors.Is(err, actions.ErrRefused):
+		m.showFeeding("sad", foodIndex)
+	}
+}
+
+// gameOutcome is the result of a finished game along with its rewards
+type gameOutcome struct {
+	name   string
+	result games.Result
+	coins  int
+	toy    string
+
+	// best is the owner's personal best in the game, including this result
+	best    int
+	newBest bool
+}
+
+// startGame starts the game at the given index of the game picker
+func (m *PetUI) startGame(index int) tea.Cmd {
+	all := games.All()
+	if index < 0 || index >= len(all) {
+		return nil
+	}
+
+	m.gameInfo = all[index]
+	m.game = m.gameInfo.New(m.pet, m.width)
+	m.inGamePicker = false
+
+	return m.game.Init()
+}
+
+// finishGame applies the result of the game that just ended through the
+// service, which saves the pet and pays out the coins, and records the score
+func (m *PetUI) finishGame() {
+	result := m.game.Result()
+	outcome := &gameOutcome{name: m.gameInfo.Name, result: result}
+
+	reward, err := m.actions.FinishGame(actions.WithinSession(context.Background()), m.pet, pet.GameResult{
+		Game:      m.gameInfo.ID,
+		Score:     result.Score,
+		MaxScore:  result.MaxScore,
+		Happiness: result.Happiness,
+		Weight:    result.Weight,
+	})
+	if err != nil {
+		log.Error("Error finishing game", "game", m.gameInfo.ID, "error", err)
+	}
+	m.RefreshInventory()
+
+	outcome.coins = reward.Coins
+	outcome.toy = strings.ToLower(reward.Toy)
+
+	m.recordScore(outcome)
+
+	m.game = nil
+	m.outcome = outcome
+	if reward.CareCoins > 0 {
+		m.showNotice(fmt.Sprintf("You earned %d coins for today's care!", reward.CareCoins))
+	}
+	m.resetToIdle()
+}
+
+// recordScore stores the score of a finished game and looks up the owner's
+// personal best
+func (m *PetUI) recordScore(outcome *gameOutcome) {
+	ctx := context.Background()
+	userID := m.pet.Parent.ID
+
+	best, bestErr := m.scores.Best(ctx, userID, m.gameInfo.ID)
+	if bestErr != nil {
+		log.Error("Error loading best score", "game", m.gameInfo.ID, "error", bestErr)
+	}
+
+	score := &models.GameScore{
+		UserID:   userID,
+		PetID:    m.pet.ID,
+		Game:     m.gameInfo.ID,
+		Score:    outcome.result.Score,
+		UserName: m.pet.Parent.Name,
+		PetName:  m.pet.Name,
+	}
+	if err := m.scores.Add(ctx, score); err != nil {
+		log.Error("Error saving score", "game", m.gameInfo.ID, "error", err)
+		return
+	}
+
+	outcome.best = max(best, score.Score)
+	outcome.newBest = bestErr == nil && score.Score > best
 }
 
 // Init initializes the model
@@ -204,25 +494,28 @@ func (m *PetUI) startGlobalTicker() tea.Cmd {
 // resetToIdle sets the animation state back to idle based on current pet state
 func (m *PetUI) resetToIdle() {
 	m.animState = "idle"
-	m.currentAnim = ascii.GetAnimationForState(m.pet.GetState())
+	m.currentAnim = m.pet.Animations().ForState(m.pet.GetState())
 	m.currentFrame = 0
 	m.frameCounter = 0
 }
 
 // updateAnimation handles all animation state transitions and frame updates
 func (m *PetUI) updateAnimation() {
+	// Record when and how the pet died
+	m.pet.MarkDead(time.Now())
+
 	// Check if the pet just died
 	if m.pet.IsDead() && !m.inGameOver {
 		m.inGameOver = true
 		m.gameOverCursor = 0
-		m.currentAnim = ascii.GetAnimationForState(ascii.StateDead)
+		m.currentAnim = m.pet.Animations().Dead
 		m.animState = "dead"
 		m.currentFrame = 0
 		return
 	}
 
 	// Game-specific animations take priority
-	if m.inGame {
+	if m.game != nil {
 		// Don't change animation during game unless explicitly requested
 		return
 	}
@@ -230,7 +523,7 @@ func (m *PetUI) updateAnimation() {
 	// Process animation state transitions based on current state
 	switch m.animState {
 	case "idle":
-		newAnim := ascii.GetAnimationForState(m.pet.GetState())
+		newAnim := m.pet.Animations().ForState(m.pet.GetState())
 
 		if newAnim.Name != m.currentAnim.Name {
 			m.currentAnim = newAnim
@@ -238,7 +531,7 @@ func (m *PetUI) updateAnimation() {
 			m.frameCounter = 0
 		}
 
-	case "eating", "cakeEating":
+	case "eating":
 		if m.frameCounter >= len(m.currentAnim.Frames) {
 			m.resetToIdle()
 		}
@@ -252,7 +545,7 @@ func (m *PetUI) updateAnimation() {
 		// Playing animation is handled by game logic
 
 	case "dead":
-		m.currentAnim = ascii.GetAnimationForState(ascii.StateDead)
+		m.currentAnim = m.pet.Animations().Dead
 	}
 
 	if len(m.currentAnim.Frames) > 0 {
@@ -301,47 +594,75 @@ func (m *PetUI) handlePetMovement() {
 	}
 }
 
+// Update handles a message and publishes what happened to the pet
 func (m *PetUI) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	model, cmd := m.update(msg)
+
+	ctx := events.WithActor(context.Background(), m.pet.Parent.Name)
+	m.events.Publish(ctx, m.pet.TakeEvents()...)
+
+	return model, cmd
+}
+
+func (m *PetUI) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 
 	switch msg := msg.(type) {
 	case FrameMsg:
 		// Global ticker handles all animations and state transitions
 
-		// Handle result display timeout
-		if m.inGame && m.showResult {
-			// Get time since last frame
-			now := time.Now()
-			elapsed := now.Sub(m.lastUpdateTime).Milliseconds()
-
-			// If we've displayed the result long enough, advance the game
-			if elapsed >= ResultDisplayTime {
-				m.showResult = false
-				m.animState = "playing"
-				m.currentAnim = ascii.Playing
-				m.currentFrame = 0
-				m.frameCounter = 0
-				m.lastUpdateTime = now
-			}
-		} else {
-			now := time.Now()
-			m.lastUpdateTime = now
+		now := time.Now()
+		m.lastUpdateTime = now
 
-			m.updateAnimation()
+		m.updateAnimation()
 
-			m.handlePetMovement()
+		m.handlePetMovement()
 
-			if now.Sub(m.lastStatUpdateTime) >= time.Second && !m.debugMode {
-				m.updatePetState()
-				m.lastStatUpdateTime = now
-			}
+		if now.Sub(m.lastStatUpdateTime) >= time.Second && !m.debugMode {
+			m.updatePetState()
+			m.lastStatUpdateTime = now
+		}
 
-			if m.selectedAction >= 0 && time.Since(m.selectedTime) > 1*time.Second {
-				m.selectedAction = -1
-			}
+		if m.selectedAction >= 0 && time.Since(m.selectedTime) > 1*time.Second {
+			m.selectedAction = -1
+		}
+
+		if m.notice != "" && time.Since(m.noticeTime) > m.noticeFor {
+			m.notice = ""
+		}
+
+		if len(m.toasts) > 0 && time.Since(m.toastTime) > ToastDisplayTime {
+			m.toasts = m.toasts[1:]
+			m.toastTime = now
+		}
+
+		// The running game animates the pet on the same ticker
+		if m.game != nil {
+			_, cmd = m.game.Update(games.TickMsg(msg))
 		}
 
-		return m, m.startGlobalTicker()
+		return m, tea.Batch(cmd, m.startGlobalTicker())
+
+	case AchievementUnlockedMsg:
+		m.showToast(msg.Achievement)
+		return m, nil
+
+	case GiftsReceivedMsg:
+		m.RefreshInventory()
+		m.showNoticeFor(giftNotice(msg.Gifts), GiftNoticeDisplayTime)
+		return m, nil
+
+	case PlaydateFinishedMsg:
+		m.sim.PlayWith(m.pet, msg.With, msg.Happiness)
+		m.showReaction("happy")
+		return m, nil
+
+	case games.FinishedMsg:
+		// Ignore games that were abandoned before they finished
+		if m.game != nil && msg.Game == m.game {
+			m.finishGame()
+		}
+		return m, nil
 
 	case tea.KeyMsg:
 		// Check for restart request
@@ -406,117 +727,41 @@ func (m *PetUI) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.inFoodSelectMode = false
 
 				if selected {
-					animState, updatedPet := handlers.FeedPet(m.foodCursor, m.pet)
-					m.pet = updatedPet
-					m.animState = animState
-
-					// Set correct animation based on state
-					if animState == "eating" {
-						m.currentAnim = ascii.Eating
-					} else if animState == "cakeEating" {
-						m.currentAnim = ascii.CakeEating
-					}
-					m.currentFrame = 0
-					m.frameCounter = 0
+					m.feed(m.foodCursor)
 				}
 			}
 
 			return m, nil
 		}
 
-		// If we're in a game, handle game controls
-		if m.inGame {
-			switch msg.String() {
-			case "h", "left":
-				// Player guesses "lower"
-				wasInGame := m.inGame
-				m.inGame, m.gameNumber, m.gameGuessesLeft, m.gameScore, m.showResult, m.lastGuessWasCorrect, m.lastNumber, m.animState, m.pet = handlers.HandleGameGuess(
-					false,
-					m.inGame,
-					m.gameNumber,
-					m.gameGuessesLeft,
-					m.gameScore,
-					m.showResult,
-					m.lastGuessWasCorrect,
-					m.lastNumber,
-					m.pet,
-				)
-
-				// Update animation based on state
-				if m.animState == "happy" {
-					m.currentAnim = ascii.Happy
-				} else if m.animState == "sad" {
-					m.currentAnim = ascii.Sad
-				} else if m.animState == "playing" {
-					m.currentAnim = ascii.Playing
-				} else if m.animState == "idle" {
-					m.currentAnim = ascii.GetAnimationForState(m.pet.GetState())
-				}
-
-				// Reset frames
-				m.currentFrame = 0
-				m.frameCounter = 0
-				m.lastUpdateTime = time.Now()
-
-				// If game just ended, setup idle state
-				if !m.inGame && wasInGame {
-					m.resetToIdle()
-					m.showResult = false
-				}
-
+		// The running game gets every key, ESC abandons it
+		if m.game != nil {
+			if msg.String() == "esc" {
+				m.game = nil
+				m.resetToIdle()
 				return m, nil
+			}
 
-			case "l", "right":
-				// Player guesses "higher"
-				wasInGame := m.inGame
-				m.inGame, m.gameNumber, m.gameGuessesLeft, m.gameScore, m.showResult, m.lastGuessWasCorrect, m.lastNumber, m.animState, m.pet = handlers.HandleGameGuess(
-					true,
-					m.inGame,
-					m.gameNumber,
-					m.gameGuessesLeft,
-					m.gameScore,
-					m.showResult,
-					m.lastGuessWasCorrect,
-					m.lastNumber,
-					m.pet,
-				)
-
-				// Update animation based on state
-				if m.animState == "happy" {
-					m.currentAnim = ascii.Happy
-				} else if m.animState == "sad" {
-					m.currentAnim = ascii.Sad
-				} else if m.animState == "playing" {
-					m.currentAnim = ascii.Playing
-				} else if m.animState == "idle" {
-					m.currentAnim = ascii.GetAnimationForState(m.pet.GetState())
-				}
-
-				// Reset frames
-				m.currentFrame = 0
-				m.frameCounter = 0
-				m.lastUpdateTime = time.Now()
-
-				// If game just ended, setup idle state
-				if !m.inGame && wasInGame {
-					m.resetToIdle()
-					m.showResult = false
-				}
-
-				return m, nil
+			_, cmd = m.game.Update(msg)
+			return m, cmd
+		}
 
-			case "esc":
-				// Exit game
-				m.inGame = false
-				m.resetToIdle()
-				m.showResult = false
+		// Any key closes the result of the last game
+		if m.outcome != nil {
+			m.outcome = nil
+			return m, nil
+		}
 
-				return m, nil
+		if m.inGamePicker {
+			return m.updateGamePicker(msg)
+		}
 
-			default:
-				// Even for unhandled keys, maintain the animation timer
-				return m, m.startGlobalTicker()
+		// ESC or Enter closes the achievements screen
+		if m.inBadges {
+			if msg.String() == "esc" || key.Matches(msg, m.keys.Action) {
+				m.inBadges = false
 			}
+			return m, nil
 		}
 
 		// Normal UI controls when not in game
@@ -562,8 +807,7 @@ func (m *PetUI) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case m.inRenameMode:
 			switch msg.String() {
 			case "enter", "return":
-				if len(m.newName) > 0 {
-					m.pet.Name = m.newName
+				if m.perform(actions.Rename, m.newName) == nil {
 					m.inRenameMode = false
 					m.newName = ""
 				}
@@ -572,7 +816,7 @@ func (m *PetUI) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.newName = m.newName[:len(m.newName)-1]
 				}
 			default:
-				if len(msg.String()) == 1 && len(m.newName) < 20 {
+				if len(msg.String()) == 1 && len(m.newName) < actions.MaxNameLength {
 					r := []rune(msg.String())[0]
 					if unicode.IsPrint(r) {
 						m.newName += msg.String()
@@ -596,18 +840,7 @@ func (m *PetUI) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 				if msg.String() == "enter" || msg.String() == " " {
 					m.showFoodSubmenu = false
-					animState, updatedPet := handlers.FeedPet(m.foodSubmenuCursor, m.pet)
-					m.pet = updatedPet
-					m.animState = animState
-
-					// Set correct animation based on state
-					if animState == "eating" {
-						m.currentAnim = ascii.Eating
-					} else if animState == "cakeEating" {
-						m.currentAnim = ascii.CakeEating
-					}
-					m.currentFrame = 0
-					m.frameCounter = 0
+					m.feed(m.foodSubmenuCursor)
 				}
 
 				return m, nil
@@ -615,27 +848,66 @@ func (m *PetUI) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.selectedAction = m.cursor
 				m.selectedTime = time.Now()
 
-				// If pet is sleeping (lights off), only allow toggling lights or quitting
-				// 5 is Toggle Lights, 6 is Quit
-				if !m.pet.LightsOn && m.cursor != 5 && m.cursor != 6 {
+				// Only allow what the pet's state permits, e.g. just toggling
+				// the lights while it sleeps
+				if !m.menuEnabled(m.cursor) {
 					return m, nil
 				}
 
 				// Regular action handling if not in food submenu
-				if m.cursor == 0 { // Feed
+				switch m.cursor {
+				case menuFeed:
 					m.showFoodSubmenu = true
 					m.foodSubmenuCursor = 0
-				} else if m.cursor == 1 { // Clean
-					m.pet.Clean()
-				} else if m.cursor == 2 { // Play
-					return m.startGame()
-				} else if m.cursor == 3 { // Medicine
-					m.pet.GiveMedicine()
-				} else if m.cursor == 4 { // Rename
+				case menuClean:
+					m.perform(actions.Clean, "")
+				case menuPlay:
+					err := m.actions.StartGame(actions.WithinSession(context.Background()), m.pet)
+					if errors.Is(err, actions.ErrRefused) {
+						m.showReaction("sad")
+						return m, nil
+					}
+					if err != nil {
+						log.Error("Error starting game", "error", err)
+						m.showNotice(err.Error())
+						return m, nil
+					}
+					m.inGamePicker = true
+				case menuMedicine:
+					m.perform(actions.Medicine, "")
+				case menuScold:
+					// Only a deserved scolding makes the pet sad
+					deserved := m.pet.IsMisbehaving()
+					if m.perform(actions.Scold, "") == nil && deserved {
+						m.showReaction("sad")
+					}
+				case menuPraise:
+					if m.perform(actions.Praise, "") == nil {
+						m.showReaction("happy")
+					}
+				case menuRename:
 					m.inRenameMode = true
-				} else if m.cursor == 5 { // Toggle Lights
-					m.pet.ToggleLights()
-				} else if m.cursor == 6 { // Quit
+				case menuShop:
+					return m, func() tea.Msg { return ShowShopMsg{} }
+				case menuScores:
+					return m, func() tea.Msg { return ShowLeaderboardMsg{} }
+				case menuBadges:
+					m.showBadges()
+				case menuDiary:
+					return m, func() tea.Msg { return ShowDiaryMsg{} }
+				case menuStats:
+					return m, func() tea.Msg { return ShowStatHistoryMsg{} }
+				case menuFamily:
+					return m, func() tea.Msg { return ShowFamilyMsg{} }
+				case menuVisit:
+					return m, func() tea.Msg { return ShowVisitMsg{} }
+				case menuFriends:
+					return m, func() tea.Msg { return ShowFriendsMsg{} }
+				case menuPlaydate:
+					return m, func() tea.Msg { return ShowPlaydateMsg{} }
+				case menuLights:
+					m.perform(actions.Lights, "")
+				case menuQuit:
 					return m, func() tea.Msg { return QuitMsg{} }
 				}
 			}
@@ -647,20 +919,8 @@ func (m *PetUI) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 						m.foodSubmenuCursor--
 					}
 				}
-			} else if m.cursor > 0 {
-				// If pet is sleeping, only allow moving to Toggle Lights or Quit
-				if !m.pet.LightsOn {
-					// Only allow movement to Toggle Lights (5) or Quit (6)
-					if m.cursor > 6 {
-						m.cursor--
-					} else if m.cursor == 6 {
-						m.cursor = 5
-					} else if m.cursor < 5 {
-						m.cursor = 5
-					}
-				} else {
-					m.cursor--
-				}
+			} else {
+				m.moveCursor(-1)
 			}
 		case key.Matches(msg, m.keys.Down), key.Matches(msg, m.keys.Right):
 			if m.showFoodSubmenu {
@@ -671,20 +931,8 @@ func (m *PetUI) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					}
 				}
 				// Down key doesn't do anything in food submenu
-			} else if m.cursor < len(choices)-1 {
-				// If pet is sleeping, only allow moving to Toggle Lights or Quit
-				if !m.pet.LightsOn {
-					// Only allow movement to Toggle Lights (5) or Quit (6)
-					if m.cursor < 5 {
-						m.cursor = 5
-					} else if m.cursor == 5 {
-						m.cursor = 6
-					} else if m.cursor > 6 {
-						m.cursor = 6
-					}
-				} else {
-					m.cursor++
-				}
+			} else {
+				m.moveCursor(1)
 			}
 		}
 
@@ -692,168 +940,104 @@ func (m *PetUI) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.width = msg.Width
 		m.height = msg.Height
 		m.help.Width = msg.Width
-	}
-
-	return m, cmd
-}
-
-// updatePetState updates the pet state over time
-func (m *PetUI) updatePetState() {
-	// If pet is dead, don't update stats
-	if m.pet.IsDead() {
-		return
-	}
-
-	// Special sleep benefits when lights are off
-	if !m.pet.LightsOn {
-		// When sleeping (lights off), pet recovers health and happiness
-		if m.pet.Health < 100 && rand.Intn(3) == 0 {
-			m.pet.Health += 1
-		}
-
-		if m.pet.Happiness < 100 && rand.Intn(5) == 0 {
-			m.pet.Happiness += 1
-		}
 
-		// Hunger increases more slowly when sleeping
-		if m.pet.Hunger < 100 && rand.Intn(10) == 0 {
-			m.pet.Hunger += 1
+		if m.game != nil {
+			_, cmd = m.game.Update(msg)
 		}
 
-		// Cap values
-		if m.pet.Health > 100 {
-			m.pet.Health = 100
-		}
-		if m.pet.Happiness > 100 {
-			m.pet.Happiness = 100
+	default:
+		// Games run their own timers
+		if m.game != nil {
+			_, cmd = m.game.Update(msg)
 		}
-		if m.pet.Hunger > 100 {
-			m.pet.Hunger = 100
-		}
-
-		// Early return - when sleeping no other effects apply
-		return
 	}
 
-	// Normal state updates (when lights are on)
-	// Increase hunger over time, but only if pet is not full
-	// Make hunger increase more consistently for testing
-	if m.pet.Hunger < 100 && rand.Intn(5) == 0 {
-		m.pet.Hunger += 1
-	}
+	return m, cmd
+}
 
-	// Cap hunger at 100
-	if m.pet.Hunger > 100 {
-		m.pet.Hunger = 100
-	}
+// updateGamePicker handles keys while the player picks a game
+func (m *PetUI) updateGamePicker(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch {
+	case msg.String() == "esc":
+		m.inGamePicker = false
 
-	// Pet loses health when very hungry - always check this regardless of other stats
-	if m.pet.Hunger > 90 {
-		m.pet.Health -= 1
-	}
+	case key.Matches(msg, m.keys.Up), key.Matches(msg, m.keys.Left):
+		if m.gameCursor > 0 {
+			m.gameCursor--
+		}
 
-	// Ensure hunger never goes below 0
-	if m.pet.Hunger < 0 {
-		m.pet.Hunger = 0
-	}
+	case key.Matches(msg, m.keys.Down), key.Matches(msg, m.keys.Right):
+		if m.gameCursor < len(games.All())-1 {
+			m.gameCursor++
+		}
 
-	// Decrease happiness over time (increased rate for testing)
-	if rand.Intn(2) == 0 {
-		m.pet.Happiness -= 1
+	case key.Matches(msg, m.keys.Action):
+		return m, m.startGame(m.gameCursor)
 	}
 
-	// Cap happiness between 0 and 100
-	if m.pet.Happiness > 100 {
-		m.pet.Happiness = 100
-	}
-	if m.pet.Happiness < 0 {
-		m.pet.Happiness = 0
-	}
+	return m, nil
+}
 
-	// Health slowly recovers if not too hungry
-	if m.pet.Hunger < 80 && m.pet.Health < 100 && !m.pet.IsSick {
-		m.pet.Health += 1
+// menuEnabled reports whether the menu choice can be picked in the pet's
+// current state
+func (m *PetUI) menuEnabled(choice int) bool {
+	action, ok := menuActions[choice]
+	if !ok {
+		return true
 	}
 
-	// Health gradually decreases over time - slowed down to once every 3 minutes on average
-	if rand.Intn(180) == 0 {
-		m.pet.Health -= 1
-	}
+	return actions.Check(m.pet, action) == nil
+}
 
-	// Weight affects health - heavier pets lose health faster
-	if m.pet.Weight > 100 {
-		if rand.Intn(60) == 0 {
-			m.pet.Health -= 1
-		}
-	} else if m.pet.Weight > 75 {
-		if rand.Intn(120) == 0 {
-			m.pet.Health -= 1
+// moveCursor moves the menu cursor in the given direction, skipping choices
+// that can't be picked right now, e.g. everything but the lights while the
+// pet sleeps
+func (m *PetUI) moveCursor(direction int) {
+	for i := m.cursor + direction; i >= 0 && i < len(choices); i += direction {
+		if m.menuEnabled(i) {
+			m.cursor = i
+			return
 		}
 	}
 
-	// Cap health between 0 and 100
-	if m.pet.Health > 100 {
-		m.pet.Health = 100
-	}
-	if m.pet.Health < 0 {
-		m.pet.Health = 0
-		// If health reaches 0, set game over state
-		m.inGameOver = true
-		m.gameOverCursor = 0
-	}
-
-	// Random chance for sickness (if not already sick)
-	if !m.pet.IsSick && !m.pet.IsDead() {
-		if rand.Intn(3600) == 0 {
-			m.pet.IsSick = true
-		}
+	// Stay within the enabled choices if the cursor is on a disabled one
+	if !m.menuEnabled(m.cursor) {
+		m.cursor = menuQuit
 	}
+}
 
-	// Natural weight loss over time when hungry
-	if m.pet.Hunger > 50 && rand.Intn(300) == 0 {
-		m.pet.Weight -= 1
-		if m.pet.Weight < 10 {
-			m.pet.Weight = 10
-		}
+// showReaction briefly plays the happy or sad animation
+func (m *PetUI) showReaction(animState string) {
+	m.animState = animState
+	if animState == "happy" {
+		m.currentAnim = m.pet.Animations().Happy
+	} else {
+		m.currentAnim = m.pet.Animations().Sad
 	}
+	m.currentFrame = 0
+	m.frameCounter = 0
+}
 
-	// Sickness decreases health
-	if m.pet.IsSick {
-		// Health decreases faster when sick
-		if rand.Intn(20) == 0 {
-			m.pet.Health -= 1
-		}
+// showFeeding plays the animation for the result of feeding the food at the
+// given menu index
+func (m *PetUI) showFeeding(animState string, foodIndex int) {
+	m.animState = animState
+
+	switch animState {
+	case "eating":
+		m.currentAnim = m.pet.Animations().EatingFood(pet.Foods().All()[foodIndex].Emoji)
+	case "sad":
+		// The pet refused to eat
+		m.currentAnim = m.pet.Animations().Sad
 	}
 
-	// Random chance to poop (if not already pooped)
-	if !m.pet.HasPooped && !m.pet.IsDead() {
-		// Base chance to poop
-		poopChance := 1800 // Default ~once per 30 minutes
-
-		// Babies poop much more frequently
-		if m.pet.LifeStage() == pet.StageBaby {
-			poopChance = 300 // Babies poop ~6x more often (every 5 minutes)
-		} else if m.pet.LifeStage() == pet.StageChild {
-			poopChance = 900 // Children poop ~2x more often (every 15 minutes)
-		}
-
-		// Recently fed pets poop more
-		if m.pet.Hunger < 30 {
-			poopChance = poopChance / 2 // Twice as likely to poop when recently fed
-		}
-
-		if rand.Intn(poopChance) == 0 {
-			m.pet.HasPooped = true
-		}
-	}
+	m.currentFrame = 0
+	m.frameCounter = 0
+}
 
-	// Uncleaned poop gradually decreases health and happiness
-	if m.pet.HasPooped {
-		if rand.Intn(30) == 0 {
-			m.pet.Health -= 1
-			m.pet.Happiness -= 1
-		}
-	}
+// updatePetState advances the pet simulation up to the current time
+func (m *PetUI) updatePetState() {
+	m.sim.Update(m.pet)
 }
 
 // View renders the UI
@@ -876,21 +1060,41 @@ func (m *PetUI) View() string {
 			m.pet,
 			m.gameOverCursor,
 		)
-	} else if m.inGame {
-		// If we're in game, render the game UI
-		output = views.RenderGameView(
-			"",
+	} else if m.game != nil {
+		output = m.game.View()
+	} else if m.outcome != nil {
+		animation := m.pet.Animations().Happy
+		if m.outcome.result.Score*2 < m.outcome.result.MaxScore {
+			animation = m.pet.Animations().Sad
+		}
+
+		output = views.RenderGameResult(
 			m.width,
-			m.currentFrame,
-			m.animState,
-			m.petPosition,
-			m.showResult,
-			m.lastGuessWasCorrect,
-			m.gameNumber,
-			m.lastNumber,
-			m.gameGuessesLeft,
-			m.gameScore,
-			m.inGame,
+			m.outcome.name,
+			animation.Frames[m.currentFrame%len(animation.Frames)],
+			m.outcome.result.Score,
+			m.outcome.result.MaxScore,
+			m.outcome.result.Happiness,
+			m.outcome.result.Weight,
+			m.outcome.coins,
+			m.outcome.toy,
+			m.outcome.best,
+			m.outcome.newBest,
+		)
+	} else if m.inBadges {
+		output = views.RenderAchievements(m.width, m.badges)
+	} else if m.inGamePicker {
+		var names, descriptions []string
+		for _, g := range games.All() {
+			names = append(names, g.Name)
+			descriptions = append(descriptions, g.Description)
+		}
+
+		output = views.RenderGamePicker(
+			m.width,
+			names,
+			descriptions,
+			m.gameCursor,
 		)
 	} else if m.inRenameMode {
 		// If we're in rename mode, render the rename UI
@@ -906,7 +1110,7 @@ func (m *PetUI) View() string {
 			m.width,
 			m.pet,
 			m.foodCursor,
-			m.foodOptions,
+			pet.Foods().All(),
 		)
 	} else {
 		// Render the main view with fixed parameters to match the function signature
@@ -926,9 +1130,16 @@ func (m *PetUI) View() string {
 			m.showFoodSubmenu,
 			m.foodSubmenuCursor,
 			m.foodOptions,
+			m.coins,
+			m.notice,
 		)
 	}
 
+	// Unlocked achievements pop up above every screen
+	if len(m.toasts) > 0 {
+		output = views.RenderToast(m.width, m.toasts[0]) + "\n" + output
+	}
+
 	// Add debug information at the bottom if in debug mode
 	if m.debugMode {
 		// Fix any trailing underscore in animState
@@ -936,7 +1147,7 @@ func (m *PetUI) View() string {
 		displayState = strings.TrimSuffix(displayState, "_")
 
 		// Get the expected animation based on current pet state
-		expectedAnim := ascii.GetAnimationForState(m.pet.GetState())
+		expectedAnim := m.pet.Animations().ForState(m.pet.GetState())
 
 		// Simplified animation display using the Name field
 		expectedAnimName := expectedAnim.Name
@@ -957,7 +1168,7 @@ func (m *PetUI) View() string {
 			expectedAnimName,
 			m.currentAnim.FPS,
 			m.petPosition,
-			m.inGame,
+			m.game != nil,
 		)
 
 		// Add more pet state information
@@ -986,28 +1197,13 @@ func (m *PetUI) View() string {
 	return output
 }
 
-// Initializes a new game
-func (m *PetUI) startGame() (tea.Model, tea.Cmd) {
-	m.inGame, m.gameGuessesLeft, m.gameScore, m.gameNumber, m.showResult, m.lastNumber, m.lastGuessWasCorrect, m.animState = handlers.StartGame()
-
-	m.currentAnim = ascii.Playing
-
-	now := time.Now()
-	m.currentFrame = 0
-	m.frameCounter = 0
-	m.animCompleted = false
-	m.lastUpdateTime = now
-
-	return m, nil
-}
-
 // Creates a new pet and resets the game state
 func (m *PetUI) restartGame() (tea.Model, tea.Cmd) {
-	m.pet = handlers.RestartGame(m.pet.Name, m.pet.Parent)
+	m.pet = handlers.RestartGame(m.pets, m.sim, m.pet.Name, m.pet.SpeciesID, m.pet.Parent)
 
 	// Reset UI state
 	now := time.Now()
-	m.currentAnim = ascii.GetAnimationForState(m.pet.GetState())
+	m.currentAnim = m.pet.Animations().ForState(m.pet.GetState())
 	m.currentFrame = 0
 	m.lastUpdateTime = now
 	m.lastStatUpdateTime = now
@@ -1021,13 +1217,9 @@ func (m *PetUI) restartGame() (tea.Model, tea.Cmd) {
 	m.resetToIdle()
 
 	// Reset game states
-	m.inGame = false
-	m.gameNumber = 0
-	m.gameGuessesLeft = 0
-	m.gameScore = 0
-	m.showResult = false
-	m.lastGuessWasCorrect = false
-	m.lastNumber = 0
+	m.inGamePicker = false
+	m.game = nil
+	m.outcome = nil
 
 	// Reset debug state
 	m.debugMode = false
@@ -0,0 +1,341 @@
+package ui
+
+import (
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kirkegaard/terminal-pet/pkg/pet"
+	"github.com/kirkegaard/terminal-pet/pkg/playdate"
+	"github.com/kirkegaard/terminal-pet/pkg/ui/keymap"
+	"github.com/kirkegaard/terminal-pet/pkg/ui/views"
+)
+
+// ShowPlaydateMsg is sent when the player wants to invite someone to a
+// playdate
+type ShowPlaydateMsg struct{}
+
+// ClosePlaydateMsg is sent when the player leaves the playdate screens
+type ClosePlaydateMsg struct{}
+
+// InvitePlaymateMsg is sent when the player picks who to invite, Index is
+// their position in the list of online players
+type InvitePlaymateMsg struct {
+	Index int
+}
+
+// AnswerInviteMsg is sent when the player answers an invitation
+type AnswerInviteMsg struct {
+	Accepted bool
+}
+
+// PlaydateInviteMsg is sent from one session to another to invite its player
+// to a playdate with the pet
+type PlaydateInviteMsg struct {
+	From    string
+	FromKey string
+	Pet     *pet.Pet
+}
+
+// PlaydateAnswerMsg is sent back to the session that sent an invitation.
+// Reason explains why the invitation wasn't accepted.
+type PlaydateAnswerMsg struct {
+	From     string
+	FromKey  string
+	Pet      *pet.Pet
+	Accepted bool
+	Reason   string
+}
+
+// PlaydateStartedMsg is sent to both sessions once an invitation was
+// accepted, Side is the side of the receiving player
+type PlaydateStartedMsg struct {
+	Playdate *playdate.Playdate
+	Side     int
+}
+
+// PlaydateStateMsg is sent to both sessions whenever the playdate changes
+type PlaydateStateMsg struct {
+	State playdate.State
+}
+
+// PlaydateFinishedMsg is sent to the pet screen when a playdate game is
+// over, so the pet gets happier
+type PlaydateFinishedMsg struct {
+	With      string
+	Happiness int
+}
+
+// PlaydateBabyMsg is sent when both players agreed to their pets having a
+// baby, Mate is the pet of the other player. Keep tells whether the player
+// asked first and gets the baby.
+type PlaydateBabyMsg struct {
+	Mate *pet.Pet
+	Keep bool
+}
+
+// PlaydatePicker lists the online players to invite to a playdate and waits
+// for their answer
+type PlaydatePicker struct {
+	names   []string
+	cursor  int
+	waiting string
+	message string
+	keys    keymap.KeyMap
+	width   int
+	height  int
+}
+
+// NewPlaydatePicker creates a screen to invite one of the named players
+func NewPlaydatePicker(names []string, width, height int) *PlaydatePicker {
+	return &PlaydatePicker{
+		names:  names,
+		keys:   keymap.Keys,
+		width:  width,
+		height: height,
+	}
+}
+
+// SetWaiting shows that the named player was invited
+func (m *PlaydatePicker) SetWaiting(name string) {
+	m.waiting = name
+	m.message = ""
+}
+
+// ShowMessage goes back to the list, explaining what happened to the last
+// invitation
+func (m *PlaydatePicker) ShowMessage(message string) {
+	m.waiting = ""
+	m.message = message
+}
+
+func (m *PlaydatePicker) Init() tea.Cmd {
+	return nil
+}
+
+func (m *PlaydatePicker) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case msg.String() == "esc", key.Matches(msg, m.keys.Quit):
+			return m, func() tea.Msg { return ClosePlaydateMsg{} }
+
+		case m.waiting != "":
+			// Nothing to do but wait for the answer
+
+		case key.Matches(msg, m.keys.Up):
+			if m.cursor > 0 {
+				m.cursor--
+			}
+
+		case key.Matches(msg, m.keys.Down):
+			if m.cursor < len(m.names)-1 {
+				m.cursor++
+			}
+
+		case key.Matches(msg, m.keys.Action):
+			if len(m.names) > 0 {
+				index := m.cursor
+				return m, func() tea.Msg { return InvitePlaymateMsg{Index: index} }
+			}
+		}
+
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+	}
+
+	return m, nil
+}
+
+func (m *PlaydatePicker) View() string {
+	return views.RenderPlaydatePicker(m.width, m.names, m.cursor, m.waiting, m.message)
+}
+
+// PlaydateInvite asks the player whether to accept an invitation
+type PlaydateInvite struct {
+	from    string
+	petName string
+	keys    keymap.KeyMap
+	width   int
+	height  int
+}
+
+// NewPlaydateInvite creates a screen asking whether to play with the named
+// player's pet
+func NewPlaydateInvite(from string, petName string, width, height int) *PlaydateInvite {
+	return &PlaydateInvite{
+		from:    from,
+		petName: petName,
+		keys:    keymap.Keys,
+		width:   width,
+		height:  height,
+	}
+}
+
+func (m *PlaydateInvite) Init() tea.Cmd {
+	return nil
+}
+
+func (m *PlaydateInvite) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case msg.String() == "y", key.Matches(msg, m.keys.Action):
+			return m, func() tea.Msg { return AnswerInviteMsg{Accepted: true} }
+
+		case msg.String() == "n", msg.String() == "esc", key.Matches(msg, m.keys.Quit):
+			return m, func() tea.Msg { return AnswerInviteMsg{Accepted: false} }
+		}
+
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+	}
+
+	return m, nil
+}
+
+func (m *PlaydateInvite) View() string {
+	return views.RenderPlaydateInvite(m.width, m.from, m.petName)
+}
+
+// playdateFrameMsg advances the animations of a playdate
+type playdateFrameMsg time.Time
+
+// Playdate shows both pets of a playdate side by side and lets the players
+// play games together
+type Playdate struct {
+	playdate *playdate.Playdate
+	side     int
+	state    playdate.State
+	cursor   int
+	frame    int
+	rewarded int
+	bred     bool
+	message  string
+	keys     keymap.KeyMap
+	width    int
+	height   int
+}
+
+// NewPlaydate creates the screen of one side of a playdate
+func NewPlaydate(pd *playdate.Playdate, side int, width, height int) *Playdate {
+	return &Playdate{
+		playdate: pd,
+		side:     side,
+		state:    pd.State(),
+		keys:     keymap.Keys,
+		width:    width,
+		height:   height,
+	}
+}
+
+// ShowMessage shows a message below the pets
+func (m *Playdate) ShowMessage(message string) {
+	m.message = message
+}
+
+// choices returns how many things there are to choose from: the games and
+// having a baby
+func (m *Playdate) choices() int {
+	return len(playdate.Games()) + 1
+}
+
+func (m *Playdate) Init() tea.Cmd {
+	return playdateFrame()
+}
+
+// playdateFrame waits for the next animation frame
+func playdateFrame() tea.Cmd {
+	return tea.Tick(AnimationTickRate, func(t time.Time) tea.Msg {
+		return playdateFrameMsg(t)
+	})
+}
+
+func (m *Playdate) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case playdateFrameMsg:
+		m.frame++
+		m.playdate.Tick(time.Time(msg))
+		return m, playdateFrame()
+
+	case PlaydateStateMsg:
+		// Changes may arrive out of order
+		if msg.State.Version < m.state.Version {
+			return m, nil
+		}
+		m.state = msg.State
+
+		// Both pets get happier whenever a game is over
+		var cmds []tea.Cmd
+		s := m.state
+		if s.Round > m.rewarded && !s.Playing && s.Left < 0 {
+			m.rewarded = s.Round
+			finished := PlaydateFinishedMsg{With: s.Pets[1-m.side].Name, Happiness: s.Reward(m.side)}
+			cmds = append(cmds, func() tea.Msg { return finished })
+		}
+
+		// The pets have a single baby, both players' pets become parents
+		if s.Bred && !m.bred {
+			m.bred = true
+			baby := PlaydateBabyMsg{Mate: s.Pets[1-m.side], Keep: s.Owner == m.side}
+			cmds = append(cmds, func() tea.Msg { return baby })
+		}
+
+		return m, tea.Batch(cmds...)
+
+	case tea.KeyMsg:
+		switch {
+		case m.state.Left >= 0:
+			return m, func() tea.Msg { return ClosePlaydateMsg{} }
+
+		case msg.String() == "esc", key.Matches(msg, m.keys.Quit):
+			m.playdate.Leave(m.side)
+			return m, func() tea.Msg { return ClosePlaydateMsg{} }
+
+		case m.state.Playing:
+			if key.Matches(msg, m.keys.Action) {
+				m.playdate.Press(m.side, time.Now())
+			}
+
+		case key.Matches(msg, m.keys.Left), key.Matches(msg, m.keys.Up):
+			m.cursor = (m.cursor + m.choices() - 1) % m.choices()
+
+		case key.Matches(msg, m.keys.Right), key.Matches(msg, m.keys.Down):
+			m.cursor = (m.cursor + 1) % m.choices()
+
+		// Only ENTER starts a game, so mashing SPACE at the end of one
+		// doesn't start the next
+		case msg.String() == "enter":
+			if m.cursor < len(playdate.Games()) {
+				m.message = ""
+				m.playdate.Start(playdate.Games()[m.cursor].ID, time.Now())
+				break
+			}
+
+			m.playdate.Consent(m.side, !m.state.Consent[m.side], time.Now())
+		}
+
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+	}
+
+	return m, nil
+}
+
+func (m *Playdate) View() string {
+	var frames [2]string
+	for side, p := range m.state.Pets {
+		animation := p.Animations().ForState(p.GetState())
+		if m.state.Playing {
+			animation = p.Animations().Happy
+		}
+		if len(animation.Frames) > 0 {
+			frames[side] = animation.Frames[m.frame%len(animation.Frames)]
+		}
+	}
+
+	return views.RenderPlaydate(m.width, m.state, m.side, frames, m.cursor, m.message, time.Now())
+}
@@ -0,0 +1,101 @@
+package ui
+
+import (
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kirkegaard/terminal-pet/pkg/shop"
+	"github.com/kirkegaard/terminal-pet/pkg/ui/keymap"
+	"github.com/kirkegaard/terminal-pet/pkg/ui/views"
+)
+
+// ShowShopMsg is sent when the player wants to visit the shop
+type ShowShopMsg struct{}
+
+// CloseShopMsg is sent when the player leaves the shop
+type CloseShopMsg struct{}
+
+// BuyItemMsg is sent when the player wants to buy an item
+type BuyItemMsg struct {
+	Item shop.Item
+}
+
+// Shop lists the items for sale and the player's coins
+type Shop struct {
+	items   []shop.Item
+	owned   map[string]int
+	coins   int
+	message string
+	cursor  int
+	keys    keymap.KeyMap
+	width   int
+	height  int
+}
+
+// NewShop creates a new shop screen
+func NewShop(coins int, owned map[string]int, width, height int) *Shop {
+	return &Shop{
+		items:  shop.Items(),
+		owned:  owned,
+		coins:  coins,
+		keys:   keymap.Keys,
+		width:  width,
+		height: height,
+	}
+}
+
+// SetBalance updates the coins and items shown after a purchase, along with
+// a message about how it went
+func (m *Shop) SetBalance(coins int, owned map[string]int, message string) {
+	m.coins = coins
+	m.owned = owned
+	m.message = message
+}
+
+func (m *Shop) Init() tea.Cmd {
+	return nil
+}
+
+func (m *Shop) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case msg.String() == "esc", key.Matches(msg, m.keys.Quit):
+			return m, func() tea.Msg { return CloseShopMsg{} }
+
+		case key.Matches(msg, m.keys.Up):
+			if m.cursor > 0 {
+				m.cursor--
+			}
+			m.message = ""
+
+		case key.Matches(msg, m.keys.Down):
+			if m.cursor < len(m.items)-1 {
+				m.cursor++
+			}
+			m.message = ""
+
+		case key.Matches(msg, m.keys.Action):
+			if len(m.items) > 0 {
+				selected := m.items[m.cursor]
+				return m, func() tea.Msg { return BuyItemMsg{Item: selected} }
+			}
+		}
+
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+	}
+
+	return m, nil
+}
+
+func (m *Shop) View() string {
+	return views.RenderShop(
+		m.width,
+		m.items,
+		m.owned,
+		m.coins,
+		m.cursor,
+		m.message,
+	)
+}
@@ -0,0 +1,144 @@
+package ui
+
+import (
+	"math"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kirkegaard/terminal-pet/pkg/db/models"
+	"github.com/kirkegaard/terminal-pet/pkg/pet"
+	"github.com/kirkegaard/terminal-pet/pkg/stats"
+	"github.com/kirkegaard/terminal-pet/pkg/ui/keymap"
+	"github.com/kirkegaard/terminal-pet/pkg/ui/views"
+)
+
+// ShowStatHistoryMsg is sent when the player wants to see the history of
+// their pet's stats
+type ShowStatHistoryMsg struct{}
+
+// CloseStatHistoryMsg is sent when the player leaves the stats screen
+type CloseStatHistoryMsg struct{}
+
+// statRange is a span of time the stats can be graphed over. A zero span
+// covers the whole life of the pet.
+type statRange struct {
+	name string
+	span time.Duration
+}
+
+var statRanges = []statRange{
+	{name: "24h", span: 24 * time.Hour},
+	{name: "7d", span: 7 * 24 * time.Hour},
+	{name: "lifetime"},
+}
+
+// StatHistory graphs the recorded stats of a pet as sparklines
+type StatHistory struct {
+	name      string
+	birthday  time.Time
+	now       time.Time
+	snapshots []models.StatSnapshot
+	current   models.StatSnapshot
+	selected  int
+	keys      keymap.KeyMap
+	width     int
+	height    int
+}
+
+// NewStatHistory creates a stats screen for a pet from its recorded
+// snapshots, oldest first. The current stats of the pet are shown as the
+// latest point.
+func NewStatHistory(p *pet.Pet, snapshots []models.StatSnapshot, now time.Time, width, height int) *StatHistory {
+	current := stats.Snapshot(p, now, 0)
+
+	return &StatHistory{
+		name:      p.Name,
+		birthday:  p.BirthDate,
+		now:       now,
+		snapshots: append(snapshots, current),
+		current:   current,
+		keys:      keymap.Keys,
+		width:     width,
+		height:    height,
+	}
+}
+
+// columns returns how wide the sparklines are
+func (m *StatHistory) columns() int {
+	return min(max(m.width-30, 20), 72)
+}
+
+func (m *StatHistory) Init() tea.Cmd {
+	return nil
+}
+
+func (m *StatHistory) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch {
+		case msg.String() == "esc", key.Matches(msg, m.keys.Quit), key.Matches(msg, m.keys.Action):
+			return m, func() tea.Msg { return CloseStatHistoryMsg{} }
+
+		case key.Matches(msg, m.keys.Left):
+			m.selected = (m.selected + len(statRanges) - 1) % len(statRanges)
+
+		case key.Matches(msg, m.keys.Right):
+			m.selected = (m.selected + 1) % len(statRanges)
+		}
+
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+	}
+
+	return m, nil
+}
+
+func (m *StatHistory) View() string {
+	r := statRanges[m.selected]
+	from := m.birthday
+	if r.span > 0 {
+		from = m.now.Add(-r.span)
+	}
+
+	// A pet born a minute ago still gets a readable graph
+	to := m.now.Add(time.Minute)
+
+	names := make([]string, len(statRanges))
+	for i, r := range statRanges {
+		names[i] = r.name
+	}
+
+	chart := func(label string, stat stats.Stat, low, high float64) views.StatChart {
+		return views.StatChart{
+			Label:   label,
+			Values:  stats.Series(m.snapshots, stat, from, to, m.columns()),
+			Low:     low,
+			High:    high,
+			Current: stat(m.current),
+		}
+	}
+
+	// Weight has no upper bound, so it is drawn between its own extremes
+	weight := chart("Weight", stats.Weight, 0, 0)
+	weight.Low, weight.High = math.Inf(1), math.Inf(-1)
+	for _, v := range weight.Values {
+		if !math.IsNaN(v) {
+			weight.Low, weight.High = math.Min(weight.Low, v), math.Max(weight.High, v)
+		}
+	}
+
+	return views.RenderStatHistory(
+		m.width,
+		m.name,
+		names,
+		m.selected,
+		[]views.StatChart{
+			chart("Hunger", stats.Hunger, 0, 100),
+			chart("Happiness", stats.Happiness, 0, 100),
+			chart("Health", stats.Health, 0, 100),
+			weight,
+		},
+	)
+}
@@ -0,0 +1,89 @@
+package views
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/kirkegaard/terminal-pet/pkg/achievements"
+)
+
+var toastStyle = lipgloss.NewStyle().
+	Border(lipgloss.RoundedBorder()).
+	BorderForeground(lipgloss.Color("#FFD700")).
+	Padding(0, 2)
+
+// RenderToast renders the pop-up shown when an achievement is unlocked
+func RenderToast(width int, a achievements.Achievement) string {
+	toast := toastStyle.Render(
+		goodStyle.Render("🏆 Achievement unlocked!") + "\n" +
+			a.Emoji + " " + infoStyle.Render(a.Name) + "\n" +
+			epitaphStyle.Render(a.Description),
+	)
+
+	var sb strings.Builder
+	for _, line := range strings.Split(toast, "\n") {
+		padding := (width - lipgloss.Width(line)) / 2
+		if padding > 0 {
+			sb.WriteString(strings.Repeat(" ", padding))
+		}
+		sb.WriteString(line)
+		sb.WriteString("\n")
+	}
+
+	return sb.String()
+}
+
+// RenderAchievements renders every achievement along with the player's
+// progress towards it
+func RenderAchievements(width int, badges []achievements.Status) string {
+	var sb strings.Builder
+
+	// Title
+	title := titleStyle.Render("🏅 Achievements 🏅")
+	for _, line := range strings.Split(title, "\n") {
+		padding := (width - lipgloss.Width(line)) / 2
+		if padding > 0 {
+			sb.WriteString(strings.Repeat(" ", padding))
+		}
+		sb.WriteString(line)
+		sb.WriteString("\n")
+	}
+	sb.WriteString("\n")
+
+	unlocked := 0
+	for _, b := range badges {
+		if b.Unlocked() {
+			unlocked++
+		}
+	}
+
+	sb.WriteString(strings.Repeat(" ", 5))
+	sb.WriteString(infoStyle.Render("Unlocked:") + fmt.Sprintf(" %d/%d", unlocked, len(badges)))
+	sb.WriteString("\n\n")
+
+	for _, b := range badges {
+		sb.WriteString(strings.Repeat(" ", 5))
+
+		if b.Unlocked() {
+			sb.WriteString(b.Emoji + " " + infoStyle.Render(b.Name))
+			sb.WriteString(epitaphStyle.Render(" - unlocked on " + b.UnlockedAt.Local().Format("2006-01-02")))
+		} else {
+			sb.WriteString("🔒 " + disabledStyle.Render(b.Name))
+			if b.Goal() > 1 {
+				sb.WriteString(epitaphStyle.Render(fmt.Sprintf(" - %d/%d", b.Progress, b.Goal())))
+			}
+		}
+		sb.WriteString("\n")
+
+		sb.WriteString(strings.Repeat(" ", 8))
+		sb.WriteString(b.Description)
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString("\n")
+	sb.WriteString(strings.Repeat(" ", 5))
+	sb.WriteString(hintStyle.Render("Press ESC or Enter to go back"))
+
+	return sb.String()
+}
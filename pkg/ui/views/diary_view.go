@@ -0,0 +1,54 @@
+package views
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// RenderDiary renders a page of a pet's diary. lines are the entries shown,
+// offset is the index of the first one among all of them.
+func RenderDiary(
+	width int,
+	name string,
+	lines []string,
+	offset int,
+	total int,
+) string {
+	var sb strings.Builder
+
+	// Title
+	title := titleStyle.Render("📖 " + name + "'s Diary 📖")
+	for _, line := range strings.Split(title, "\n") {
+		padding := (width - lipgloss.Width(line)) / 2
+		if padding > 0 {
+			sb.WriteString(strings.Repeat(" ", padding))
+		}
+		sb.WriteString(line)
+		sb.WriteString("\n")
+	}
+	sb.WriteString("\n")
+
+	if total == 0 {
+		sb.WriteString(strings.Repeat(" ", 5))
+		sb.WriteString(epitaphStyle.Render("Nothing has happened yet."))
+		sb.WriteString("\n")
+	}
+
+	for _, line := range lines {
+		sb.WriteString(strings.Repeat(" ", 5))
+		sb.WriteString(line)
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString("\n")
+	sb.WriteString(strings.Repeat(" ", 5))
+	if total > len(lines) {
+		sb.WriteString(infoStyle.Render(fmt.Sprintf("%d-%d of %d", offset+1, offset+len(lines), total)))
+		sb.WriteString("   ")
+	}
+	sb.WriteString(hintStyle.Render("↑/↓ to scroll, ←/→ for pages, ESC to go back"))
+
+	return sb.String()
+}
@@ -0,0 +1,157 @@
+package views
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/kirkegaard/terminal-pet/pkg/pet"
+)
+
+// petColor returns a style drawing a pet in its color, pets without one are
+// drawn as they are
+func petColor(p *pet.Pet) lipgloss.Style {
+	style := lipgloss.NewStyle()
+	if c, ok := p.Color(); ok {
+		style = style.Foreground(lipgloss.Color(c.Hex))
+	}
+
+	return style
+}
+
+// capitalize upper cases the first letter of a message
+func capitalize(s string) string {
+	r := []rune(s)
+	if len(r) > 0 {
+		r[0] = unicode.ToUpper(r[0])
+	}
+
+	return string(r)
+}
+
+// relative describes a pet in a family tree by name, owner and whether it
+// is still alive
+func relative(p *pet.Pet) string {
+	label := petColor(p).Render(p.Name) + normalStyle.Render(" ("+p.Parent.Name+")")
+	if p.IsDead() {
+		label += epitaphStyle.Render(" ✝")
+	}
+
+	return label
+}
+
+// relatives lists pets in a family tree
+func relatives(pets []*pet.Pet) string {
+	labels := make([]string, len(pets))
+	for i, p := range pets {
+		labels[i] = relative(p)
+	}
+
+	return strings.Join(labels, normalStyle.Render(" & "))
+}
+
+// geneBar draws a base stat as a bar from MinGene to MaxGene
+func geneBar(gene int) string {
+	gene = max(pet.MinGene, min(gene, pet.MaxGene))
+	return strings.Repeat("●", gene) + strings.Repeat("○", pet.MaxGene-gene)
+}
+
+// RenderFamily renders the genes of a pet and its family tree: its parents
+// with their own parents, and its babies
+func RenderFamily(
+	width int,
+	p *pet.Pet,
+	parents []*pet.Pet,
+	grandparents [][]*pet.Pet,
+	children []*pet.Pet,
+) string {
+	var sb strings.Builder
+
+	// Title
+	title := titleStyle.Render("🌳 " + p.Name + "'s Family 🌳")
+	for _, line := range strings.Split(title, "\n") {
+		padding := (width - lipgloss.Width(line)) / 2
+		if padding > 0 {
+			sb.WriteString(strings.Repeat(" ", padding))
+		}
+		sb.WriteString(line)
+		sb.WriteString("\n")
+	}
+	sb.WriteString("\n")
+
+	// Genes
+	color := "None"
+	if c, ok := p.Color(); ok {
+		color = petColor(p).Render(c.Name)
+	}
+	sb.WriteString(strings.Repeat(" ", 5))
+	sb.WriteString(infoStyle.Render("Color:") + "    " + color + "\n")
+
+	var traits []string
+	for _, id := range p.Genes.Traits {
+		if t, ok := pet.TraitByID(id); ok {
+			traits = append(traits, t.Name+epitaphStyle.Render(" ("+strings.ToLower(t.Description)+")"))
+		}
+	}
+	if len(traits) == 0 {
+		traits = append(traits, "None")
+	}
+	sb.WriteString(strings.Repeat(" ", 5))
+	sb.WriteString(infoStyle.Render("Traits:") + "   " + strings.Join(traits, ", ") + "\n")
+
+	for _, gene := range []struct {
+		label string
+		value int
+	}{
+		{"Appetite:", p.Genes.Appetite},
+		{"Spirit:", p.Genes.Spirit},
+		{"Vigor:", p.Genes.Vigor},
+	} {
+		sb.WriteString(strings.Repeat(" ", 5))
+		sb.WriteString(infoStyle.Render(gene.label))
+		sb.WriteString(strings.Repeat(" ", 10-len(gene.label)))
+		sb.WriteString(geneBar(gene.value) + "\n")
+	}
+	sb.WriteString("\n")
+
+	// Parents and grandparents
+	sb.WriteString(strings.Repeat(" ", 5))
+	sb.WriteString(highlightStyle.Render("Parents"))
+	sb.WriteString("\n")
+	if len(parents) == 0 {
+		sb.WriteString(strings.Repeat(" ", 7))
+		sb.WriteString(epitaphStyle.Render(p.Name + " was adopted."))
+		sb.WriteString("\n")
+	}
+	for i, parent := range parents {
+		sb.WriteString(strings.Repeat(" ", 7))
+		sb.WriteString(relative(parent))
+		if i < len(grandparents) && len(grandparents[i]) > 0 {
+			sb.WriteString(epitaphStyle.Render(", baby of ") + relatives(grandparents[i]))
+		}
+		sb.WriteString("\n")
+	}
+	sb.WriteString("\n")
+
+	// Children
+	sb.WriteString(strings.Repeat(" ", 5))
+	sb.WriteString(highlightStyle.Render(fmt.Sprintf("Babies (%d)", len(children))))
+	sb.WriteString("\n")
+	if len(children) == 0 {
+		sb.WriteString(strings.Repeat(" ", 7))
+		sb.WriteString(epitaphStyle.Render("No babies yet. Grown up pets can have one on a playdate."))
+		sb.WriteString("\n")
+	}
+	for _, child := range children {
+		sb.WriteString(strings.Repeat(" ", 7))
+		sb.WriteString(relative(child))
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString("\n")
+	sb.WriteString(strings.Repeat(" ", 5))
+	sb.WriteString(hintStyle.Render("ESC to go back"))
+
+	return sb.String()
+}
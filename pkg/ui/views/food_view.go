@@ -13,7 +13,7 @@ func RenderFoodSelectionView(
 	width int,
 	pet *pet.Pet,
 	foodCursor int,
-	foodOptions []string,
+	foods []*pet.Food,
 ) string {
 	var output strings.Builder
 	output.WriteString(baseOutput)
@@ -52,9 +52,9 @@ func RenderFoodSelectionView(
 	output.WriteString("\n\n")
 
 	// Food options with descriptions
-	foodDescriptions := []string{
-		"Burger - Nutritious meal (requires hunger > 20)",
-		"Cake - Sweet treat (can feed anytime)",
+	foodDescriptions := make([]string, len(foods))
+	for i, food := range foods {
+		foodDescriptions[i] = fmt.Sprintf("%s %s - %s", food.Emoji, food.Name, food.Description)
 	}
 
 	for i, description := range foodDescriptions {
@@ -87,23 +87,24 @@ func RenderFoodSelectionView(
 	output.WriteString("\n")
 
 	// Food effects info
-	burgerInfo := "Burger: +10 Health, -10 Hunger, +2 Weight"
-	cakeInfo := "Cake: +0 Health, +0 Hunger, +5 Weight"
+	for _, food := range foods {
+		info := fmt.Sprintf("%s: %+d Health, -%d Hunger, %+d Happiness, %+d Weight",
+			food.Name, food.Health, food.Hunger, food.Happiness, food.Weight)
+		if food.SicknessRisk > 0 {
+			info += fmt.Sprintf(", %.0f%% sickness risk", food.SicknessRisk*100)
+		}
 
-	infoWidth := len(burgerInfo)
-	infoPadding := (width - infoWidth) / 2
-	if infoPadding < 0 {
-		infoPadding = 0
-	}
+		infoPadding := (width - len(info)) / 2
+		if infoPadding < 0 {
+			infoPadding = 0
+		}
 
-	output.WriteString(strings.Repeat(" ", infoPadding))
-	output.WriteString(infoStyle.Render(burgerInfo))
+		output.WriteString(strings.Repeat(" ", infoPadding))
+		output.WriteString(infoStyle.Render(info))
+		output.WriteString("\n")
+	}
 	output.WriteString("\n")
 
-	output.WriteString(strings.Repeat(" ", infoPadding))
-	output.WriteString(infoStyle.Render(cakeInfo))
-	output.WriteString("\n\n")
-
 	// Controls
 	controls := "Arrow keys: Navigate   Enter: Select   ESC: Cancel"
 	controlsWidth := len(controls)
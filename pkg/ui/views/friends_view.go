@@ -0,0 +1,167 @@
+package views
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/kirkegaard/terminal-pet/pkg/pet"
+	"github.com/kirkegaard/terminal-pet/pkg/shop"
+)
+
+// friendNameWidth is how much room the names on the friends screen get
+const friendNameWidth = 16
+
+// FriendEntry is a friend or friend request on the friends screen. Pending
+// requests weren't accepted yet, incoming ones were sent by the other
+// player. Pets are only filled in for friends.
+type FriendEntry struct {
+	Name     string
+	Online   bool
+	LastSeen time.Time
+	Pets     []*pet.Pet
+	Pending  bool
+	Incoming bool
+}
+
+// FriendStatus describes when a friend was last around
+func FriendStatus(f FriendEntry) string {
+	switch {
+	case f.Online:
+		return "online now"
+	case f.LastSeen.IsZero():
+		return "not seen yet"
+	default:
+		return "seen " + f.LastSeen.Local().Format("Jan 2 15:04")
+	}
+}
+
+// RenderFriends renders the friends of the player and the requests between
+// them and others, with a prompt for the name of a new friend while adding
+// one and the items to give while picking a gift
+func RenderFriends(
+	width int,
+	friends []FriendEntry,
+	cursor int,
+	adding bool,
+	name string,
+	gifts []shop.Item,
+	owned map[string]int,
+	giftCursor int,
+	message string,
+	failed bool,
+) string {
+	var sb strings.Builder
+
+	// Title
+	title := titleStyle.Render("💌 Friends 💌")
+	for _, line := range strings.Split(title, "\n") {
+		padding := (width - lipgloss.Width(line)) / 2
+		if padding > 0 {
+			sb.WriteString(strings.Repeat(" ", padding))
+		}
+		sb.WriteString(line)
+		sb.WriteString("\n")
+	}
+	sb.WriteString("\n")
+
+	if len(friends) == 0 {
+		sb.WriteString(strings.Repeat(" ", 5))
+		sb.WriteString(epitaphStyle.Render("No friends yet, press A to add someone."))
+		sb.WriteString("\n")
+	}
+
+	for i, f := range friends {
+		// Requests are listed after the friends
+		if f.Pending && (i == 0 || !friends[i-1].Pending) {
+			if i > 0 {
+				sb.WriteString("\n")
+			}
+			sb.WriteString(strings.Repeat(" ", 5))
+			sb.WriteString(infoStyle.Render("Friend requests"))
+			sb.WriteString("\n")
+		}
+
+		label := fmt.Sprintf("%-*s", friendNameWidth, f.Name)
+		sb.WriteString(strings.Repeat(" ", 5))
+		if i == cursor {
+			sb.WriteString("> " + highlightStyle.Render(label))
+		} else {
+			sb.WriteString("  " + normalStyle.Render(" "+label+" "))
+		}
+		sb.WriteString("  ")
+
+		switch {
+		case f.Pending && f.Incoming:
+			sb.WriteString(goodStyle.Render("wants to be your friend"))
+		case f.Pending:
+			sb.WriteString(epitaphStyle.Render("hasn't answered yet"))
+		default:
+			sb.WriteString(infoStyle.Render(fmt.Sprintf("%-22s", FriendStatus(f))))
+			sb.WriteString(" " + friendPets(f.Pets))
+		}
+		sb.WriteString("\n")
+	}
+
+	if adding {
+		sb.WriteString("\n")
+		sb.WriteString(strings.Repeat(" ", 5))
+		sb.WriteString(infoStyle.Render("Player:") + " " + highlightStyle.Render(name+"▌"))
+		sb.WriteString("\n")
+	}
+
+	if len(gifts) > 0 {
+		sb.WriteString("\n")
+		sb.WriteString(strings.Repeat(" ", 5))
+		sb.WriteString(infoStyle.Render("Give "+friends[cursor].Name+":") + " ")
+		for i, item := range gifts {
+			option := fmt.Sprintf("%s %s x%d", item.Emoji, item.Name, owned[item.ID])
+			if i == giftCursor {
+				sb.WriteString(highlightStyle.Render(option))
+			} else {
+				sb.WriteString(normalStyle.Render(" " + option + " "))
+			}
+			sb.WriteString(" ")
+		}
+		sb.WriteString("\n")
+	}
+
+	if message != "" {
+		sb.WriteString("\n")
+		sb.WriteString(strings.Repeat(" ", 5))
+		if failed {
+			sb.WriteString(warningStyle.Render(message))
+		} else {
+			sb.WriteString(goodStyle.Render(message))
+		}
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString("\n")
+	sb.WriteString(strings.Repeat(" ", 5))
+	switch {
+	case adding:
+		sb.WriteString(hintStyle.Render("ENTER to send a friend request, ESC to cancel"))
+	case len(gifts) > 0:
+		sb.WriteString(hintStyle.Render("←/→ to choose, ENTER to give, ESC to cancel"))
+	default:
+		sb.WriteString(hintStyle.Render("↑/↓ to choose, ENTER to accept or give a gift, A to add, X to remove, ESC to go back"))
+	}
+
+	return sb.String()
+}
+
+// friendPets describes the state of a friend's living pets in their colors
+func friendPets(pets []*pet.Pet) string {
+	if len(pets) == 0 {
+		return epitaphStyle.Render("no living pets")
+	}
+
+	labels := make([]string, len(pets))
+	for i, p := range pets {
+		labels[i] = petColor(p).Render(p.Name) + normalStyle.Render(": "+GetPetState(p))
+	}
+
+	return strings.Join(labels, normalStyle.Render(", "))
+}
@@ -12,6 +12,7 @@ import (
 func RenderGameView(
 	baseView string,
 	width int,
+	animations ascii.AnimationSet,
 	currentFrame int,
 	animState string,
 	petPosition int,
@@ -48,25 +49,25 @@ func RenderGameView(
 	// Pick pet animation based on game state
 	if showResult {
 		if lastGuessWasCorrect {
-			animation = ascii.Happy
+			animation = animations.Happy
 		} else {
-			animation = ascii.Sad
+			animation = animations.Sad
 		}
 	} else {
-		animation = ascii.Playing
+		animation = animations.Playing
 	}
 
 	// Get current frame
-	frameIdx := currentFrame
-	if frameIdx >= len(animation.Frames) {
-		frameIdx = 0
+	frameIdx := 0
+	if len(animation.Frames) > 0 {
+		frameIdx = currentFrame % len(animation.Frames)
 	}
 
 	var frame string
 	if len(animation.Frames) > 0 {
 		frame = animation.Frames[frameIdx]
 	} else {
-		frame = ascii.Happy.Frames[0] // Default frame
+		frame = animations.Happy.Frames[0] // Default frame
 	}
 
 	// Add spacing
@@ -6,7 +6,6 @@ import (
 
 	"github.com/charmbracelet/lipgloss"
 	"github.com/kirkegaard/terminal-pet/pkg/pet"
-	"github.com/kirkegaard/terminal-pet/pkg/pet/ascii"
 )
 
 // RenderGameOver renders the game over screen
@@ -36,7 +35,7 @@ func RenderGameOver(
 	sb.WriteString("\n\n")
 
 	// Pet animation (dead)
-	frame := ascii.Dead.Frames[0]
+	frame := pet.Animations().Dead.Frames[0]
 
 	// Center the frame
 	for _, line := range strings.Split(frame, "\n") {
@@ -64,8 +63,8 @@ func RenderGameOver(
 	sb.WriteString("\n\n")
 
 	// Pet stats
-	ageDays := pet.Age()
-	lifeStage := pet.LifeStage()
+	ageDays := pet.AgeAt(pet.SimulatedAt)
+	lifeStage := pet.LifeStageAt(pet.SimulatedAt)
 	petAge := fmt.Sprintf("Age: %d days (%s)", ageDays, lifeStage)
 
 	padding = (width - lipgloss.Width(petAge)) / 2
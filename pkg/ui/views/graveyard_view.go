@@ -0,0 +1,91 @@
+package views
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/kirkegaard/terminal-pet/pkg/pet"
+)
+
+var epitaphStyle = lipgloss.NewStyle().
+	Foreground(lipgloss.Color("#AAAAAA")).
+	Italic(true)
+
+// RenderGraveyard renders the list of a player's deceased pets
+func RenderGraveyard(
+	width int,
+	pets []*pet.Pet,
+	cursor int,
+	inEpitaphMode bool,
+	epitaph string,
+) string {
+	var sb strings.Builder
+
+	// Title
+	title := titleStyle.Render("🪦 Graveyard 🪦")
+	for _, line := range strings.Split(title, "\n") {
+		padding := (width - lipgloss.Width(line)) / 2
+		if padding > 0 {
+			sb.WriteString(strings.Repeat(" ", padding))
+		}
+		sb.WriteString(line)
+		sb.WriteString("\n")
+	}
+	sb.WriteString("\n")
+
+	if len(pets) == 0 {
+		sb.WriteString(strings.Repeat(" ", 5))
+		sb.WriteString(epitaphStyle.Render("No pets rest here yet."))
+		sb.WriteString("\n")
+	}
+
+	for i, p := range pets {
+		days := int(p.Lifetime(p.SimulatedAt).Hours() / 24)
+
+		cause := p.CauseOfDeath
+		if cause == "" {
+			cause = "unknown causes"
+		}
+
+		died := "unknown date"
+		if !p.DiedAt.IsZero() {
+			died = p.DiedAt.Format("Jan 2 2006")
+		}
+
+		line := fmt.Sprintf("%s - %d days (%s), died of %s on %s", p.Name, days, p.FinalLifeStage(p.SimulatedAt), cause, died)
+
+		sb.WriteString(strings.Repeat(" ", 5))
+		if i == cursor {
+			sb.WriteString("> " + highlightStyle.Render(line))
+		} else {
+			sb.WriteString("  " + normalStyle.Render(" "+line+" "))
+		}
+		sb.WriteString("\n")
+
+		// Epitaph below each grave
+		sb.WriteString(strings.Repeat(" ", 9))
+		if i == cursor && inEpitaphMode {
+			sb.WriteString(infoStyle.Render("Epitaph:") + " " + highlightStyle.Render(epitaph+"▌"))
+		} else if p.Epitaph != "" {
+			sb.WriteString(epitaphStyle.Render(fmt.Sprintf("\"%s\"", p.Epitaph)))
+		} else {
+			sb.WriteString(disabledStyle.Render("Here lies " + p.Name))
+		}
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString("\n")
+	sb.WriteString(strings.Repeat(" ", 5))
+
+	instructions := "↑/↓ to navigate, Enter to write an epitaph, ESC to go back"
+	if inEpitaphMode {
+		instructions = "Enter to save the epitaph, ESC to cancel"
+	}
+
+	sb.WriteString(lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#AAAAAA")).
+		Render(instructions))
+
+	return sb.String()
+}
@@ -0,0 +1,63 @@
+package views
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/kirkegaard/terminal-pet/pkg/db/models"
+)
+
+// RenderLeaderboard renders the top scores of one game. page is the index
+// of the game among all pages, and the scores of userID are highlighted.
+func RenderLeaderboard(
+	width int,
+	name string,
+	page int,
+	pages int,
+	scores []models.GameScore,
+	userID int,
+) string {
+	var sb strings.Builder
+
+	// Title
+	title := titleStyle.Render("🏆 High Scores 🏆")
+	for _, line := range strings.Split(title, "\n") {
+		padding := (width - lipgloss.Width(line)) / 2
+		if padding > 0 {
+			sb.WriteString(strings.Repeat(" ", padding))
+		}
+		sb.WriteString(line)
+		sb.WriteString("\n")
+	}
+	sb.WriteString("\n")
+
+	sb.WriteString(strings.Repeat(" ", 5))
+	sb.WriteString(infoStyle.Render(name) + fmt.Sprintf(" (%d/%d)", page+1, pages))
+	sb.WriteString("\n\n")
+
+	if len(scores) == 0 {
+		sb.WriteString(strings.Repeat(" ", 5))
+		sb.WriteString(epitaphStyle.Render("Nobody has played this game yet."))
+		sb.WriteString("\n")
+	}
+
+	for i, score := range scores {
+		line := fmt.Sprintf("%2d. %-16s %-16s %4d   %s",
+			i+1, score.UserName, score.PetName, score.Score, score.CreatedAt.Local().Format("2006-01-02"))
+
+		sb.WriteString(strings.Repeat(" ", 5))
+		if score.UserID == userID {
+			sb.WriteString(highlightStyle.Render(line))
+		} else {
+			sb.WriteString(normalStyle.Render(" " + line + " "))
+		}
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString("\n")
+	sb.WriteString(strings.Repeat(" ", 5))
+	sb.WriteString(hintStyle.Render("←/→ to switch games, ESC to go back"))
+
+	return sb.String()
+}
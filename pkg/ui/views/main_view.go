@@ -11,7 +11,7 @@ import (
 	"github.com/kirkegaard/terminal-pet/pkg/ui/keymap"
 )
 
-var choices = []string{"Feed", "Clean", "Play", "Medicine", "Rename", "Toggle Lights", "Quit"}
+var choices = []string{"Feed", "Clean", "Play", "Medicine", "Scold", "Praise", "Rename", "Shop", "Scores", "Badges", "Diary", "Stats", "Family", "Visit", "Friends", "Playdate", "Toggle Lights", "Quit"}
 
 var (
 	normalStyle = lipgloss.NewStyle().
@@ -102,6 +102,29 @@ func GetPetState(pet *pet.Pet) string {
 	return "Content"
 }
 
+// getHearts draws a percentage as up to five hearts
+func getHearts(percentage int) string {
+	percentage = clamp(percentage, 0, 100)
+
+	fullHearts := percentage / 20
+	halfHeart := percentage%20 >= 10
+
+	hearts := strings.Repeat("♥ ", fullHearts)
+	if halfHeart {
+		hearts += "♡ "
+	}
+	return hearts
+}
+
+// misbehaviorMessage describes what the pet is doing that deserves a scolding
+func misbehaviorMessage(p *pet.Pet) string {
+	if p.Misbehavior == pet.MisbehaviorRefusal {
+		return "Your pet is being naughty! ❗"
+	}
+
+	return "Your pet is calling for attention! ❗"
+}
+
 func RenderMainView(
 	pet *pet.Pet,
 	currentAnim ascii.Animation,
@@ -118,6 +141,8 @@ func RenderMainView(
 	showFoodSubmenu bool,
 	foodSubmenuCursor int,
 	foodOptions []string,
+	coins int,
+	notice string,
 ) string {
 	var output strings.Builder
 
@@ -132,16 +157,19 @@ func RenderMainView(
 		frameStr = currentAnim.Frames[currentFrame]
 	}
 
-	lines := strings.Split(frameStr, "\n")
+	lines := strings.Split(petColor(pet).Render(frameStr), "\n")
 	offsetLines := make([]string, len(lines))
 
 	for i, line := range lines {
 		offsetLines[i] = strings.Repeat(" ", basePadding+petPosition) + line
 	}
 
-	if pet.IsSick || pet.HasPooped {
+	if pet.IsSick || pet.HasPooped || pet.IsMisbehaving() {
 		if len(offsetLines) >= 2 {
 			statusIndicators := ""
+			if pet.IsMisbehaving() {
+				statusIndicators += "❗"
+			}
 			if pet.IsSick {
 				statusIndicators += "☠️"
 			}
@@ -162,29 +190,19 @@ func RenderMainView(
 	output.WriteString("\n")
 
 	if showStats {
-		getHearts := func(percentage int) string {
-			percentage = clamp(percentage, 0, 100)
-
-			fullHearts := percentage / 20
-			halfHeart := percentage%20 >= 10
-
-			hearts := strings.Repeat("♥ ", fullHearts)
-			if halfHeart {
-				hearts += "♡ "
-			}
-			return hearts
-		}
-
-		ageDays := pet.Age()
-		lifeStage := pet.LifeStage()
+		ageDays := pet.AgeAt(pet.SimulatedAt)
+		lifeStage := pet.LifeStageAt(pet.SimulatedAt)
 		petState := GetPetState(pet)
 
+		speciesLabel := infoStyle.Render("Species:")
+		output.WriteString(speciesLabel + " " + pet.Species().Name + "\n")
+
 		stateLabel := infoStyle.Render("State:")
 		stateValue := fmt.Sprintf(" %s", petState)
 		output.WriteString(stateLabel + stateValue + "\n")
 
 		ageLabel := infoStyle.Render("Age:")
-		ageValue := fmt.Sprintf(" %d days (%s)", ageDays, lifeStage)
+		ageValue := fmt.Sprintf(" %d days (%s, %s)", ageDays, pet.Character().Name, lifeStage)
 		output.WriteString(ageLabel + ageValue + "\n")
 
 		healthLabel := infoStyle.Render("Health:")
@@ -215,13 +233,19 @@ func RenderMainView(
 		}
 
 		output.WriteString(weightLabel + weightStr + "\n")
+
+		disciplineLabel := infoStyle.Render("Discipline:")
+		disciplineHearts := getHearts(pet.Discipline)
+		output.WriteString(disciplineLabel + " " + disciplineHearts + "\n")
+
+		coinsLabel := infoStyle.Render("Coins:")
+		output.WriteString(coinsLabel + fmt.Sprintf(" %d 🪙", coins) + "\n")
 	}
 
 	output.WriteString("\n\n")
 
 	for i, choice := range choices {
-		// 5=Toggle Lights, 6=Quit
-		if !pet.LightsOn && i != 5 && i != 6 {
+		if !pet.LightsOn && choice != "Shop" && choice != "Scores" && choice != "Badges" && choice != "Diary" && choice != "Stats" && choice != "Family" && choice != "Visit" && choice != "Friends" && choice != "Toggle Lights" && choice != "Quit" {
 			output.WriteString(disabledStyle.Render(" " + choice + " "))
 		} else if i == cursor {
 			if i == selectedAction {
@@ -240,7 +264,7 @@ func RenderMainView(
 	}
 	output.WriteString("\n")
 
-	if !pet.LightsOn || pet.IsSick || pet.HasPooped {
+	if !pet.LightsOn || pet.IsSick || pet.HasPooped || pet.IsMisbehaving() {
 		output.WriteString("\n")
 
 		if !pet.LightsOn {
@@ -263,6 +287,17 @@ func RenderMainView(
 			output.WriteString(poopMsg)
 			output.WriteString("\n")
 		}
+
+		if pet.IsMisbehaving() {
+			output.WriteString(warningStyle.Render(misbehaviorMessage(pet)))
+			output.WriteString("\n")
+		}
+	}
+
+	if notice != "" {
+		output.WriteString("\n")
+		output.WriteString(infoStyle.Render(notice))
+		output.WriteString("\n")
 	}
 
 	if showFoodSubmenu {
@@ -0,0 +1,308 @@
+package views
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+var (
+	gameTitleStyle = lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color("#FFFFFF")).
+			Background(lipgloss.Color("#FF6700")).
+			Padding(0, 1)
+
+	goodStyle = lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color("#00AA00"))
+
+	badStyle = lipgloss.NewStyle().
+			Bold(true).
+			Foreground(lipgloss.Color("#AA0000"))
+
+	hintStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#AAAAAA"))
+)
+
+// gamePadding is the indentation of everything below a game's title
+const gamePadding = 10
+
+// writeGameTitle writes a centered game title
+func writeGameTitle(sb *strings.Builder, width int, title string) {
+	rendered := gameTitleStyle.Render(" " + strings.ToUpper(title) + " ")
+
+	padding := (width - lipgloss.Width(rendered)) / 2
+	if padding > 0 {
+		sb.WriteString(strings.Repeat(" ", padding))
+	}
+	sb.WriteString(rendered)
+	sb.WriteString("\n\n")
+}
+
+// writeGameLine writes an indented line below the game title
+func writeGameLine(sb *strings.Builder, line string) {
+	sb.WriteString(strings.Repeat(" ", gamePadding))
+	sb.WriteString(line)
+	sb.WriteString("\n")
+}
+
+// writePetFrame writes the pet with an optional text next to its head
+func writePetFrame(sb *strings.Builder, frame string, beside string) {
+	lines := strings.Split(strings.Trim(frame, "\n"), "\n")
+
+	width := 0
+	for _, line := range lines {
+		width = max(width, lipgloss.Width(line))
+	}
+
+	for i, line := range lines {
+		sb.WriteString(strings.Repeat(" ", gamePadding))
+		sb.WriteString(line)
+		if i == 1 && beside != "" {
+			sb.WriteString(strings.Repeat(" ", width-lipgloss.Width(line)+4))
+			sb.WriteString(beside)
+		}
+		sb.WriteString("\n")
+	}
+}
+
+// RenderGamePicker renders the list of minigames shown when playing with
+// the pet
+func RenderGamePicker(
+	width int,
+	names []string,
+	descriptions []string,
+	cursor int,
+) string {
+	var sb strings.Builder
+
+	title := titleStyle.Render("🎮 Play a Game 🎮")
+	for _, line := range strings.Split(title, "\n") {
+		padding := (width - lipgloss.Width(line)) / 2
+		if padding > 0 {
+			sb.WriteString(strings.Repeat(" ", padding))
+		}
+		sb.WriteString(line)
+		sb.WriteString("\n")
+	}
+	sb.WriteString("\n")
+
+	for i, name := range names {
+		sb.WriteString(strings.Repeat(" ", 5))
+		if i == cursor {
+			sb.WriteString("> " + highlightStyle.Render(name))
+		} else {
+			sb.WriteString("  " + normalStyle.Render(" "+name+" "))
+		}
+		sb.WriteString("\n")
+	}
+
+	if cursor >= 0 && cursor < len(descriptions) {
+		sb.WriteString("\n")
+		sb.WriteString(strings.Repeat(" ", 5))
+		sb.WriteString(epitaphStyle.Render(descriptions[cursor]))
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString("\n")
+	sb.WriteString(strings.Repeat(" ", 5))
+	sb.WriteString(hintStyle.Render("↑/↓ to choose, Enter to play, ESC to go back"))
+
+	return sb.String()
+}
+
+// RenderGameResult renders the outcome of a finished minigame
+func RenderGameResult(
+	width int,
+	name string,
+	frame string,
+	score int,
+	maxScore int,
+	happiness int,
+	weight int,
+	coins int,
+	toy string,
+	best int,
+	newBest bool,
+) string {
+	var sb strings.Builder
+
+	writeGameTitle(&sb, width, name)
+	writePetFrame(&sb, frame, "")
+	sb.WriteString("\n")
+
+	writeGameLine(&sb, infoStyle.Render(fmt.Sprintf("Final score: %d/%d", score, maxScore)))
+	if newBest {
+		writeGameLine(&sb, goodStyle.Render("New personal best!"))
+	} else if best > 0 {
+		writeGameLine(&sb, fmt.Sprintf("Personal best: %d", best))
+	}
+	writeGameLine(&sb, fmt.Sprintf("Happiness %+d, weight %+d", happiness, weight))
+
+	if toy != "" {
+		writeGameLine(&sb, fmt.Sprintf("The %s made it extra fun.", toy))
+	}
+	if coins > 0 {
+		writeGameLine(&sb, goodStyle.Render(fmt.Sprintf("You won %d coins!", coins)))
+	}
+
+	sb.WriteString("\n")
+	writeGameLine(&sb, hintStyle.Render("Press any key to continue"))
+
+	return sb.String()
+}
+
+// RenderDodgeView renders the dodge game. ball is how far the ball has
+// come from 0 to 1, or negative when none is thrown.
+func RenderDodgeView(
+	width int,
+	frame string,
+	jumping bool,
+	ball float64,
+	round int,
+	rounds int,
+	score int,
+	lives int,
+	message string,
+) string {
+	var sb strings.Builder
+
+	writeGameTitle(&sb, width, "Dodge")
+
+	lines := strings.Split(strings.Trim(frame, "\n"), "\n")
+	petWidth := 0
+	for _, line := range lines {
+		petWidth = max(petWidth, lipgloss.Width(line))
+	}
+
+	// The pet stands on the ground, and jumps a line up to dodge
+	if jumping {
+		lines = append(lines, "")
+	} else {
+		lines = append([]string{""}, lines...)
+	}
+
+	const track = 30
+	for i, line := range lines {
+		sb.WriteString(strings.Repeat(" ", gamePadding))
+		sb.WriteString(line)
+
+		// The ball rolls along the ground towards the pet
+		if i == len(lines)-1 && ball >= 0 {
+			position := track - int(min(ball, 1)*track)
+			sb.WriteString(strings.Repeat(" ", petWidth-lipgloss.Width(line)+position))
+			sb.WriteString("⚾")
+		}
+		sb.WriteString("\n")
+	}
+	writeGameLine(&sb, strings.Repeat("‾", petWidth+track+2))
+	sb.WriteString("\n")
+
+	messageStyle := infoStyle
+	switch message {
+	case "Jump!", "Nice jump!":
+		messageStyle = goodStyle
+	case "Too early!", "Ouch! Too slow":
+		messageStyle = badStyle
+	}
+	writeGameLine(&sb, messageStyle.Render(message))
+	sb.WriteString("\n")
+
+	writeGameLine(&sb, infoStyle.Render(fmt.Sprintf("Round: %d/%d   Dodged: %d   Lives: %s",
+		min(round+1, rounds), rounds, score, strings.Repeat("♥ ", lives))))
+	sb.WriteString("\n")
+	writeGameLine(&sb, "Space/↑: Jump   ESC: Exit")
+
+	return sb.String()
+}
+
+// RenderMemoryView renders the memory game. shown is the arrow being played
+// back and entered the arrows the player entered so far.
+func RenderMemoryView(
+	width int,
+	frame string,
+	shown string,
+	entered []string,
+	length int,
+	score int,
+	maxScore int,
+	message string,
+) string {
+	var sb strings.Builder
+
+	writeGameTitle(&sb, width, "Memory")
+
+	beside := ""
+	if shown != "" {
+		beside = highlightStyle.Render(" " + shown + " ")
+	}
+	writePetFrame(&sb, frame, beside)
+	sb.WriteString("\n")
+
+	writeGameLine(&sb, infoStyle.Render(message))
+	sb.WriteString("\n")
+
+	// One slot for every arrow in the sequence
+	slots := make([]string, length)
+	for i := range slots {
+		slots[i] = "·"
+		if i < len(entered) {
+			slots[i] = entered[i]
+		}
+	}
+	writeGameLine(&sb, strings.Join(slots, " "))
+	sb.WriteString("\n")
+
+	writeGameLine(&sb, infoStyle.Render(fmt.Sprintf("Longest sequence: %d/%d", score, maxScore)))
+	sb.WriteString("\n")
+	writeGameLine(&sb, "Arrow keys: Repeat the sequence   ESC: Exit")
+
+	return sb.String()
+}
+
+// RenderRockPaperScissorsView renders a game of rock, paper, scissors.
+// outcome is 1 when the player won the last round, -1 when the pet did and
+// 0 for a draw.
+func RenderRockPaperScissorsView(
+	width int,
+	name string,
+	frame string,
+	player string,
+	petHand string,
+	outcome int,
+	round int,
+	rounds int,
+	wins int,
+	draws int,
+) string {
+	var sb strings.Builder
+
+	writeGameTitle(&sb, width, "Rock Paper Scissors")
+	writePetFrame(&sb, frame, petHand)
+	sb.WriteString("\n")
+
+	if player != "" {
+		writeGameLine(&sb, fmt.Sprintf("You played %s, %s played %s", player, name, petHand))
+
+		switch outcome {
+		case 1:
+			writeGameLine(&sb, goodStyle.Render("You win the round! ✓"))
+		case -1:
+			writeGameLine(&sb, badStyle.Render(name+" wins the round! ✗"))
+		default:
+			writeGameLine(&sb, infoStyle.Render("It's a draw"))
+		}
+	} else {
+		writeGameLine(&sb, "Pick your hand!")
+		sb.WriteString("\n")
+	}
+	sb.WriteString("\n")
+
+	writeGameLine(&sb, infoStyle.Render(fmt.Sprintf("Round: %d/%d   Wins: %d   Draws: %d", round, rounds, wins, draws)))
+	sb.WriteString("\n")
+	writeGameLine(&sb, "R: Rock   P: Paper   S: Scissors   ESC: Exit")
+
+	return sb.String()
+}
@@ -0,0 +1,99 @@
+package views
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/kirkegaard/terminal-pet/pkg/pet"
+)
+
+// RenderPetPicker renders the pet selection screen shown on connect
+func RenderPetPicker(
+	width int,
+	pets []*pet.Pet,
+	cursor int,
+	maxPets int,
+	canAdopt bool,
+	inNameMode bool,
+	newName string,
+	message string,
+) string {
+	var sb strings.Builder
+
+	// Title
+	title := titleStyle.Render("🐾 Your Pets 🐾")
+	for _, line := range strings.Split(title, "\n") {
+		padding := (width - lipgloss.Width(line)) / 2
+		if padding > 0 {
+			sb.WriteString(strings.Repeat(" ", padding))
+		}
+		sb.WriteString(line)
+		sb.WriteString("\n")
+	}
+	sb.WriteString("\n")
+
+	if inNameMode {
+		prompt := infoStyle.Render("Name your new pet:")
+		input := highlightStyle.Render(newName + "▌")
+
+		sb.WriteString(strings.Repeat(" ", 5))
+		sb.WriteString(prompt + " " + input)
+		sb.WriteString("\n\n")
+		sb.WriteString(strings.Repeat(" ", 5))
+		sb.WriteString(lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#AAAAAA")).
+			Render("Enter to adopt, ESC to cancel"))
+
+		return sb.String()
+	}
+
+	count := infoStyle.Render(fmt.Sprintf("%d/%d pets", len(pets), maxPets))
+	sb.WriteString(strings.Repeat(" ", 5))
+	sb.WriteString(count)
+	sb.WriteString("\n\n")
+
+	options := make([]string, 0, len(pets)+3)
+	for _, p := range pets {
+		options = append(options, fmt.Sprintf("%-20s %-7s %-7s %s", p.Name, p.Species().Name, p.LifeStageAt(p.SimulatedAt), GetPetState(p)))
+	}
+
+	if canAdopt {
+		options = append(options, "Adopt a new pet")
+	} else {
+		options = append(options, "Adopt a new pet (limit reached)")
+	}
+	options = append(options, "Graveyard", "Quit")
+
+	for i, option := range options {
+		var line string
+
+		switch {
+		case i == len(pets) && !canAdopt:
+			line = "  " + disabledStyle.Render(option)
+		case i == cursor:
+			line = "> " + highlightStyle.Render(option)
+		default:
+			line = "  " + normalStyle.Render(" "+option+" ")
+		}
+
+		sb.WriteString(strings.Repeat(" ", 5))
+		sb.WriteString(line)
+		sb.WriteString("\n")
+	}
+
+	if message != "" {
+		sb.WriteString("\n")
+		sb.WriteString(strings.Repeat(" ", 5))
+		sb.WriteString(warningStyle.Render(message))
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString("\n")
+	sb.WriteString(strings.Repeat(" ", 5))
+	sb.WriteString(lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#AAAAAA")).
+		Render("↑/↓ to navigate, Enter to select, q to quit"))
+
+	return sb.String()
+}
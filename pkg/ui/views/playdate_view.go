@@ -0,0 +1,266 @@
+package views
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/kirkegaard/terminal-pet/pkg/playdate"
+)
+
+// playdateColumn is how wide each pet's half of the playdate scene is
+const playdateColumn = 30
+
+// renderPlaydateTitle centers the title of a playdate screen
+func renderPlaydateTitle(sb *strings.Builder, width int, text string) {
+	title := titleStyle.Render(text)
+	for _, line := range strings.Split(title, "\n") {
+		padding := (width - lipgloss.Width(line)) / 2
+		if padding > 0 {
+			sb.WriteString(strings.Repeat(" ", padding))
+		}
+		sb.WriteString(line)
+		sb.WriteString("\n")
+	}
+	sb.WriteString("\n")
+}
+
+// RenderPlaydatePicker renders the list of online players to invite to a
+// playdate
+func RenderPlaydatePicker(
+	width int,
+	names []string,
+	cursor int,
+	waiting string,
+	message string,
+) string {
+	var sb strings.Builder
+
+	renderPlaydateTitle(&sb, width, "🎈 Playdate 🎈")
+
+	if waiting != "" {
+		sb.WriteString(strings.Repeat(" ", 5))
+		sb.WriteString(infoStyle.Render("Waiting for " + waiting + " to answer…"))
+		sb.WriteString("\n\n")
+		sb.WriteString(strings.Repeat(" ", 5))
+		sb.WriteString(hintStyle.Render("ESC to give up"))
+		return sb.String()
+	}
+
+	if len(names) == 0 {
+		sb.WriteString(strings.Repeat(" ", 5))
+		sb.WriteString(epitaphStyle.Render("Nobody else is online right now."))
+		sb.WriteString("\n")
+	}
+
+	for i, name := range names {
+		sb.WriteString(strings.Repeat(" ", 5))
+		if i == cursor {
+			sb.WriteString("> " + highlightStyle.Render(name))
+		} else {
+			sb.WriteString("  " + normalStyle.Render(" "+name+" "))
+		}
+		sb.WriteString("\n")
+	}
+
+	if message != "" {
+		sb.WriteString("\n")
+		sb.WriteString(strings.Repeat(" ", 5))
+		sb.WriteString(warningStyle.Render(message))
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString("\n")
+	sb.WriteString(strings.Repeat(" ", 5))
+	sb.WriteString(hintStyle.Render("↑/↓ to choose, ENTER to invite, ESC to go back"))
+
+	return sb.String()
+}
+
+// RenderPlaydateInvite renders an invitation to a playdate
+func RenderPlaydateInvite(
+	width int,
+	from string,
+	petName string,
+) string {
+	var sb strings.Builder
+
+	renderPlaydateTitle(&sb, width, "🎈 Playdate 🎈")
+
+	sb.WriteString(strings.Repeat(" ", 5))
+	sb.WriteString(highlightStyle.Render(from) + " invites you to a playdate with " + highlightStyle.Render(petName) + "!")
+	sb.WriteString("\n\n")
+	sb.WriteString(strings.Repeat(" ", 5))
+	sb.WriteString(hintStyle.Render("Y or ENTER to accept, N or ESC to decline"))
+
+	return sb.String()
+}
+
+// RenderPlaydate renders both pets of a playdate side by side, with the game
+// being played or the games to choose from below them
+func RenderPlaydate(
+	width int,
+	s playdate.State,
+	side int,
+	frames [2]string,
+	cursor int,
+	message string,
+	now time.Time,
+) string {
+	var sb strings.Builder
+
+	renderPlaydateTitle(&sb, width, "🎈 Playdate 🎈")
+
+	// Both pets side by side in their colors, the host on the left
+	left := strings.Split(petColor(s.Pets[playdate.Host]).Render(frames[playdate.Host]), "\n")
+	right := strings.Split(petColor(s.Pets[playdate.Guest]).Render(frames[playdate.Guest]), "\n")
+	for i := 0; i < max(len(left), len(right)); i++ {
+		line := ""
+		if i < len(left) {
+			line = left[i]
+		}
+		sb.WriteString(strings.Repeat(" ", 5))
+		sb.WriteString(line)
+		sb.WriteString(strings.Repeat(" ", max(playdateColumn-lipgloss.Width(line), 1)))
+		if i < len(right) {
+			sb.WriteString(right[i])
+		}
+		sb.WriteString("\n")
+	}
+
+	var labels [2]string
+	for i, p := range s.Pets {
+		labels[i] = fmt.Sprintf("%s (%s)", p.Name, s.Players[i])
+		if i == side {
+			labels[i] = fmt.Sprintf("%s (you)", p.Name)
+		}
+	}
+	sb.WriteString(strings.Repeat(" ", 5))
+	sb.WriteString(highlightStyle.Render(labels[playdate.Host]))
+	sb.WriteString(strings.Repeat(" ", max(playdateColumn-lipgloss.Width(highlightStyle.Render(labels[playdate.Host])), 1)))
+	sb.WriteString(highlightStyle.Render(labels[playdate.Guest]))
+	sb.WriteString("\n\n")
+
+	if s.Left >= 0 {
+		who := s.Players[s.Left] + " went home."
+		if s.Left == side {
+			who = "You went home."
+		}
+		sb.WriteString(strings.Repeat(" ", 5))
+		sb.WriteString(epitaphStyle.Render(who))
+		sb.WriteString("\n\n")
+		sb.WriteString(strings.Repeat(" ", 5))
+		sb.WriteString(hintStyle.Render("Press any key to go back"))
+		return sb.String()
+	}
+
+	if s.Playing {
+		sb.WriteString(strings.Repeat(" ", 5))
+		sb.WriteString(infoStyle.Render(s.Game.Name))
+		sb.WriteString("\n\n")
+		sb.WriteString(strings.Repeat(" ", 5))
+		if s.Game.Cooperative {
+			left := max(s.Deadline.Sub(now), 0).Round(time.Second)
+			sb.WriteString(strings.Repeat("▆", s.Blocks()))
+			sb.WriteString(fmt.Sprintf("  %d/%d blocks, %s left", s.Blocks(), s.Game.Goal, left))
+		} else {
+			sb.WriteString(renderRope(s.Rope, s.Game.Goal))
+		}
+		sb.WriteString("\n\n")
+		sb.WriteString(strings.Repeat(" ", 5))
+		sb.WriteString(hintStyle.Render("Mash SPACE!"))
+		return sb.String()
+	}
+
+	// How the last game went
+	if s.Game != nil {
+		result := ""
+		switch {
+		case s.Game.Cooperative && s.Success:
+			result = fmt.Sprintf("You stacked all %d blocks together!", s.Game.Goal)
+		case s.Game.Cooperative:
+			result = fmt.Sprintf("The tower fell short at %d blocks.", s.Blocks())
+		case s.Winner == side:
+			result = "You won the " + strings.ToLower(s.Game.Name) + "!"
+		default:
+			result = s.Players[s.Winner] + " won the " + strings.ToLower(s.Game.Name) + "."
+		}
+		sb.WriteString(strings.Repeat(" ", 5))
+		sb.WriteString(goodStyle.Render(result))
+		sb.WriteString(" " + infoStyle.Render(fmt.Sprintf("+%d happiness", s.Reward(side))))
+		sb.WriteString("\n\n")
+	}
+
+	for i, g := range playdate.Games() {
+		sb.WriteString(strings.Repeat(" ", 5))
+		if i == cursor {
+			sb.WriteString("> " + highlightStyle.Render(g.Name))
+		} else {
+			sb.WriteString("  " + normalStyle.Render(" "+g.Name+" "))
+		}
+		sb.WriteString("  " + epitaphStyle.Render(g.Description))
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString(strings.Repeat(" ", 5))
+	if cursor == len(playdate.Games()) {
+		sb.WriteString("> " + highlightStyle.Render("Have a baby"))
+	} else {
+		sb.WriteString("  " + normalStyle.Render(" Have a baby "))
+	}
+	sb.WriteString("  " + renderConsent(s, side, now))
+	sb.WriteString("\n")
+
+	if message != "" {
+		sb.WriteString("\n")
+		sb.WriteString(strings.Repeat(" ", 5))
+		sb.WriteString(goodStyle.Render(message))
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString("\n")
+	sb.WriteString(strings.Repeat(" ", 5))
+	sb.WriteString(hintStyle.Render("↑/↓ to choose, ENTER to play, ESC to go home"))
+
+	return sb.String()
+}
+
+// renderConsent describes whether the pets can have a baby and who agreed
+// to it
+func renderConsent(s playdate.State, side int, now time.Time) string {
+	other := s.Players[1-side]
+
+	if err := s.CanBreed(now); err != nil {
+		return epitaphStyle.Render(capitalize(err.Error()))
+	}
+
+	switch {
+	case s.Consent[side]:
+		return infoStyle.Render("Waiting for " + other + " to agree")
+	case s.Consent[1-side]:
+		return goodStyle.Render(other + " would like the pets to have a baby!")
+	case s.Full[side]:
+		return epitaphStyle.Render("You have no room for another pet, " + other + " has to ask")
+	default:
+		return epitaphStyle.Render("Both players have to agree")
+	}
+}
+
+// renderRope draws the rope of a tug of war, the knot moves towards the side
+// pulling harder
+func renderRope(rope int, goal int) string {
+	var sb strings.Builder
+
+	sb.WriteString("|")
+	for i := -goal; i <= goal; i++ {
+		if i == rope {
+			sb.WriteString("●")
+		} else {
+			sb.WriteString("═")
+		}
+	}
+	sb.WriteString("|")
+
+	return sb.String()
+}
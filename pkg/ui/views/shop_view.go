@@ -0,0 +1,75 @@
+package views
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/kirkegaard/terminal-pet/pkg/shop"
+)
+
+// RenderShop renders the shop with the player's coins and how many of each
+// item they own
+func RenderShop(
+	width int,
+	items []shop.Item,
+	owned map[string]int,
+	coins int,
+	cursor int,
+	message string,
+) string {
+	var sb strings.Builder
+
+	// Title
+	title := titleStyle.Render("🛒 Shop 🛒")
+	for _, line := range strings.Split(title, "\n") {
+		padding := (width - lipgloss.Width(line)) / 2
+		if padding > 0 {
+			sb.WriteString(strings.Repeat(" ", padding))
+		}
+		sb.WriteString(line)
+		sb.WriteString("\n")
+	}
+	sb.WriteString("\n")
+
+	sb.WriteString(strings.Repeat(" ", 5))
+	sb.WriteString(infoStyle.Render("Coins:") + fmt.Sprintf(" %d 🪙", coins))
+	sb.WriteString("\n\n")
+
+	for i, item := range items {
+		line := fmt.Sprintf("%s %-10s %4d coins   owned: %d", item.Emoji, item.Name, item.Price, owned[item.ID])
+
+		sb.WriteString(strings.Repeat(" ", 5))
+		switch {
+		case i == cursor:
+			sb.WriteString("> " + highlightStyle.Render(line))
+		case item.Price > coins:
+			sb.WriteString("  " + disabledStyle.Render(" "+line+" "))
+		default:
+			sb.WriteString("  " + normalStyle.Render(" "+line+" "))
+		}
+		sb.WriteString("\n")
+	}
+
+	if cursor >= 0 && cursor < len(items) {
+		sb.WriteString("\n")
+		sb.WriteString(strings.Repeat(" ", 5))
+		sb.WriteString(epitaphStyle.Render(items[cursor].Description))
+		sb.WriteString("\n")
+	}
+
+	if message != "" {
+		sb.WriteString("\n")
+		sb.WriteString(strings.Repeat(" ", 5))
+		sb.WriteString(infoStyle.Render(message))
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString("\n")
+	sb.WriteString(strings.Repeat(" ", 5))
+	sb.WriteString(lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#AAAAAA")).
+		Render("↑/↓ to choose, Enter to buy, ESC to go back"))
+
+	return sb.String()
+}
@@ -0,0 +1,84 @@
+package views
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/kirkegaard/terminal-pet/pkg/pet"
+)
+
+// RenderSpeciesPicker renders the species choice shown when adopting a pet
+func RenderSpeciesPicker(
+	width int,
+	name string,
+	species []*pet.Species,
+	cursor int,
+) string {
+	var sb strings.Builder
+
+	// Title
+	title := titleStyle.Render("🐾 Adopt a Pet 🐾")
+	for _, line := range strings.Split(title, "\n") {
+		padding := (width - lipgloss.Width(line)) / 2
+		if padding > 0 {
+			sb.WriteString(strings.Repeat(" ", padding))
+		}
+		sb.WriteString(line)
+		sb.WriteString("\n")
+	}
+	sb.WriteString("\n")
+
+	sb.WriteString(strings.Repeat(" ", 5))
+	sb.WriteString(infoStyle.Render(fmt.Sprintf("What kind of pet is %s?", name)))
+	sb.WriteString("\n\n")
+
+	for i, s := range species {
+		var line string
+		if i == cursor {
+			line = "> " + highlightStyle.Render(s.Name)
+		} else {
+			line = "  " + normalStyle.Render(" "+s.Name+" ")
+		}
+
+		sb.WriteString(strings.Repeat(" ", 5))
+		sb.WriteString(line)
+		sb.WriteString("\n")
+	}
+
+	if cursor >= 0 && cursor < len(species) {
+		selected := species[cursor]
+
+		// Preview what the pet looks like when it hatches
+		frame := selected.Characters[pet.FormBaby].Animations.Idle.Frames[0]
+		for _, line := range strings.Split(strings.Trim(frame, "\n"), "\n") {
+			sb.WriteString(strings.Repeat(" ", 9))
+			sb.WriteString(line)
+			sb.WriteString("\n")
+		}
+		sb.WriteString("\n")
+
+		details := []string{selected.Description}
+		if favorites := selected.FavoriteFoods(); len(favorites) > 0 {
+			details = append(details, "Loves: "+strings.Join(favorites, ", "))
+		}
+		if disliked := selected.DislikedFoods(); len(disliked) > 0 {
+			details = append(details, "Dislikes: "+strings.Join(disliked, ", "))
+		}
+		details = append(details, fmt.Sprintf("Lifespan: %d years", selected.Lifespan))
+
+		for _, detail := range details {
+			sb.WriteString(strings.Repeat(" ", 5))
+			sb.WriteString(detail)
+			sb.WriteString("\n")
+		}
+	}
+
+	sb.WriteString("\n")
+	sb.WriteString(strings.Repeat(" ", 5))
+	sb.WriteString(lipgloss.NewStyle().
+		Foreground(lipgloss.Color("#AAAAAA")).
+		Render("↑/↓ to choose, Enter to adopt, ESC to go back"))
+
+	return sb.String()
+}
@@ -0,0 +1,110 @@
+package views
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// sparks are the levels of a sparkline, lowest first
+var sparks = []rune("▁▂▃▄▅▆▇█")
+
+// StatChart is the history of one stat of a pet. Values are drawn between
+// Low and High, NaN values are gaps where nothing was recorded.
+type StatChart struct {
+	Label   string
+	Values  []float64
+	Low     float64
+	High    float64
+	Current int
+}
+
+// Sparkline draws values as a line of block characters between low and
+// high, leaving gaps for NaN values
+func Sparkline(values []float64, low, high float64) string {
+	var sb strings.Builder
+
+	for _, v := range values {
+		if math.IsNaN(v) {
+			sb.WriteRune(' ')
+			continue
+		}
+
+		level := len(sparks) / 2
+		if high > low {
+			level = int((v - low) / (high - low) * float64(len(sparks)-1))
+		}
+		sb.WriteRune(sparks[min(max(level, 0), len(sparks)-1)])
+	}
+
+	return sb.String()
+}
+
+// RenderStatHistory renders the stats screen of a pet, showing the history
+// of its stats over the selected range
+func RenderStatHistory(
+	width int,
+	name string,
+	ranges []string,
+	selected int,
+	charts []StatChart,
+) string {
+	var sb strings.Builder
+
+	// Title
+	title := titleStyle.Render("📈 " + name + "'s Stats 📈")
+	for _, line := range strings.Split(title, "\n") {
+		padding := (width - lipgloss.Width(line)) / 2
+		if padding > 0 {
+			sb.WriteString(strings.Repeat(" ", padding))
+		}
+		sb.WriteString(line)
+		sb.WriteString("\n")
+	}
+	sb.WriteString("\n")
+
+	// Ranges
+	sb.WriteString(strings.Repeat(" ", 5))
+	for i, r := range ranges {
+		if i > 0 {
+			sb.WriteString(normalStyle.Render(" | "))
+		}
+		if i == selected {
+			sb.WriteString(highlightStyle.Render("[" + r + "]"))
+		} else {
+			sb.WriteString(normalStyle.Render(" " + r + " "))
+		}
+	}
+	sb.WriteString("\n\n")
+
+	for _, c := range charts {
+		recorded := make([]float64, 0, len(c.Values))
+		for _, v := range c.Values {
+			if !math.IsNaN(v) {
+				recorded = append(recorded, v)
+			}
+		}
+
+		sb.WriteString(strings.Repeat(" ", 5))
+		sb.WriteString(fmt.Sprintf("%-10s", c.Label))
+		sb.WriteString(highlightStyle.Render(Sparkline(c.Values, c.Low, c.High)))
+		sb.WriteString("\n")
+
+		sb.WriteString(strings.Repeat(" ", 15))
+		if len(recorded) == 0 {
+			sb.WriteString(epitaphStyle.Render("Nothing recorded yet."))
+		} else {
+			sb.WriteString(infoStyle.Render(fmt.Sprintf("now %d, min %.0f, max %.0f",
+				c.Current, slices.Min(recorded), slices.Max(recorded))))
+		}
+		sb.WriteString("\n\n")
+	}
+
+	sb.WriteString(strings.Repeat(" ", 5))
+	sb.WriteString(hintStyle.Render("←/→ to change range, ESC to go back"))
+
+	return sb.String()
+}
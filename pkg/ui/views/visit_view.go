@@ -0,0 +1,146 @@
+package views
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/kirkegaard/terminal-pet/pkg/pet"
+)
+
+// RenderVisitPrompt renders the screen asking who to visit, along with the
+// player's own privacy setting
+func RenderVisitPrompt(
+	width int,
+	name string,
+	private bool,
+	message string,
+) string {
+	var sb strings.Builder
+
+	// Title
+	title := titleStyle.Render("🏡 Visit a friend 🏡")
+	for _, line := range strings.Split(title, "\n") {
+		padding := (width - lipgloss.Width(line)) / 2
+		if padding > 0 {
+			sb.WriteString(strings.Repeat(" ", padding))
+		}
+		sb.WriteString(line)
+		sb.WriteString("\n")
+	}
+	sb.WriteString("\n")
+
+	sb.WriteString(strings.Repeat(" ", 5))
+	sb.WriteString(infoStyle.Render("Player:") + " " + highlightStyle.Render(name+"▌"))
+	sb.WriteString("\n")
+
+	if message != "" {
+		sb.WriteString("\n")
+		sb.WriteString(strings.Repeat(" ", 5))
+		sb.WriteString(warningStyle.Render(message))
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString("\n")
+	sb.WriteString(strings.Repeat(" ", 5))
+	if private {
+		sb.WriteString(epitaphStyle.Render("Your pets are private, nobody else can visit them."))
+	} else {
+		sb.WriteString(epitaphStyle.Render("Your pets are public, anyone can visit them."))
+	}
+	sb.WriteString("\n\n")
+
+	sb.WriteString(strings.Repeat(" ", 5))
+	sb.WriteString(hintStyle.Render("ENTER to visit, TAB to change your privacy, ESC to go back"))
+
+	return sb.String()
+}
+
+// RenderVisit renders one of the pets of the visited player. The pet is nil
+// when they have no living pets.
+func RenderVisit(
+	width int,
+	owner string,
+	p *pet.Pet,
+	frame string,
+	index int,
+	count int,
+) string {
+	var sb strings.Builder
+
+	// Title
+	title := titleStyle.Render("🏡 " + owner + "'s home 🏡")
+	for _, line := range strings.Split(title, "\n") {
+		padding := (width - lipgloss.Width(line)) / 2
+		if padding > 0 {
+			sb.WriteString(strings.Repeat(" ", padding))
+		}
+		sb.WriteString(line)
+		sb.WriteString("\n")
+	}
+	sb.WriteString("\n")
+
+	if p == nil {
+		sb.WriteString(strings.Repeat(" ", 5))
+		sb.WriteString(epitaphStyle.Render(owner + " has no living pets."))
+		sb.WriteString("\n\n")
+		sb.WriteString(strings.Repeat(" ", 5))
+		sb.WriteString(hintStyle.Render("ESC to visit someone else"))
+		return sb.String()
+	}
+
+	sb.WriteString(strings.Repeat(" ", 5))
+	sb.WriteString(highlightStyle.Render(p.Name))
+	if count > 1 {
+		sb.WriteString(" " + infoStyle.Render(fmt.Sprintf("%d of %d", index+1, count)))
+	}
+	sb.WriteString("\n\n")
+
+	for i, line := range strings.Split(petColor(p).Render(frame), "\n") {
+		sb.WriteString(strings.Repeat(" ", 5))
+		sb.WriteString(line)
+
+		// Same indicators as on the owner's screen
+		if i == 1 {
+			indicators := ""
+			if p.IsMisbehaving() {
+				indicators += "❗"
+			}
+			if p.IsSick {
+				indicators += "☠️"
+			}
+			if p.HasPooped {
+				indicators += "💩"
+			}
+			if indicators != "" {
+				sb.WriteString(" " + indicators)
+			}
+		}
+		sb.WriteString("\n")
+	}
+	sb.WriteString("\n")
+
+	sb.WriteString(infoStyle.Render("Species:") + " " + p.Species().Name + "\n")
+	sb.WriteString(infoStyle.Render("State:") + " " + GetPetState(p) + "\n")
+	sb.WriteString(infoStyle.Render("Age:") + fmt.Sprintf(" %d days (%s, %s)", p.AgeAt(p.SimulatedAt), p.Character().Name, p.LifeStageAt(p.SimulatedAt)) + "\n")
+	sb.WriteString(infoStyle.Render("Health:") + " " + getHearts(p.Health) + "\n")
+	sb.WriteString(infoStyle.Render("Hunger:") + " " + getHearts(100-p.Hunger) + "\n")
+	sb.WriteString(infoStyle.Render("Happiness:") + " " + getHearts(p.Happiness) + "\n")
+	sb.WriteString(infoStyle.Render("Weight:") + fmt.Sprintf(" %d kg", p.Weight) + "\n")
+
+	if !p.LightsOn {
+		sb.WriteString("\n")
+		sb.WriteString(epitaphStyle.Render(p.Name + " is sleeping, so be quiet!"))
+		sb.WriteString("\n")
+	}
+
+	sb.WriteString("\n")
+	sb.WriteString(strings.Repeat(" ", 5))
+	if count > 1 {
+		sb.WriteString(hintStyle.Render("←/→ for the other pets, ESC to visit someone else"))
+	} else {
+		sb.WriteString(hintStyle.Render("ESC to visit someone else"))
+	}
+
+	return sb.String()
+}
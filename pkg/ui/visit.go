@@ -0,0 +1,208 @@
+package ui
+
+import (
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kirkegaard/terminal-pet/pkg/pet"
+	"github.com/kirkegaard/terminal-pet/pkg/ui/keymap"
+	"github.com/kirkegaard/terminal-pet/pkg/ui/views"
+)
+
+// VisitRefreshInterval is how often the visited pets are reloaded
+const VisitRefreshInterval = 15 * time.Second
+
+// maxPlayerNameLength is the longest player name that can be looked up
+const maxPlayerNameLength = 32
+
+// ShowVisitMsg is sent when the player wants to visit another player
+type ShowVisitMsg struct{}
+
+// CloseVisitMsg is sent when the player stops visiting
+type CloseVisitMsg struct{}
+
+// VisitPlayerMsg is sent when the player looks up someone to visit, and
+// again every VisitRefreshInterval while visiting them
+type VisitPlayerMsg struct {
+	Name string
+}
+
+// SetPrivacyMsg is sent when the player changes whether others may visit
+// their pets
+type SetPrivacyMsg struct {
+	Private bool
+}
+
+// visitFrameMsg advances the animation of the visited pet
+type visitFrameMsg time.Time
+
+// visitRefreshMsg asks for the visited pets to be reloaded
+type visitRefreshMsg struct{}
+
+// Visit looks up another player by name and shows their living pets. The
+// pets can be looked at but not touched.
+type Visit struct {
+	// Looking up a player
+	name    string
+	private bool
+	message string
+
+	// Visiting, owner is empty while looking someone up
+	owner  string
+	pets   []*pet.Pet
+	cursor int
+	frame  int
+
+	keys   keymap.KeyMap
+	width  int
+	height int
+}
+
+// NewVisit creates a visit screen asking who to visit. private is whether
+// the player keeps their own pets private.
+func NewVisit(private bool, width, height int) *Visit {
+	return &Visit{
+		private: private,
+		keys:    keymap.Keys,
+		width:   width,
+		height:  height,
+	}
+}
+
+// Show starts visiting the pets of the owner. Reloading the same owner keeps
+// the selected pet.
+func (m *Visit) Show(owner string, pets []*pet.Pet) {
+	if owner != m.owner {
+		m.cursor = 0
+	}
+
+	m.owner = owner
+	m.pets = pets
+	m.cursor = min(m.cursor, max(len(pets)-1, 0))
+	m.message = ""
+}
+
+// ShowError goes back to looking someone up, explaining why the visit
+// didn't work out
+func (m *Visit) ShowError(message string) {
+	m.owner = ""
+	m.pets = nil
+	m.message = message
+}
+
+// SetPrivate updates the player's own privacy setting
+func (m *Visit) SetPrivate(private bool) {
+	m.private = private
+}
+
+func (m *Visit) Init() tea.Cmd {
+	return tea.Batch(visitFrame(), visitRefresh())
+}
+
+// visitFrame waits for the next animation frame
+func visitFrame() tea.Cmd {
+	return tea.Tick(AnimationTickRate, func(t time.Time) tea.Msg {
+		return visitFrameMsg(t)
+	})
+}
+
+// visitRefresh waits until the visited pets should be reloaded
+func visitRefresh() tea.Cmd {
+	return tea.Tick(VisitRefreshInterval, func(time.Time) tea.Msg {
+		return visitRefreshMsg{}
+	})
+}
+
+func (m *Visit) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case visitFrameMsg:
+		m.frame++
+		return m, visitFrame()
+
+	case visitRefreshMsg:
+		if m.owner == "" {
+			return m, visitRefresh()
+		}
+		owner := m.owner
+		return m, tea.Batch(visitRefresh(), func() tea.Msg { return VisitPlayerMsg{Name: owner} })
+
+	case tea.KeyMsg:
+		if m.owner == "" {
+			return m.updatePrompt(msg)
+		}
+
+		switch {
+		case msg.String() == "esc", key.Matches(msg, m.keys.Quit):
+			m.owner = ""
+			m.pets = nil
+
+		case key.Matches(msg, m.keys.Left), key.Matches(msg, m.keys.Up):
+			if len(m.pets) > 0 {
+				m.cursor = (m.cursor + len(m.pets) - 1) % len(m.pets)
+			}
+
+		case key.Matches(msg, m.keys.Right), key.Matches(msg, m.keys.Down):
+			if len(m.pets) > 0 {
+				m.cursor = (m.cursor + 1) % len(m.pets)
+			}
+		}
+
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+	}
+
+	return m, nil
+}
+
+// updatePrompt handles typing the name of the player to visit
+func (m *Visit) updatePrompt(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	switch msg.String() {
+	case "esc":
+		return m, func() tea.Msg { return CloseVisitMsg{} }
+	case "enter":
+		name := strings.TrimSpace(m.name)
+		if name == "" {
+			return m, nil
+		}
+		return m, func() tea.Msg { return VisitPlayerMsg{Name: name} }
+	case "tab":
+		private := !m.private
+		return m, func() tea.Msg { return SetPrivacyMsg{Private: private} }
+	case "backspace":
+		if len(m.name) > 0 {
+			runes := []rune(m.name)
+			m.name = string(runes[:len(runes)-1])
+		}
+	default:
+		if len(msg.Runes) == 1 && len([]rune(m.name)) < maxPlayerNameLength {
+			if unicode.IsPrint(msg.Runes[0]) {
+				m.name += string(msg.Runes)
+			}
+		}
+	}
+
+	return m, nil
+}
+
+func (m *Visit) View() string {
+	if m.owner == "" {
+		return views.RenderVisitPrompt(m.width, m.name, m.private, m.message)
+	}
+
+	if len(m.pets) == 0 {
+		return views.RenderVisit(m.width, m.owner, nil, "", 0, 0)
+	}
+
+	p := m.pets[m.cursor]
+	animation := p.Animations().ForState(p.GetState())
+	frame := ""
+	if len(animation.Frames) > 0 {
+		frame = animation.Frames[m.frame%len(animation.Frames)]
+	}
+
+	return views.RenderVisit(m.width, m.owner, p, frame, m.cursor, len(m.pets))
+}
@@ -0,0 +1,137 @@
+package world
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/charmbracelet/log"
+	"github.com/kirkegaard/terminal-pet/pkg/db/repo"
+	"github.com/kirkegaard/terminal-pet/pkg/events"
+	"github.com/kirkegaard/terminal-pet/pkg/pet"
+)
+
+// Ticker periodically advances every living pet in the database, so pets
+// keep aging while their owners are away. Pets being played in a live
+// session are left to the session. The events of the simulation are
+// published on the bus once the pet is saved.
+type Ticker struct {
+	petRepo  repo.PetStore
+	events   *events.Bus
+	sim      *pet.Simulator
+	sessions Sessions
+	interval time.Duration
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// Sessions tells which pets are being played in a live session, which
+// simulates them itself. Every connection of a player is a session of its
+// own.
+type Sessions interface {
+	Playing(petID int) bool
+}
+
+// NewTicker creates a world ticker that runs every interval. sessions may be
+// nil if there are none.
+func NewTicker(petRepo repo.PetStore, bus *events.Bus, sim *pet.Simulator, sessions Sessions, interval time.Duration) *Ticker {
+	return &Ticker{
+		petRepo:  petRepo,
+		events:   bus,
+		sim:      sim,
+		sessions: sessions,
+		interval: interval,
+	}
+}
+
+// Start runs the ticker in the background until Stop is called or the
+// context is cancelled
+func (t *Ticker) Start(ctx context.Context) {
+	ctx, t.cancel = context.WithCancel(ctx)
+
+	t.wg.Add(1)
+	go func() {
+		defer t.wg.Done()
+
+		ticker := time.NewTicker(t.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ticker.C:
+				if err := t.Tick(ctx); err != nil {
+					log.Error("World tick failed", "error", err)
+				}
+			case <-ctx.Done():
+				log.Debug("World ticker stopped")
+				return
+			}
+		}
+	}()
+
+	log.Info("World ticker started", "interval", t.interval)
+}
+
+// Stop stops the ticker and waits for a running tick to finish
+func (t *Ticker) Stop() {
+	if t.cancel != nil {
+		t.cancel()
+	}
+
+	t.wg.Wait()
+}
+
+// Tick advances all living pets once and persists them
+func (t *Ticker) Tick(ctx context.Context) error {
+	pets, err := t.petRepo.ListAlive(ctx)
+	if err != nil {
+		return err
+	}
+
+	updated := 0
+	for _, p := range pets {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		// The session publishes the pet's events and saves it
+		if t.sessions != nil && t.sessions.Playing(p.ID) {
+			continue
+		}
+
+		if t.sim.Update(p) == 0 {
+			continue
+		}
+
+		// A session may have picked the pet while it was simulated
+		if t.sessions != nil && t.sessions.Playing(p.ID) {
+			continue
+		}
+
+		ok, err := t.petRepo.UpdateSimulated(ctx, p)
+		if err != nil {
+			log.Error("Could not save simulated pet", "id", p.ID, "error", err)
+			continue
+		}
+
+		// Someone else saved the pet since we loaded it, it will be picked
+		// up again on the next tick
+		if !ok {
+			log.Debug("Pet changed during world tick, skipping", "id", p.ID)
+			continue
+		}
+
+		if p.IsDead() {
+			log.Info("Pet died while simulated", "id", p.ID, "name", p.Name)
+		}
+
+		t.events.Publish(ctx, p.TakeEvents()...)
+
+		updated++
+	}
+
+	log.Debug("World tick finished", "pets", len(pets), "updated", updated)
+
+	return nil
+}